@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvToken is the environment variable that overrides the stored token
+const EnvToken = "GLOBALPING_TOKEN"
+
+// Path of the token file, overridable for tests
+var Path = defaultPath()
+
+func defaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".globalping", "token")
+	}
+	return filepath.Join(home, ".globalping", "token")
+}
+
+// Source describes where the active token was loaded from
+type Source string
+
+const (
+	SourceNone Source = ""
+	SourceEnv  Source = "environment"
+	SourceFile Source = "file"
+)
+
+// Token returns the API token to use, the environment variable takes precedence over the stored token
+func Token() (string, Source, error) {
+	if t := strings.TrimSpace(os.Getenv(EnvToken)); t != "" {
+		return t, SourceEnv, nil
+	}
+
+	b, err := os.ReadFile(Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", SourceNone, nil
+		}
+		return "", SourceNone, errors.New("err: failed to read token file")
+	}
+
+	t := strings.TrimSpace(string(b))
+	if t == "" {
+		return "", SourceNone, nil
+	}
+	return t, SourceFile, nil
+}
+
+// Save stores the token in a file only readable by the current user
+func Save(token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return errors.New("token is empty")
+	}
+
+	err := os.MkdirAll(filepath.Dir(Path), 0o700)
+	if err != nil {
+		return errors.New("err: failed to create token directory")
+	}
+
+	err = os.WriteFile(Path, []byte(token+"\n"), 0o600)
+	if err != nil {
+		return errors.New("err: failed to write token file")
+	}
+	return nil
+}
+
+// Remove deletes the stored token
+func Remove() error {
+	err := os.Remove(Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.New("err: failed to remove token file")
+	}
+	return nil
+}
+
+// Mask hides most of the token so it can be safely printed
+func Mask(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:4] + strings.Repeat("*", len(token)-8) + token[len(token)-4:]
+}
@@ -0,0 +1,49 @@
+package auth_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/auth"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken(t *testing.T) {
+	auth.Path = filepath.Join(t.TempDir(), "token")
+	t.Setenv(auth.EnvToken, "")
+
+	token, source, err := auth.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "", token)
+	assert.Equal(t, auth.SourceNone, source)
+
+	assert.Error(t, auth.Save("  "))
+	assert.NoError(t, auth.Save(" abcdefghijkl \n"))
+
+	info, err := os.Stat(auth.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	token, source, err = auth.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "abcdefghijkl", token)
+	assert.Equal(t, auth.SourceFile, source)
+
+	t.Setenv(auth.EnvToken, "env-token")
+	token, source, _ = auth.Token()
+	assert.Equal(t, "env-token", token)
+	assert.Equal(t, auth.SourceEnv, source)
+
+	t.Setenv(auth.EnvToken, "")
+	assert.NoError(t, auth.Remove())
+	assert.NoError(t, auth.Remove())
+	token, _, _ = auth.Token()
+	assert.Equal(t, "", token)
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "abcd****ijkl", auth.Mask("abcdefghijkl"))
+	assert.Equal(t, "****", auth.Mask("abcd"))
+}
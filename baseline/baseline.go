@@ -0,0 +1,82 @@
+// Package baseline stores named measurements on disk, so later runs of the same measurement can be compared against
+// them to detect regressions.
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Dir of the baselines, one file per name, overridable for tests
+var Dir = defaultDir()
+
+func defaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".globalping", "baselines")
+	}
+	return filepath.Join(home, ".globalping", "baselines")
+}
+
+// Names are used as file names
+var validName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+func path(name string) (string, error) {
+	if !validName.MatchString(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("err: invalid baseline name %q, use letters, digits, dots, dashes and underscores", name)
+	}
+	return filepath.Join(Dir, name+".json"), nil
+}
+
+// Save stores a finished measurement as the baseline name, replacing the previous one
+func Save(name string, data model.GetMeasurement) error {
+	p, err := path(name)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return errors.New("err: failed to encode the baseline")
+	}
+
+	err = os.MkdirAll(Dir, 0o700)
+	if err != nil {
+		return errors.New("err: failed to create the baselines directory")
+	}
+
+	err = os.WriteFile(p, b, 0o600)
+	if err != nil {
+		return errors.New("err: failed to write the baseline file")
+	}
+	return nil
+}
+
+// Load returns the measurement saved as the baseline name
+func Load(name string) (model.GetMeasurement, error) {
+	p, err := path(name)
+	if err != nil {
+		return model.GetMeasurement{}, err
+	}
+
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return model.GetMeasurement{}, fmt.Errorf("err: unknown baseline %q - save it with --baseline save:%s", name, name)
+		}
+		return model.GetMeasurement{}, errors.New("err: failed to read the baseline file")
+	}
+
+	var data model.GetMeasurement
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return model.GetMeasurement{}, fmt.Errorf("err: invalid baseline file %s", p)
+	}
+	return data, nil
+}
@@ -0,0 +1,53 @@
+package baseline_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/baseline"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func useTempDir(t *testing.T) {
+	dir := baseline.Dir
+	baseline.Dir = filepath.Join(t.TempDir(), "baselines")
+	t.Cleanup(func() { baseline.Dir = dir })
+}
+
+func TestSaveLoad(t *testing.T) {
+	useTempDir(t)
+
+	data := model.GetMeasurement{ID: "abcd", Type: "ping", Target: "jsdelivr.com", Status: "finished"}
+	assert.NoError(t, baseline.Save("prod", data))
+
+	loaded, err := baseline.Load("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, data.ID, loaded.ID)
+	assert.Equal(t, data.Target, loaded.Target)
+
+	// Saving again replaces the baseline
+	data.ID = "efgh"
+	assert.NoError(t, baseline.Save("prod", data))
+	loaded, err = baseline.Load("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, "efgh", loaded.ID)
+}
+
+func TestLoadErrors(t *testing.T) {
+	useTempDir(t)
+
+	_, err := baseline.Load("missing")
+	assert.EqualError(t, err, `err: unknown baseline "missing" - save it with --baseline save:missing`)
+
+	_, err = baseline.Load("../config")
+	assert.EqualError(t, err, `err: invalid baseline name "../config", use letters, digits, dots, dashes and underscores`)
+	assert.Error(t, baseline.Save("a/b", model.GetMeasurement{}))
+
+	assert.NoError(t, os.MkdirAll(baseline.Dir, 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseline.Dir, "broken.json"), []byte("{"), 0o600))
+	_, err = baseline.Load("broken")
+	assert.ErrorContains(t, err, "err: invalid baseline file")
+}
@@ -0,0 +1,148 @@
+// Package cache stores the raw JSON of finished measurements on disk, so showing or formatting them again does not
+// call the API. Results of a finished measurement never change, the TTL bounds the size of the cache: expired
+// measurements are removed when they are read and by a sweep of the whole cache, run by Put at most every hour.
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Dir of the cached measurements, one file per measurement, overridable for tests
+var Dir = defaultDir()
+
+// TTL is the duration a measurement is kept in the cache
+var TTL = 7 * 24 * time.Hour
+
+// Minimum duration between two sweeps of the expired measurements
+const pruneEvery = time.Hour
+
+// File whose modification time is the last sweep
+const pruneStamp = ".pruned"
+
+func defaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".globalping", "cache")
+	}
+	return filepath.Join(home, ".globalping", "cache")
+}
+
+// IDs are used as file names, anything else is never cached
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func path(id string) string {
+	return filepath.Join(Dir, id+".json")
+}
+
+// Get returns the cached measurement, expired ones are removed
+func Get(id string) ([]byte, bool) {
+	if !validID.MatchString(id) {
+		return nil, false
+	}
+
+	info, err := os.Stat(path(id))
+	if err != nil {
+		return nil, false
+	}
+	if time.Since(info.ModTime()) > TTL {
+		os.Remove(path(id))
+		return nil, false
+	}
+
+	b, err := os.ReadFile(path(id))
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// Put stores a finished measurement. The file is written under a temporary name and renamed, so concurrent readers,
+// e.g. the requests of serve, never read a partial file
+func Put(id string, raw []byte) error {
+	if !validID.MatchString(id) {
+		return errors.New("err: invalid measurement id")
+	}
+
+	err := os.MkdirAll(Dir, 0o700)
+	if err != nil {
+		return errors.New("err: failed to create cache directory")
+	}
+
+	f, err := os.CreateTemp(Dir, id+".*.tmp")
+	if err != nil {
+		return errors.New("err: failed to write cache file")
+	}
+	_, err = f.Write(raw)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path(id))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return errors.New("err: failed to write cache file")
+	}
+
+	prune()
+	return nil
+}
+
+// Remove the expired measurements, and the temporary files left by interrupted writes, unless the cache was swept
+// less than pruneEvery ago. Failures are ignored, the next sweep tries again
+func prune() {
+	stamp := filepath.Join(Dir, pruneStamp)
+	if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < pruneEvery {
+		return
+	}
+	now := time.Now()
+	if err := os.WriteFile(stamp, nil, 0o600); err == nil {
+		_ = os.Chtimes(stamp, now, now)
+	}
+
+	entries, err := os.ReadDir(Dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !(strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".tmp")) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if now.Sub(info.ModTime()) > TTL {
+			os.Remove(filepath.Join(Dir, name))
+		}
+	}
+}
+
+// Clear removes every cached measurement and returns how many were removed
+func Clear() (int, error) {
+	entries, err := os.ReadDir(Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, errors.New("err: failed to read cache directory")
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(Dir, e.Name())); err != nil {
+			return removed, errors.New("err: failed to remove cache file")
+		}
+		removed++
+	}
+	return removed, nil
+}
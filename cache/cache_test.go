@@ -0,0 +1,94 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func useTempDir(t *testing.T) {
+	dir, ttl := cache.Dir, cache.TTL
+	cache.Dir = filepath.Join(t.TempDir(), "cache")
+	t.Cleanup(func() { cache.Dir, cache.TTL = dir, ttl })
+}
+
+func TestPutGet(t *testing.T) {
+	useTempDir(t)
+
+	_, ok := cache.Get("abcd")
+	assert.False(t, ok)
+
+	assert.NoError(t, cache.Put("abcd", []byte(`{"id":"abcd"}`)))
+	raw, ok := cache.Get("abcd")
+	assert.True(t, ok)
+	assert.Equal(t, `{"id":"abcd"}`, string(raw))
+
+	assert.EqualError(t, cache.Put("../abcd", nil), "err: invalid measurement id")
+	_, ok = cache.Get("../abcd")
+	assert.False(t, ok)
+}
+
+func TestExpired(t *testing.T) {
+	useTempDir(t)
+	cache.TTL = time.Hour
+
+	assert.NoError(t, cache.Put("abcd", []byte(`{"id":"abcd"}`)))
+	old := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(filepath.Join(cache.Dir, "abcd.json"), old, old))
+
+	_, ok := cache.Get("abcd")
+	assert.False(t, ok)
+	_, err := os.Stat(filepath.Join(cache.Dir, "abcd.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestClear(t *testing.T) {
+	useTempDir(t)
+
+	n, err := cache.Clear()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	assert.NoError(t, cache.Put("a", []byte(`{}`)))
+	assert.NoError(t, cache.Put("b", []byte(`{}`)))
+	n, err = cache.Clear()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+}
+
+func TestPrune(t *testing.T) {
+	useTempDir(t)
+	cache.TTL = time.Hour
+
+	assert.NoError(t, cache.Put("old", []byte(`{}`)))
+	old := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(filepath.Join(cache.Dir, "old.json"), old, old))
+	assert.NoError(t, os.WriteFile(filepath.Join(cache.Dir, "partial.123.tmp"), nil, 0o600))
+	assert.NoError(t, os.Chtimes(filepath.Join(cache.Dir, "partial.123.tmp"), old, old))
+
+	// The cache was swept by the first Put, the next sweep waits
+	assert.NoError(t, cache.Put("new", []byte(`{}`)))
+	_, err := os.Stat(filepath.Join(cache.Dir, "old.json"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.Chtimes(filepath.Join(cache.Dir, ".pruned"), old, old))
+	assert.NoError(t, cache.Put("new", []byte(`{}`)))
+	_, err = os.Stat(filepath.Join(cache.Dir, "old.json"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(cache.Dir, "partial.123.tmp"))
+	assert.True(t, os.IsNotExist(err))
+	_, ok := cache.Get("new")
+	assert.True(t, ok)
+
+	entries, err := os.ReadDir(cache.Dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
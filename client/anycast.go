@@ -0,0 +1,124 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// AnycastTypes are the measurement types the anycast check can run
+var AnycastTypes = []string{"ping", "dns"}
+
+// Addresses a probe reached or resolved the target to: the address pinged by the probe, or the A and AAAA answers
+// of a dns result
+func anycastAddresses(cmd string, result model.MeasurementResponse) []string {
+	if cmd != "dns" {
+		if result.Result.ResolvedAddress == "" {
+			return nil
+		}
+		return []string{result.Result.ResolvedAddress}
+	}
+
+	var addresses []string
+	for _, a := range FilterAnswers(result.Result.Answers, []string{"A", "AAAA"}) {
+		addresses = append(addresses, a.Value)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// FormatAnycast renders the addresses and the latency of every probe, the distinct addresses seen in every region and
+// flags the probes with a latency above maxLatency: an anycast address is expected to be served by a nearby site, a
+// high latency means the probe is routed to a distant one. Returns the number of flagged probes.
+func FormatAnycast(cmd string, data model.GetMeasurement, maxLatency time.Duration) (string, int) {
+	max := float64(maxLatency) / float64(time.Millisecond)
+
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROBE\tREGION\tADDRESS\tLATENCY\tNOTE")
+
+	var regions []string
+	probes := map[string]int{}
+	regionAddresses := map[string]map[string]bool{}
+	regionLatencies := map[string][]float64{}
+
+	var addresses []string
+	addressProbes := map[string]int{}
+	var distant []string
+
+	for _, result := range data.Results {
+		region := result.Probe.Region
+		if region == "" {
+			region = result.Probe.Continent
+		}
+		if _, ok := probes[region]; !ok {
+			regions = append(regions, region)
+			regionAddresses[region] = map[string]bool{}
+		}
+		probes[region]++
+
+		probeAddresses := anycastAddresses(cmd, result)
+		for _, a := range probeAddresses {
+			regionAddresses[region][a] = true
+			if addressProbes[a] == 0 {
+				addresses = append(addresses, a)
+			}
+			addressProbes[a]++
+		}
+		address := strings.Join(probeAddresses, ", ")
+		if address == "" {
+			address = "-"
+		}
+
+		latency, note := "-", ""
+		if result.Result.Status != "" && result.Result.Status != "finished" {
+			note = "probe " + result.Result.Status
+		}
+		if v, ok := KeyMetric(cmd, result); ok {
+			latency = fmt.Sprintf("%.1f ms", v)
+			regionLatencies[region] = append(regionLatencies[region], v)
+			if max > 0 && v > max {
+				note = "distant site"
+				distant = append(distant, probeLabel(result.Probe))
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", probeLabel(result.Probe), region, address, latency, note)
+	}
+	w.Flush()
+
+	output.WriteString("\n")
+	w = tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REGION\tPROBES\tADDRESSES\tMEDIAN LATENCY")
+	for _, region := range regions {
+		median := "-"
+		if values := regionLatencies[region]; len(values) > 0 {
+			sort.Float64s(values)
+			median = fmt.Sprintf("%.1f ms", Percentile(values, 50))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", region, probes[region], len(regionAddresses[region]), median)
+	}
+	w.Flush()
+
+	seen := make([]string, len(addresses))
+	for i, a := range addresses {
+		seen[i] = fmt.Sprintf("%s (%s)", a, plural(addressProbes[a], "probe"))
+	}
+	distinct := fmt.Sprintf("%d distinct addresses", len(addresses))
+	if len(addresses) == 1 {
+		distinct = "1 distinct address"
+	}
+	output.WriteString(fmt.Sprintf("\n%s: %s\n", distinct, strings.Join(seen, ", ")))
+	switch {
+	case max <= 0:
+	case len(distant) == 0:
+		output.WriteString(fmt.Sprintf("Every probe is within %s of a site", maxLatency))
+	default:
+		output.WriteString(fmt.Sprintf("%s above %s, likely routed to a distant site: %s", plural(len(distant), "probe"), maxLatency, strings.Join(distant, "; ")))
+	}
+
+	return strings.TrimRight(output.String(), "\n"), len(distant)
+}
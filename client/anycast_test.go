@@ -0,0 +1,63 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAnycast(t *testing.T) {
+	probe := func(city, country, region, address string, avg float64) model.MeasurementResponse {
+		return model.MeasurementResponse{
+			Probe:  model.ProbeData{City: city, Country: country, Region: region, ASN: 1},
+			Result: model.ResultData{Status: "finished", ResolvedAddress: address, Stats: &model.PingStats{Avg: ms(avg)}},
+		}
+	}
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		probe("Berlin", "DE", "Western Europe", "1.1.1.1", 4.2),
+		probe("Paris", "FR", "Western Europe", "1.1.1.1", 6),
+		probe("Sydney", "AU", "Australia and New Zealand", "1.1.1.1", 180.5),
+		probe("Tokyo", "JP", "Eastern Asia", "1.0.0.1", 3),
+		{Probe: model.ProbeData{City: "Lima", Country: "PE", Continent: "SA", ASN: 2}, Result: model.ResultData{Status: "failed"}},
+	}}
+
+	output, distant := client.FormatAnycast("ping", data, 50*time.Millisecond)
+	assert.Equal(t, 1, distant)
+	assert.Equal(t, `PROBE              REGION                     ADDRESS  LATENCY   NOTE
+Berlin, DE, ASN:1  Western Europe             1.1.1.1  4.2 ms    
+Paris, FR, ASN:1   Western Europe             1.1.1.1  6.0 ms    
+Sydney, AU, ASN:1  Australia and New Zealand  1.1.1.1  180.5 ms  distant site
+Tokyo, JP, ASN:1   Eastern Asia               1.0.0.1  3.0 ms    
+Lima, PE, ASN:2    SA                         -        -         probe failed
+
+REGION                     PROBES  ADDRESSES  MEDIAN LATENCY
+Western Europe             2       1          5.1 ms
+Australia and New Zealand  1       1          180.5 ms
+Eastern Asia               1       1          3.0 ms
+SA                         1       0          -
+
+2 distinct addresses: 1.1.1.1 (3 probes), 1.0.0.1 (1 probe)
+1 probe above 50ms, likely routed to a distant site: Sydney, AU, ASN:1`, output)
+
+	// dns results are compared by their A and AAAA answers
+	dns := model.GetMeasurement{Results: []model.MeasurementResponse{{
+		Probe: model.ProbeData{City: "Berlin", Country: "DE", Region: "Western Europe", ASN: 1},
+		Result: model.ResultData{Status: "finished", Timings: &model.Timings{Total: ms(12)}, Answers: []model.DnsAnswer{
+			{Type: "CNAME", Value: "jsdelivr.map.fastly.net."},
+			{Type: "A", Value: "151.101.1.229"},
+		}},
+	}}}
+	output, distant = client.FormatAnycast("dns", dns, 0)
+	assert.Equal(t, 0, distant)
+	assert.Equal(t, `PROBE              REGION          ADDRESS        LATENCY  NOTE
+Berlin, DE, ASN:1  Western Europe  151.101.1.229  12.0 ms  
+
+REGION          PROBES  ADDRESSES  MEDIAN LATENCY
+Western Europe  1       1          12.0 ms
+
+1 distinct address: 151.101.1.229 (1 probe)`, output)
+}
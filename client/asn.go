@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// ASNInfo is the autonomous system announcing an IP address
+type ASNInfo struct {
+	ASN  int
+	Name string
+}
+
+// ASNLookup resolves the autonomous system of an IP address, replaced in tests
+var ASNLookup = lookupCymru
+
+// Maximum time spent resolving the ASN of one address, and number of addresses resolved at once
+const (
+	asnTimeout = 2 * time.Second
+	asnWorkers = 8
+)
+
+var (
+	asnCacheMu sync.Mutex
+	asnCache   = map[string]ASNInfo{}
+)
+
+// Public address whose ASN can be looked up
+func asnAddress(ip string) (net.IP, bool) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsLinkLocalUnicast() {
+		return nil, false
+	}
+	return parsed, true
+}
+
+// LookupASN returns the autonomous system of a public IP address, results are cached for the life of the process.
+// Lookups interrupted by the context are not cached
+func LookupASN(c context.Context, ip string) (ASNInfo, bool) {
+	parsed, ok := asnAddress(ip)
+	if !ok {
+		return ASNInfo{}, false
+	}
+	if info, ok := cachedASN(ip); ok {
+		return info, info.ASN != 0
+	}
+
+	lc, cancel := context.WithTimeout(c, asnTimeout)
+	defer cancel()
+	info, err := ASNLookup(lc, parsed)
+	if err != nil {
+		info = ASNInfo{}
+	}
+	if c.Err() != nil {
+		return info, info.ASN != 0
+	}
+
+	asnCacheMu.Lock()
+	asnCache[ip] = info
+	asnCacheMu.Unlock()
+	return info, info.ASN != 0
+}
+
+// Cached ASN of an address, the second value is false if it was never looked up
+func cachedASN(ip string) (ASNInfo, bool) {
+	asnCacheMu.Lock()
+	defer asnCacheMu.Unlock()
+	info, ok := asnCache[ip]
+	return info, ok
+}
+
+// knownASN returns the ASN of an address resolved by ResolveASNs
+func knownASN(ip string) (ASNInfo, bool) {
+	info, _ := cachedASN(ip)
+	return info, info.ASN != 0
+}
+
+// ResolveASNs looks up the ASN of every hop of a traceroute measurement concurrently, the formatters enriching the
+// hops only read the results. It returns early when the context is done, the remaining hops are left without ASN
+func ResolveASNs(c context.Context, data model.GetMeasurement) {
+	seen := map[string]bool{}
+	var ips []string
+	for _, result := range data.Results {
+		for _, hop := range result.Result.Hops {
+			ip := hop.ResolvedAddress
+			if _, ok := asnAddress(ip); !ok || seen[ip] {
+				continue
+			}
+			seen[ip] = true
+			if _, ok := cachedASN(ip); !ok {
+				ips = append(ips, ip)
+			}
+		}
+	}
+
+	sem := make(chan struct{}, asnWorkers)
+	var wg sync.WaitGroup
+	for _, ip := range ips {
+		select {
+		case <-c.Done():
+		case sem <- struct{}{}:
+			wg.Add(1)
+			go func(ip string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				LookupASN(c, ip)
+			}(ip)
+		}
+	}
+	wg.Wait()
+}
+
+// lookupCymru queries the IP to ASN mapping of Team Cymru over DNS
+func lookupCymru(c context.Context, ip net.IP) (ASNInfo, error) {
+	var name string
+	if v4 := ip.To4(); v4 != nil {
+		name = fmt.Sprintf("%d.%d.%d.%d.origin.asn.cymru.com", v4[3], v4[2], v4[1], v4[0])
+	} else {
+		// Reversed nibbles of the IPv6 address
+		hex := fmt.Sprintf("%x", []byte(ip.To16()))
+		nibbles := make([]string, len(hex))
+		for i := range hex {
+			nibbles[len(hex)-1-i] = string(hex[i])
+		}
+		name = strings.Join(nibbles, ".") + ".origin6.asn.cymru.com"
+	}
+
+	// e.g. "15169 | 142.250.0.0/15 | US | arin | 2012-05-24"
+	records, err := net.DefaultResolver.LookupTXT(c, name)
+	if err != nil || len(records) == 0 {
+		return ASNInfo{}, errors.New("err: no ASN found for " + ip.String())
+	}
+	var info ASNInfo
+	fields := strings.Split(records[0], "|")
+	// Addresses announced by several ASNs list all of them, keep the first one
+	asns := strings.Fields(fields[0])
+	if len(asns) == 0 {
+		return ASNInfo{}, errors.New("err: invalid ASN record for " + ip.String())
+	}
+	_, err = fmt.Sscanf(asns[0], "%d", &info.ASN)
+	if err != nil {
+		return ASNInfo{}, errors.New("err: invalid ASN record for " + ip.String())
+	}
+
+	// e.g. "15169 | US | arin | 2000-03-30 | GOOGLE, US"
+	records, err = net.DefaultResolver.LookupTXT(c, fmt.Sprintf("AS%d.asn.cymru.com", info.ASN))
+	if err == nil && len(records) > 0 {
+		fields = strings.Split(records[0], "|")
+		info.Name = strings.TrimSpace(fields[len(fields)-1])
+	}
+
+	return info, nil
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// ASN of the hops of a traceroute or mtr result in order, mtr hops carry them while traceroute hops are resolved by
+// ResolveASNs unless disabled in the context. names collects the organization of the looked up ASNs
+func hopASNs(cmd string, hops []model.Hop, ctx model.Context, names map[int]string) [][]int {
+	res := make([][]int, len(hops))
+	for i, hop := range hops {
+		if cmd == "mtr" {
+			res[i] = hop.ASN
+			continue
+		}
+		if ctx.NoEnrich {
+			continue
+		}
+		if info, ok := knownASN(hop.ResolvedAddress); ok {
+			res[i] = []int{info.ASN}
+			if info.Name != "" {
+				names[info.ASN] = info.Name
+			}
+		}
+	}
+	return res
+}
+
+// ASPath returns the sequence of autonomous systems crossed by a traceroute or mtr result, starting with the network
+// of the probe. Hops without an ASN are skipped and consecutive hops in the same ASN are merged
+func ASPath(cmd string, result model.MeasurementResponse, ctx model.Context, names map[int]string) []int {
+	var path []int
+	add := func(asn int) {
+		if asn != 0 && (len(path) == 0 || path[len(path)-1] != asn) {
+			path = append(path, asn)
+		}
+	}
+
+	add(result.Probe.ASN)
+	for _, asns := range hopASNs(cmd, result.Result.Hops, ctx, names) {
+		// Addresses announced by several ASNs use the first one
+		if len(asns) > 0 {
+			add(asns[0])
+		}
+	}
+	return path
+}
+
+// Name of an ASN with its organization when known, e.g. AS174 (COGENT-174)
+func asnLabel(asn int, names map[int]string) string {
+	if name, ok := names[asn]; ok {
+		return fmt.Sprintf("AS%d (%s)", asn, name)
+	}
+	return fmt.Sprintf("AS%d", asn)
+}
+
+// FormatASPath renders the AS path of every probe of a traceroute or mtr measurement, followed by the transit
+// networks, the ones between the network of the probe and the last network reached, by number of probes crossing them
+func FormatASPath(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+	if cmd != "traceroute" && cmd != "mtr" {
+		return "", fmt.Errorf("err: the aspath format is not supported for %s measurements", cmd)
+	}
+	if cmd == "traceroute" && ctx.NoEnrich {
+		return "", fmt.Errorf("err: the aspath format needs the ASN of every hop, remove --no-enrich")
+	}
+
+	var output strings.Builder
+	names := map[int]string{}
+	transit := map[int]int{}
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+
+		path := ASPath(cmd, result, ctx, names)
+		labels := make([]string, len(path))
+		for i, asn := range path {
+			labels[i] = fmt.Sprintf("AS%d", asn)
+			if i > 0 && i < len(path)-1 {
+				transit[asn]++
+			}
+		}
+		if len(result.Result.Hops) == 0 {
+			output.WriteString("No hops\n\n")
+			continue
+		}
+		output.WriteString(strings.Join(labels, " → ") + "\n\n")
+	}
+
+	if len(transit) == 0 {
+		output.WriteString("No transit networks")
+		return output.String(), nil
+	}
+
+	asns := make([]int, 0, len(transit))
+	for asn := range transit {
+		asns = append(asns, asn)
+	}
+	sort.Slice(asns, func(i, j int) bool {
+		if transit[asns[i]] != transit[asns[j]] {
+			return transit[asns[i]] > transit[asns[j]]
+		}
+		return asns[i] < asns[j]
+	})
+
+	output.WriteString("Transit networks:\n")
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NETWORK\tPROBES")
+	for _, asn := range asns {
+		fmt.Fprintf(w, "%s\t%d of %d\n", asnLabel(asn, names), transit[asn], len(data.Results))
+	}
+	w.Flush()
+
+	return strings.TrimRight(output.String(), "\n"), nil
+}
@@ -0,0 +1,78 @@
+package client_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatASPath(t *testing.T) {
+	lookup := client.ASNLookup
+	t.Cleanup(func() { client.ASNLookup = lookup })
+	client.ASNLookup = func(c context.Context, ip net.IP) (client.ASNInfo, error) {
+		switch ip.String() {
+		case "198.51.100.1":
+			return client.ASNInfo{ASN: 174, Name: "COGENT-174"}, nil
+		case "198.51.100.2":
+			return client.ASNInfo{ASN: 13335, Name: "CLOUDFLARENET"}, nil
+		}
+		return client.ASNInfo{ASN: 3320, Name: "DTAG"}, nil
+	}
+
+	data := model.GetMeasurement{Type: "traceroute", Results: []model.MeasurementResponse{
+		{
+			Probe: model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 3320, Network: "Deutsche Telekom"},
+			Result: model.ResultData{Status: "finished", Hops: hops(`[
+				{"resolvedAddress": "10.0.0.1"},
+				{"resolvedAddress": "198.51.100.3"},
+				{"resolvedAddress": ""},
+				{"resolvedAddress": "198.51.100.1"},
+				{"resolvedAddress": "198.51.100.1"},
+				{"resolvedAddress": "198.51.100.2"}
+			]`)},
+		},
+		{
+			Probe:  model.ProbeData{Continent: "EU", Country: "FR", City: "Paris", ASN: 12322, Network: "Free"},
+			Result: model.ResultData{Status: "finished", Hops: hops(`[{"resolvedAddress": "198.51.100.2"}]`)},
+		},
+	}}
+
+	client.ResolveASNs(context.Background(), data)
+	output, err := client.FormatASPath(data, model.Context{CI: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `> EU, DE, Berlin, ASN:3320, Deutsche Telekom
+AS3320 → AS174 → AS13335
+
+> EU, FR, Paris, ASN:12322, Free
+AS12322 → AS13335
+
+Transit networks:
+NETWORK             PROBES
+AS174 (COGENT-174)  1 of 2`, output)
+
+	mtr := model.GetMeasurement{Type: "mtr", Results: []model.MeasurementResponse{{
+		Probe: model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 3320, Network: "Deutsche Telekom"},
+		Result: model.ResultData{Status: "finished", Hops: hops(`[
+			{"resolvedAddress": "192.168.1.1", "asn": []},
+			{"resolvedAddress": "203.0.113.1", "asn": [3320]},
+			{"resolvedAddress": "203.0.113.2", "asn": [13335]}
+		]`)},
+	}}}
+	output, err = client.FormatASPath(mtr, model.Context{CI: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `> EU, DE, Berlin, ASN:3320, Deutsche Telekom
+AS3320 → AS13335
+
+No transit networks`, output)
+
+	_, err = client.FormatASPath(data, model.Context{CI: true, NoEnrich: true})
+	assert.EqualError(t, err, "err: the aspath format needs the ASN of every hop, remove --no-enrich")
+
+	_, err = client.FormatASPath(model.GetMeasurement{Type: "ping"}, model.Context{})
+	assert.EqualError(t, err, "err: the aspath format is not supported for ping measurements")
+}
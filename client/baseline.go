@@ -0,0 +1,50 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Tolerance of a comparison with a baseline: Latency is the increase allowed in percent of the baseline latency and
+// Loss the increase of packet loss allowed in percentage points
+type Tolerance struct {
+	Latency float64
+	Loss    float64
+}
+
+// CompareBaseline returns the regressions of a measurement against its baseline, probe by probe as aligned by diff.
+// A probe regresses when it no longer finishes, or when its latency or packet loss grew beyond the tolerance. Probes
+// missing from one of the measurements are not compared.
+func CompareBaseline(cmd string, base, data model.GetMeasurement, tol Tolerance) []Violation {
+	var violations []Violation
+	for _, p := range AlignResults(base, data) {
+		if p.A == nil || p.B == nil {
+			continue
+		}
+
+		if p.A.Result.Status == "finished" && p.B.Result.Status != "finished" {
+			violations = append(violations, Violation{Probe: p.Label, Reason: "probe " + p.B.Result.Status + ", finished in the baseline"})
+			continue
+		}
+
+		bm, dm := diffMetrics(cmd, p.A), diffMetrics(cmd, p.B)
+		if bv, ok := bm["latency"]; ok && bv > 0 {
+			if dv, ok := dm["latency"]; ok && (dv-bv)/bv*100 > tol.Latency {
+				violations = append(violations, Violation{
+					Probe:  p.Label,
+					Reason: fmt.Sprintf("latency %.2f ms is %.0f%% above the baseline %.2f ms", dv, (dv-bv)/bv*100, bv),
+				})
+			}
+		}
+		if bv, ok := bm["loss"]; ok {
+			if dv, ok := dm["loss"]; ok && dv-bv > tol.Loss {
+				violations = append(violations, Violation{
+					Probe:  p.Label,
+					Reason: fmt.Sprintf("packet loss %v%% exceeds the baseline %v%%", dv, bv),
+				})
+			}
+		}
+	}
+	return violations
+}
@@ -0,0 +1,31 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareBaseline(t *testing.T) {
+	lossy := pingResult("Munich", 20)
+	lossy.Result.Stats.Loss = 2.0
+	failed := pingResult("Hamburg", 0)
+	failed.Result.Status = "failed"
+
+	base := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 10), pingResult("Munich", 20), pingResult("Hamburg", 5), pingResult("Leipzig", 30)}}
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 11), lossy, failed, pingResult("Leipzig", 40)}}
+
+	violations := client.CompareBaseline("ping", base, data, client.Tolerance{Latency: 10})
+	assert.Equal(t, []client.Violation{
+		{Probe: "Munich, DE, ASN:1", Reason: "packet loss 2% exceeds the baseline 0%"},
+		{Probe: "Hamburg, DE, ASN:1", Reason: "probe failed, finished in the baseline"},
+		{Probe: "Leipzig, DE, ASN:1", Reason: "latency 40.00 ms is 33% above the baseline 30.00 ms"},
+	}, violations)
+
+	// Within the tolerance
+	violations = client.CompareBaseline("ping", base, data, client.Tolerance{Latency: 50, Loss: 5})
+	assert.Equal(t, []client.Violation{{Probe: "Hamburg, DE, ASN:1", Reason: "probe failed, finished in the baseline"}}, violations)
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// BatchResult is the outcome of one measurement of a batch
+type BatchResult struct {
+	ID   string
+	Data model.GetMeasurement
+	Err  error
+}
+
+// BatchSummary renders one line per target with its status, number of probes and average latency across probes
+func BatchSummary(cmd string, targets []string, results []BatchResult) string {
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TARGET\tSTATUS\tPROBES\tAVG")
+
+	failed := 0
+	for i, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(w, "%s\tfailed\t-\t-\n", targets[i])
+			continue
+		}
+
+		avg := "-"
+		if v, ok := averageMetric(cmd, r.Data); ok {
+			avg = fmt.Sprintf("%.2f ms", v)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", targets[i], r.Data.Status, len(r.Data.Results), avg)
+	}
+	w.Flush()
+
+	output.WriteString(fmt.Sprintf("\n%d targets, %d succeeded, %d failed", len(results), len(results)-failed, failed))
+	return output.String()
+}
+
+// Average of the key metric across the probes of a measurement
+func averageMetric(cmd string, data model.GetMeasurement) (float64, bool) {
+	sum, n := 0.0, 0
+	for _, result := range data.Results {
+		if v, ok := KeyMetric(cmd, result); ok {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// ManifestResult is the outcome of a named measurement of a manifest, it passes when every probe finished within the
+// thresholds
+type ManifestResult struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Target     string   `json:"target"`
+	ID         string   `json:"id,omitempty"`
+	Status     string   `json:"status"`
+	Probes     int      `json:"probes"`
+	Avg        *float64 `json:"avg,omitempty"`
+	Passed     bool     `json:"passed"`
+	Violations []string `json:"violations,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// NewManifestResult checks the result of a measurement of a manifest against its thresholds
+func NewManifestResult(name string, ctx model.Context, r BatchResult) ManifestResult {
+	res := ManifestResult{Name: name, Type: ctx.Cmd, Target: ctx.Target, ID: r.ID}
+	if r.Err != nil {
+		res.Status = "failed"
+		res.Error = r.Err.Error()
+		return res
+	}
+
+	res.Status = r.Data.Status
+	res.Probes = len(r.Data.Results)
+	if v, ok := averageMetric(ctx.Cmd, r.Data); ok {
+		res.Avg = &v
+	}
+	violations := CheckThresholds(ctx.Cmd, r.Data, ctx.Thresholds)
+	for _, v := range violations {
+		res.Violations = append(res.Violations, v.String())
+	}
+	res.Passed = !MeasurementFailed(r.Data, violations, ctx.Thresholds.FailOnProbeErrors)
+	return res
+}
+
+// ManifestReport renders one line per measurement of a manifest with its result, followed by the reasons of the
+// failures and a summary
+func ManifestReport(results []ManifestResult) string {
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tTARGET\tSTATUS\tPROBES\tAVG\tRESULT")
+
+	failed := 0
+	for _, r := range results {
+		result := "PASS"
+		if !r.Passed {
+			result = "FAIL"
+			failed++
+		}
+		probes, avg := "-", "-"
+		if r.Error == "" {
+			probes = fmt.Sprint(r.Probes)
+		}
+		if r.Avg != nil {
+			avg = fmt.Sprintf("%.2f ms", *r.Avg)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Name, r.Type, r.Target, r.Status, probes, avg, result)
+	}
+	w.Flush()
+
+	for _, r := range results {
+		if r.Passed {
+			continue
+		}
+		output.WriteString("\n" + r.Name + ":")
+		if r.Error != "" {
+			output.WriteString("\n  " + r.Error)
+		}
+		for _, v := range r.Violations {
+			output.WriteString("\n  " + v)
+		}
+		if r.Error == "" && len(r.Violations) == 0 {
+			output.WriteString("\n  the measurement did not finish on every probe")
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString(fmt.Sprintf("\n%d measurements, %d passed, %d failed", len(results), len(results)-failed, failed))
+	return output.String()
+}
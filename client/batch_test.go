@@ -0,0 +1,56 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchSummary(t *testing.T) {
+	results := []client.BatchResult{
+		{ID: "a", Data: model.GetMeasurement{Status: "finished", Results: []model.MeasurementResponse{pingResult("Berlin", 10), pingResult("Munich", 20)}}},
+		{Err: errors.New("err: request failed")},
+	}
+
+	assert.Equal(t, `TARGET          STATUS    PROBES  AVG
+google.com      finished  2       15.00 ms
+cloudflare.com  failed    -       -
+
+2 targets, 1 succeeded, 1 failed`, client.BatchSummary("ping", []string{"google.com", "cloudflare.com"}, results))
+}
+
+func TestManifestReport(t *testing.T) {
+	data := model.GetMeasurement{Status: "finished", Results: []model.MeasurementResponse{pingResult("Berlin", 10), pingResult("Munich", 80)}}
+	ctx := model.Context{Cmd: "ping", Target: "jsdelivr.com"}
+
+	passed := client.NewManifestResult("ping", ctx, client.BatchResult{ID: "a", Data: data})
+	assert.True(t, passed.Passed)
+	assert.Equal(t, 45.0, *passed.Avg)
+
+	ctx.Thresholds.MaxLatency = 50 * time.Millisecond
+	slow := client.NewManifestResult("slow ping", ctx, client.BatchResult{ID: "b", Data: data})
+	assert.False(t, slow.Passed)
+	assert.Equal(t, []string{"Munich, DE, ASN:1: latency 80.00 ms exceeds 50ms"}, slow.Violations)
+
+	failed := client.NewManifestResult("cdn", model.Context{Cmd: "http", Target: "cdn.jsdelivr.net"}, client.BatchResult{Err: errors.New("err: request failed")})
+	assert.False(t, failed.Passed)
+	assert.Equal(t, "err: request failed", failed.Error)
+
+	assert.Equal(t, `NAME       TYPE  TARGET            STATUS    PROBES  AVG       RESULT
+ping       ping  jsdelivr.com      finished  2       45.00 ms  PASS
+slow ping  ping  jsdelivr.com      finished  2       45.00 ms  FAIL
+cdn        http  cdn.jsdelivr.net  failed    -       -         FAIL
+
+slow ping:
+  Munich, DE, ASN:1: latency 80.00 ms exceeds 50ms
+
+cdn:
+  err: request failed
+
+3 measurements, 1 passed, 2 failed`, client.ManifestReport([]client.ManifestResult{passed, slow, failed}))
+}
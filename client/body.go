@@ -0,0 +1,72 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// ResponseBodies renders the response body returned to every probe of an http measurement under its header
+func ResponseBodies(data model.GetMeasurement, ctx model.Context) string {
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+		if result.Result.RawBody == "" {
+			output.WriteString("No response body, only GET requests return one\n\n")
+			continue
+		}
+		output.WriteString(strings.TrimSpace(limitBody(result.Result.RawBody, ctx.BodyLimit)) + "\n\n")
+	}
+	return strings.TrimSpace(output.String())
+}
+
+// Cut the body to at most limit bytes, zero means no limit
+func limitBody(body string, limit int) string {
+	if limit > 0 && len(body) > limit {
+		return body[:limit]
+	}
+	return body
+}
+
+var unsafeFilename = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// BodyFilename returns the per-probe file name for a response body saved to path, e.g. out.html becomes out-DE-Berlin.html
+func BodyFilename(path string, probe model.ProbeData) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	suffix := unsafeFilename.ReplaceAllString(probe.Country+"-"+probe.City, "_")
+	return base + "-" + suffix + ext
+}
+
+// SaveBodies writes the response body of every probe of an http measurement to its own file derived from path
+// and returns the written files. Probes without a body are skipped.
+func SaveBodies(data model.GetMeasurement, path string, limit int) ([]string, error) {
+	var files []string
+	seen := map[string]int{}
+
+	for _, result := range data.Results {
+		if result.Result.RawBody == "" {
+			continue
+		}
+
+		name := BodyFilename(path, result.Probe)
+		// Several probes may be in the same city
+		seen[name]++
+		if seen[name] > 1 {
+			ext := filepath.Ext(name)
+			name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), seen[name], ext)
+		}
+
+		err := os.WriteFile(name, []byte(limitBody(result.Result.RawBody, limit)), 0o644)
+		if err != nil {
+			return files, fmt.Errorf("err: failed to save response body: %s", name)
+		}
+		files = append(files, name)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,53 @@
+package client_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseBodies(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		{
+			Probe:  model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+			Result: model.ResultData{RawBody: "<html></html>\n"},
+		},
+		{
+			Probe: model.ProbeData{Continent: "EU", Country: "FR", City: "Paris", ASN: 2, Network: "Network"},
+		},
+	}}
+
+	assert.Equal(t, `> EU, DE, Berlin, ASN:1, Network
+<html></html>
+
+> EU, FR, Paris, ASN:2, Network
+No response body, only GET requests return one`, client.ResponseBodies(data, model.Context{CI: true}))
+}
+
+func TestBodyFilename(t *testing.T) {
+	assert.Equal(t, "out-DE-Berlin.html", client.BodyFilename("out.html", model.ProbeData{Country: "DE", City: "Berlin"}))
+	assert.Equal(t, "dir/out-US-New_York", client.BodyFilename("dir/out", model.ProbeData{Country: "US", City: "New York"}))
+}
+
+func TestSaveBodies(t *testing.T) {
+	dir := t.TempDir()
+	berlin := model.ProbeData{Country: "DE", City: "Berlin"}
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		{Probe: berlin, Result: model.ResultData{RawBody: "<html>first</html>"}},
+		{Probe: berlin, Result: model.ResultData{RawBody: "<html>second</html>"}},
+		{Probe: model.ProbeData{Country: "FR", City: "Paris"}},
+	}}
+
+	files, err := client.SaveBodies(data, filepath.Join(dir, "out.html"), 12)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "out-DE-Berlin.html"), filepath.Join(dir, "out-DE-Berlin-2.html")}, files)
+
+	b, err := os.ReadFile(files[1])
+	assert.NoError(t, err)
+	assert.Equal(t, "<html>second", string(b))
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Latency of the last hop of a traceroute or mtr result, the destination when it was reached
+func lastHopLatency(cmd string, result model.MeasurementResponse) (float64, bool) {
+	hops := result.Result.Hops
+	if len(hops) == 0 {
+		return 0, false
+	}
+	last := hops[len(hops)-1]
+	if cmd == "mtr" {
+		s := hopStats(last)
+		return s.Avg, s.Rcv > 0
+	}
+	rtts := last.Timings.RTTs()
+	if len(rtts) == 0 {
+		return 0, false
+	}
+	return rtts[len(rtts)-1], true
+}
+
+// Metric column of the brief format in milliseconds without unit, the status of the probe if it has no latency
+func briefMetric(cmd string, result model.MeasurementResponse) string {
+	if ProbeFailed(result) || result.Result.Status == "in-progress" {
+		return result.Result.Status
+	}
+	v, ok := probeLatency(cmd, result)
+	if !ok {
+		return "-"
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// Tabs and line breaks would split a field
+var briefReplacer = strings.NewReplacer("\t", " ", "\n", " ")
+
+// FormatBrief renders one tab separated line per probe with its country, city, network and key metric, without
+// headers or colors so the output can be processed with awk, sort or cut
+func FormatBrief(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+
+	lines := make([]string, len(data.Results))
+	for i, result := range data.Results {
+		p := result.Probe
+		fields := []string{p.Country, p.City, p.Network, briefMetric(cmd, result)}
+		for j, f := range fields {
+			fields[j] = briefReplacer.Replace(f)
+		}
+		lines[i] = strings.Join(fields, "\t")
+	}
+	return strings.Join(lines, "\n"), nil
+}
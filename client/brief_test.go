@@ -0,0 +1,44 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatBrief(t *testing.T) {
+	failed := pingResult("Munich", 0)
+	failed.Result.Status = "failed"
+	noStats := pingResult("Hamburg", 0)
+	noStats.Result.Stats = nil
+	berlin := pingResult("Berlin", 12.345)
+	berlin.Probe.Network = "Deutsche\tTelekom"
+
+	output, err := client.FormatBrief(model.GetMeasurement{Type: "ping", Results: []model.MeasurementResponse{berlin, failed, noStats}}, model.Context{})
+	assert.NoError(t, err)
+	assert.Equal(t, "DE\tBerlin\tDeutsche Telekom\t12.35\n"+
+		"DE\tMunich\t\tfailed\n"+
+		"DE\tHamburg\t\t-", output)
+
+	mtr := model.GetMeasurement{Type: "mtr", Results: []model.MeasurementResponse{{
+		Probe: model.ProbeData{Country: "FR", City: "Paris", Network: "Free"},
+		Result: model.ResultData{Status: "finished", Hops: hops(`[
+			{"resolvedAddress": "10.0.0.1", "stats": {"avg": 0.5, "rcv": 3}},
+			{"resolvedAddress": "1.1.1.1", "stats": {"avg": 4.25, "rcv": 3}}
+		]`)},
+	}}}
+	output, err = client.FormatBrief(mtr, model.Context{})
+	assert.NoError(t, err)
+	assert.Equal(t, "FR\tParis\tFree\t4.25", output)
+
+	traceroute := model.GetMeasurement{Type: "traceroute", Results: []model.MeasurementResponse{{
+		Probe:  model.ProbeData{Country: "FR", City: "Paris", Network: "Free"},
+		Result: model.ResultData{Status: "finished", Hops: hops(`[{"resolvedAddress": "1.1.1.1", "timings": [{"rtt": 4.1}, {"rtt": 3.9}]}]`)},
+	}}}
+	output, err = client.FormatBrief(traceroute, model.Context{})
+	assert.NoError(t, err)
+	assert.Equal(t, "FR\tParis\tFree\t3.90", output)
+}
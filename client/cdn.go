@@ -0,0 +1,141 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// CDNInfo is the CDN that served the response of a probe and its POP (point of presence), empty if unknown
+type CDNInfo struct {
+	CDN string
+	POP string
+}
+
+func (i CDNInfo) String() string {
+	if i.POP == "" {
+		return i.CDN
+	}
+	return i.CDN + " " + i.POP
+}
+
+var (
+	// e.g. cache-fra-eddf8230098-FRA
+	fastlyPOP = regexp.MustCompile(`-([A-Z]{3})$`)
+	// e.g. am3-up-gc88
+	gcorePOP = regexp.MustCompile(`^([a-z]{2,3}\d*)-up-gc\d+`)
+	// e.g. BunnyCDN-DE1-1053
+	bunnyPOP = regexp.MustCompile(`(?i)^BunnyCDN-([A-Z]+\d*)`)
+)
+
+// First value of a header, empty if missing
+func firstHeader(headers []Header, name string) string {
+	if values, ok := findHeader(headers, name); ok {
+		return values[0]
+	}
+	return ""
+}
+
+// DetectCDN identifies the CDN that served the response of a probe, and its POP when the CDN exposes it, from the
+// headers the CDN adds to the response
+func DetectCDN(result model.MeasurementResponse) (CDNInfo, bool) {
+	headers := ParseHeaders(result)
+	server := strings.ToLower(firstHeader(headers, "Server"))
+	via := strings.ToLower(firstHeader(headers, "Via"))
+
+	switch {
+	case firstHeader(headers, "CF-Ray") != "" || firstHeader(headers, "CF-Cache-Status") != "" || server == "cloudflare":
+		// The ray ID ends with the airport code of the data center, e.g. 79de849d3fa30c33-AMS
+		ray := firstHeader(headers, "CF-Ray")
+		pop := ""
+		if i := strings.LastIndex(ray, "-"); i >= 0 {
+			pop = ray[i+1:]
+		}
+		return CDNInfo{CDN: "Cloudflare", POP: pop}, true
+	case firstHeader(headers, "X-Amz-Cf-Pop") != "" || firstHeader(headers, "X-Amz-Cf-Id") != "" || strings.Contains(via, "cloudfront"):
+		return CDNInfo{CDN: "CloudFront", POP: firstHeader(headers, "X-Amz-Cf-Pop")}, true
+	case firstHeader(headers, "X-Served-By") != "" && strings.HasPrefix(firstHeader(headers, "X-Served-By"), "cache-"),
+		firstHeader(headers, "X-Fastly-Request-ID") != "":
+		// With shielding the edge closest to the client is the last one
+		pop := ""
+		if values, ok := findHeader(headers, "X-Served-By"); ok {
+			served := strings.Split(values[len(values)-1], ",")
+			if m := fastlyPOP.FindStringSubmatch(strings.TrimSpace(served[len(served)-1])); m != nil {
+				pop = m[1]
+			}
+		}
+		return CDNInfo{CDN: "Fastly", POP: pop}, true
+	case strings.HasPrefix(server, "akamai") || firstHeader(headers, "X-Akamai-Transformed") != "" || firstHeader(headers, "Akamai-Grn") != "":
+		return CDNInfo{CDN: "Akamai"}, true
+	case gcorePOP.MatchString(firstHeader(headers, "X-ID")):
+		return CDNInfo{CDN: "Gcore", POP: strings.ToUpper(gcorePOP.FindStringSubmatch(firstHeader(headers, "X-ID"))[1])}, true
+	case strings.HasPrefix(server, "bunnycdn"):
+		pop := ""
+		if m := bunnyPOP.FindStringSubmatch(firstHeader(headers, "Server")); m != nil {
+			pop = strings.ToUpper(m[1])
+		}
+		return CDNInfo{CDN: "Bunny", POP: pop}, true
+	case firstHeader(headers, "X-Vercel-Id") != "":
+		// e.g. fra1::iad1::abcd-1700000000000-0123456789ab, the first region is the edge
+		pop, _, _ := strings.Cut(firstHeader(headers, "X-Vercel-Id"), "::")
+		return CDNInfo{CDN: "Vercel", POP: pop}, true
+	case server == "netlify" || firstHeader(headers, "X-Nf-Request-Id") != "":
+		return CDNInfo{CDN: "Netlify"}, true
+	}
+	return CDNInfo{}, false
+}
+
+// FormatCDN renders the CDN, POP and cache status of every probe of an http measurement, followed by the number of
+// probes served by every POP
+func FormatCDN(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+	if cmd != "http" {
+		return "", fmt.Errorf("err: the cdn format is not supported for %s measurements", cmd)
+	}
+
+	var order []string
+	counts := map[string]int{}
+
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROBE\tCDN\tPOP\tCACHE")
+	for _, result := range data.Results {
+		cdn, pop, cache := "-", "-", "-"
+		pops := "unknown"
+		if info, ok := DetectCDN(result); ok {
+			cdn = info.CDN
+			if info.POP != "" {
+				pop = info.POP
+			}
+			pops = info.String()
+		} else if result.Result.Status != "" && result.Result.Status != "finished" {
+			cdn = result.Result.Status
+			pops = "no response"
+		}
+		if _, value, ok := cacheStatus(ParseHeaders(result)); ok {
+			cache = value
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", probeLabel(result.Probe), cdn, pop, cache)
+
+		if counts[pops] == 0 {
+			order = append(order, pops)
+		}
+		counts[pops]++
+	}
+	w.Flush()
+
+	// Most used POPs first, then in the order of the probes
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	output.WriteString("\nPOP distribution:\n")
+	w = tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	for _, pop := range order {
+		fmt.Fprintf(w, "  %s\t%s\n", pop, plural(counts[pop], "probe"))
+	}
+	w.Flush()
+
+	return strings.TrimRight(output.String(), "\n"), nil
+}
@@ -0,0 +1,67 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectCDN(t *testing.T) {
+	tests := []struct {
+		headers  string
+		expected client.CDNInfo
+	}{
+		{"Server: cloudflare\nCF-Ray: 79de849d3fa30c33-AMS", client.CDNInfo{CDN: "Cloudflare", POP: "AMS"}},
+		{"Via: 1.1 varnish\nX-Served-By: cache-fra-eddf8230098-FRA, cache-ams21046-AMS", client.CDNInfo{CDN: "Fastly", POP: "AMS"}},
+		{"Via: 1.1 abc.cloudfront.net (CloudFront)\nX-Amz-Cf-Pop: FRA56-P1", client.CDNInfo{CDN: "CloudFront", POP: "FRA56-P1"}},
+		{"Server: AkamaiGHost", client.CDNInfo{CDN: "Akamai"}},
+		{"Server: nginx\nX-ID: am3-up-gc88", client.CDNInfo{CDN: "Gcore", POP: "AM3"}},
+		{"Server: BunnyCDN-DE1-1053", client.CDNInfo{CDN: "Bunny", POP: "DE1"}},
+		{"X-Vercel-Id: fra1::iad1::abcd-1700000000000", client.CDNInfo{CDN: "Vercel", POP: "fra1"}},
+	}
+	for _, tt := range tests {
+		info, ok := client.DetectCDN(model.MeasurementResponse{Result: model.ResultData{RawHeaders: tt.headers}})
+		assert.True(t, ok, tt.headers)
+		assert.Equal(t, tt.expected, info)
+	}
+
+	_, ok := client.DetectCDN(model.MeasurementResponse{Result: model.ResultData{RawHeaders: "Server: nginx"}})
+	assert.False(t, ok)
+}
+
+func TestFormatCDN(t *testing.T) {
+	probe := func(city string, asn int, headers string) model.MeasurementResponse {
+		return model.MeasurementResponse{
+			Probe:  model.ProbeData{City: city, Country: "DE", ASN: asn},
+			Result: model.ResultData{Status: "finished", RawHeaders: headers},
+		}
+	}
+	data := model.GetMeasurement{Type: "http", Results: []model.MeasurementResponse{
+		probe("Berlin", 1, "CF-Ray: 1-TXL\nCF-Cache-Status: HIT"),
+		probe("Munich", 2, "CF-Ray: 2-MUC\nCF-Cache-Status: MISS"),
+		probe("Hamburg", 3, "CF-Ray: 3-TXL\nCF-Cache-Status: HIT"),
+		probe("Cologne", 4, "Server: nginx"),
+		{Probe: model.ProbeData{City: "Bonn", Country: "DE", ASN: 5}, Result: model.ResultData{Status: "failed"}},
+	}}
+
+	output, err := client.FormatCDN(data, model.Context{})
+	assert.NoError(t, err)
+	assert.Equal(t, `PROBE               CDN         POP  CACHE
+Berlin, DE, ASN:1   Cloudflare  TXL  HIT
+Munich, DE, ASN:2   Cloudflare  MUC  MISS
+Hamburg, DE, ASN:3  Cloudflare  TXL  HIT
+Cologne, DE, ASN:4  -           -    -
+Bonn, DE, ASN:5     failed      -    -
+
+POP distribution:
+  Cloudflare TXL  2 probes
+  Cloudflare MUC  1 probe
+  unknown         1 probe
+  no response     1 probe`, output)
+
+	_, err = client.FormatCDN(model.GetMeasurement{Type: "dns"}, model.Context{})
+	assert.EqualError(t, err, "err: the cdn format is not supported for dns measurements")
+}
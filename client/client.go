@@ -1,175 +1,200 @@
 package client
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/pkg/globalping"
 )
 
 const userAgent = "Globalping API Go Client / v1" + " (" + runtime.GOOS + "/" + runtime.GOARCH + ")"
 
 var ApiUrl = "https://api.globalping.io/v1/measurements"
 
-// Post measurement to Globalping API - boolean indicates whether to print CLI help on error
-func PostAPI(measurement model.PostMeasurement) (model.PostResponse, bool, error) {
-	// Format post data
+// Timeout of every request made to the API, zero means no timeout
+var Timeout time.Duration
+
+// SetBaseUrl points every endpoint to the given API base URL, e.g. https://api.globalping.io/v1
+func SetBaseUrl(base string) {
+	base = strings.TrimRight(base, "/")
+	ApiUrl = base + "/measurements"
+	ProbesApiUrl = base + "/probes"
+	LimitsApiUrl = base + "/limits"
+}
+
+// ApiToken is sent as a bearer token with every request if set, registered users get higher rate limits
+var ApiToken string
+
+// api returns the API client of an endpoint with the token, the retry policy and the HTTP client of the CLI.
+// endpointUrl is ApiUrl, ProbesApiUrl or LimitsApiUrl, the endpoint is removed from it to get the base URL
+func api(endpointUrl, endpoint string) *globalping.Client {
+	return globalping.NewClient(
+		globalping.WithBaseURL(strings.TrimSuffix(endpointUrl, "/"+endpoint)),
+		globalping.WithHTTPClient(debugClient(httpClient())),
+		globalping.WithToken(ApiToken),
+		globalping.WithUserAgent(userAgent),
+		globalping.WithRetry(Retry),
+		globalping.WithLogger(func(format string, args ...interface{}) {
+			Logf(LevelVerbose, format, args...)
+		}),
+	)
+}
+
+// MeasurementBody returns the JSON body posted to the API for a measurement
+func MeasurementBody(measurement model.PostMeasurement) ([]byte, error) {
 	postData, err := json.Marshal(measurement)
 	if err != nil {
-		return model.PostResponse{}, false, errors.New("err: failed to marshal post data - please report this bug")
+		return nil, errors.New("err: failed to marshal post data - please report this bug")
 	}
+	return postData, nil
+}
 
-	// Create a new request
-	req, err := http.NewRequest("POST", ApiUrl, bytes.NewBuffer(postData))
+// Post measurement to Globalping API, errors returned by the API are an *APIError
+func PostAPI(c context.Context, measurement model.PostMeasurement) (model.PostResponse, error) {
+	// Format post data
+	postData, err := MeasurementBody(measurement)
 	if err != nil {
-		return model.PostResponse{}, false, errors.New("err: failed to create request - please report this bug")
+		return model.PostResponse{}, err
 	}
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Content-Type", "application/json")
+	return postBody(c, postData, measurement.Options != nil && measurement.Options.IPVersion == 6)
+}
 
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return model.PostResponse{}, false, errors.New("err: request failed - please try again later")
+// PostRawAPI posts a measurement body as is, e.g. with options the CLI has no flags for yet
+func PostRawAPI(c context.Context, body []byte) (model.PostResponse, error) {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(body, &obj) != nil {
+		return model.PostResponse{}, errors.New("err: the measurement must be a JSON object")
 	}
-	defer resp.Body.Close()
-
-	// If an error is returned
-	if resp.StatusCode != http.StatusAccepted {
-		// Decode the response body as JSON
-		var data model.PostError
-
-		err = json.NewDecoder(resp.Body).Decode(&data)
-		if err != nil {
-			return model.PostResponse{}, false, errors.New("err: invalid error format returned - please report this bug")
-		}
-
-		// 422 error
-		if data.Error.Type == "no_probes_found" {
-			return model.PostResponse{}, true, errors.New("no suitable probes found - please choose a different location")
-		}
-
-		// 400 error
-		if data.Error.Type == "validation_error" {
-			for _, v := range data.Error.Params {
-				fmt.Printf("err: %s\n", v)
-			}
-			return model.PostResponse{}, true, errors.New("invalid parameters - please check the help for more information")
-		}
-
-		// 500 error
-		if data.Error.Type == "api_error" {
-			return model.PostResponse{}, false, errors.New("err: internal server error - please try again later")
-		}
-
-		// If the error type is unknown
-		return model.PostResponse{}, false, fmt.Errorf("err: unknown error response: %s", data.Error.Type)
+	var opts struct {
+		Options struct {
+			IPVersion int `json:"ipVersion"`
+		} `json:"measurementOptions"`
 	}
+	_ = json.Unmarshal(body, &opts)
+	return postBody(c, body, opts.Options.IPVersion == 6)
+}
 
-	// Read the response body
-	var data model.PostResponse
-	err = json.NewDecoder(resp.Body).Decode(&data)
+// Post the JSON body of a measurement, ipv6 explains the lack of probes with IPv6 support
+func postBody(c context.Context, postData []byte, ipv6 bool) (model.PostResponse, error) {
+	res, err := api(ApiUrl, "measurements").CreateMeasurementJSON(c, postData)
 	if err != nil {
-		fmt.Println(err)
-		return model.PostResponse{}, false, errors.New("err: invalid post measurement format returned - please report this bug")
+		return model.PostResponse{}, postError(err, ipv6)
 	}
-
-	return data, false, nil
+	if res.RateLimit.Set {
+		setLastRateLimit(res.RateLimit)
+	}
+	return res, nil
 }
 
-func DecodeTimings(cmd string, timings json.RawMessage) (model.Timings, error) {
-	var data model.Timings
-
-	if cmd == "ping" {
-		err := json.Unmarshal(timings, &data.Arr)
-		if err != nil {
-			return model.Timings{}, errors.New("invalid timings format returned (ping)")
-		}
-	} else {
-		err := json.Unmarshal(timings, &data.Interface)
-		if err != nil {
-			return model.Timings{}, errors.New("invalid timings format returned (other)")
+// Replace the message of an error of a posted measurement with the explanation of the CLI
+func postError(err error, ipv6 bool) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		if errors.Is(err, globalping.ErrInvalidResponse) {
+			return errors.New("err: invalid post measurement format returned - please report this bug")
 		}
+		return requestError(err, "err: request failed - please try again later")
+	}
+	if apiErr.RateLimit.Set {
+		setLastRateLimit(apiErr.RateLimit)
 	}
 
-	return data, nil
+	switch apiErr.Type {
+	// 429 error, the body is not needed to explain it
+	case ErrorTypeRateLimit:
+		apiErr.Message = rateLimitMessage(apiErr.RateLimit)
+	// 422 error
+	case ErrorTypeNoProbes:
+		apiErr.Message = "no suitable probes found - please choose a different location"
+		if ipv6 {
+			apiErr.Message = "no suitable probes with IPv6 support found - please choose a different location or remove -6"
+		}
+	// 400 error, the reason of every invalid field is in Params
+	case ErrorTypeValidation:
+		apiErr.Message = "invalid parameters - please check the help for more information"
+	// 500 error
+	case ErrorTypeApi:
+		apiErr.Message = "err: internal server error - please try again later"
+	// If the error type is unknown
+	default:
+		apiErr.Message = fmt.Sprintf("err: unknown error response: %s", apiErr.Type)
+	}
+	return apiErr
 }
 
 // Get measurement from Globalping API
-func GetAPI(id string) (model.GetMeasurement, error) {
-	// Create a new request
-	req, err := http.NewRequest("GET", ApiUrl+"/"+id, nil)
-	if err != nil {
-		return model.GetMeasurement{}, errors.New("err: failed to create request")
-	}
-	req.Header.Set("User-Agent", userAgent)
+func GetAPI(c context.Context, id string) (model.GetMeasurement, error) {
+	return newPoller(id).get(c)
+}
 
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return model.GetMeasurement{}, errors.New("err: request failed")
+// Replace the message of an error of a fetched measurement with the explanation of the CLI
+func getError(err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return requestError(err, "err: request failed")
 	}
-	defer resp.Body.Close()
 
+	switch {
 	// 404 not found
-	if resp.StatusCode == http.StatusNotFound {
-		return model.GetMeasurement{}, errors.New("err: measurement not found")
-	}
-
+	case apiErr.StatusCode == http.StatusNotFound:
+		apiErr.Type = ErrorTypeNotFound
+		apiErr.Message = "err: measurement not found"
 	// 500 error
-	if resp.StatusCode == http.StatusInternalServerError {
-		return model.GetMeasurement{}, errors.New("err: internal server error - please try again later")
+	case apiErr.StatusCode >= http.StatusInternalServerError:
+		apiErr.Type = ErrorTypeApi
+		apiErr.Message = "err: internal server error - please try again later"
+	default:
+		apiErr.Message = fmt.Sprintf("err: unknown error response: %s", apiErr.Type)
 	}
+	return apiErr
+}
 
-	// Read the response body
-	var data model.GetMeasurement
-	err = json.NewDecoder(resp.Body).Decode(&data)
+// Poll the API until the measurement is complete, or until Wait is over in which case the partial results are
+// returned with the in-progress status
+func WaitForResults(c context.Context, id string) (model.GetMeasurement, error) {
+	p := newPoller(id)
+	data, err := p.get(c)
 	if err != nil {
-		return model.GetMeasurement{}, errors.New("invalid get measurement format returned")
+		return model.GetMeasurement{}, err
+	}
+
+	for data.Status == "in-progress" && !p.expired() {
+		if err := p.wait(c); err != nil {
+			return model.GetMeasurement{}, err
+		}
+		data, err = p.get(c)
+		if err != nil {
+			return model.GetMeasurement{}, err
+		}
 	}
 
 	return data, nil
 }
 
-func GetApiJson(id string) (string, error) {
-	// Create a new request
-	req, err := http.NewRequest("GET", ApiUrl+"/"+id, nil)
-	if err != nil {
-		return "", errors.New("err: failed to create request")
+// GetApiJson returns the JSON of a measurement as sent by the API, finished measurements are read from the cache
+func GetApiJson(c context.Context, id string) (string, error) {
+	if raw, ok := storedJson(id); ok {
+		return string(raw), nil
 	}
-	req.Header.Set("User-Agent", userAgent)
 
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	raw, _, err := api(ApiUrl, "measurements").GetMeasurementJSON(c, id, "")
 	if err != nil {
-		return "", errors.New("err: request failed")
+		return "", getError(err)
 	}
-	defer resp.Body.Close()
 
-	// 404 not found
-	if resp.StatusCode == http.StatusNotFound {
-		return "", errors.New("err: measurement not found")
-	}
-
-	// 500 error
-	if resp.StatusCode == http.StatusInternalServerError {
-		return "", errors.New("err: internal server error - please try again later")
+	var status struct {
+		Status string `json:"status"`
 	}
-
-	// Read the response body
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", errors.New("err: failed to read response body")
+	if json.Unmarshal(raw, &status) == nil {
+		cacheFinished(id, raw, status.Status)
 	}
-	respString := string(respBytes)
 
-	return respString, nil
+	return string(raw), nil
 }
@@ -1,11 +1,13 @@
 package client_test
 
 import (
-	"encoding/json"
+	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/jsdelivr/globalping-cli/client"
 	"github.com/jsdelivr/globalping-cli/model"
@@ -55,11 +57,10 @@ func testPostValid(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	res, showHelp, err := client.PostAPI(opts)
+	res, err := client.PostAPI(context.Background(), opts)
 
 	assert.Equal(t, "abcd", res.ID)
 	assert.Equal(t, 1, res.ProbesCount)
-	assert.False(t, showHelp)
 	assert.NoError(t, err)
 }
 
@@ -72,9 +73,12 @@ func testPostNoProbes(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	_, showHelp, err := client.PostAPI(opts)
+	_, err := client.PostAPI(context.Background(), opts)
 	assert.EqualError(t, err, "no suitable probes found - please choose a different location")
-	assert.True(t, showHelp)
+	assert.True(t, client.IsUsageError(err))
+
+	_, err = client.PostAPI(context.Background(), model.PostMeasurement{Options: &model.MeasurementOptions{IPVersion: 6}})
+	assert.EqualError(t, err, "no suitable probes with IPv6 support found - please choose a different location or remove -6")
 }
 
 func testPostValidation(t *testing.T) {
@@ -90,9 +94,15 @@ func testPostValidation(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	_, showHelp, err := client.PostAPI(opts)
+	_, err := client.PostAPI(context.Background(), opts)
 	assert.EqualError(t, err, "invalid parameters - please check the help for more information")
-	assert.True(t, showHelp)
+	assert.True(t, client.IsUsageError(err))
+
+	var apiErr *client.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 400, apiErr.StatusCode)
+	assert.Equal(t, client.ErrorTypeValidation, apiErr.Type)
+	assert.Equal(t, `"target" does not match any of the allowed types`, apiErr.Params["target"])
 }
 
 func testPostInternalError(t *testing.T) {
@@ -104,9 +114,9 @@ func testPostInternalError(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	_, showHelp, err := client.PostAPI(opts)
+	_, err := client.PostAPI(context.Background(), opts)
 	assert.EqualError(t, err, "err: internal server error - please try again later")
-	assert.False(t, showHelp)
+	assert.False(t, client.IsUsageError(err))
 }
 
 // GetAPI tests
@@ -131,7 +141,7 @@ func testGetValid(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	res, err := client.GetAPI("abcd")
+	res, err := client.GetAPI(context.Background(), "abcd")
 	if err != nil {
 		t.Error(err)
 	}
@@ -146,7 +156,7 @@ func testGetJson(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	res, err := client.GetApiJson("abcd")
+	res, err := client.GetApiJson(context.Background(), "abcd")
 	if err != nil {
 		t.Error(err)
 	}
@@ -200,7 +210,7 @@ func testGetPing(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	res, err := client.GetAPI("abcd")
+	res, err := client.GetAPI(context.Background(), "abcd")
 	if err != nil {
 		t.Error(err)
 	}
@@ -224,13 +234,7 @@ func testGetPing(t *testing.T) {
 
 	assert.Equal(t, "PING", res.Results[0].Result.RawOutput)
 	assert.Equal(t, "1.1.1.1", res.Results[0].Result.ResolvedAddress)
-	assert.Equal(t, 27.088, res.Results[0].Result.Stats["avg"])
-	assert.Equal(t, 28.193, res.Results[0].Result.Stats["max"])
-	assert.Equal(t, 24.891, res.Results[0].Result.Stats["min"])
-	assert.Equal(t, float64(3), res.Results[0].Result.Stats["total"])
-	assert.Equal(t, float64(3), res.Results[0].Result.Stats["rcv"])
-	assert.Equal(t, float64(0), res.Results[0].Result.Stats["loss"])
-	assert.Equal(t, float64(0), res.Results[0].Result.Stats["drop"])
+	assert.Equal(t, &model.PingStats{Min: ms(24.891), Avg: ms(27.088), Max: ms(28.193), Total: 3, Rcv: 3}, res.Results[0].Result.Stats)
 }
 
 func testGetTraceroute(t *testing.T) {
@@ -293,7 +297,7 @@ func testGetTraceroute(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	res, err := client.GetAPI("abcd")
+	res, err := client.GetAPI(context.Background(), "abcd")
 	if err != nil {
 		t.Error(err)
 	}
@@ -369,7 +373,7 @@ func testGetDns(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	res, err := client.GetAPI("abcd")
+	res, err := client.GetAPI(context.Background(), "abcd")
 	if err != nil {
 		t.Error(err)
 	}
@@ -393,12 +397,7 @@ func testGetDns(t *testing.T) {
 
 	assert.Equal(t, "DNS", res.Results[0].Result.RawOutput)
 	assert.Equal(t, "finished", res.Results[0].Result.Status)
-	assert.IsType(t, json.RawMessage{}, res.Results[0].Result.TimingsRaw)
-
-	// Test timings
-	timings, _ := client.DecodeTimings("dns", res.Results[0].Result.TimingsRaw)
-	assert.Equal(t, float64(15), timings.Interface["total"])
-	assert.Nil(t, timings.Arr)
+	assert.Equal(t, &model.Timings{Total: ms(15)}, res.Results[0].Result.Timings)
 }
 
 func testGetMtr(t *testing.T) {
@@ -492,7 +491,7 @@ func testGetMtr(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	res, err := client.GetAPI("abcd")
+	res, err := client.GetAPI(context.Background(), "abcd")
 	if err != nil {
 		t.Error(err)
 	}
@@ -516,7 +515,15 @@ func testGetMtr(t *testing.T) {
 
 	assert.Equal(t, "MTR", res.Results[0].Result.RawOutput)
 	assert.Equal(t, "finished", res.Results[0].Result.Status)
-	assert.IsType(t, json.RawMessage{}, res.Results[0].Result.TimingsRaw)
+	assert.Nil(t, res.Results[0].Result.Timings)
+
+	// Test hops
+	hops := res.Results[0].Result.Hops
+	assert.Len(t, hops, 2)
+	assert.Equal(t, "172.19.66.225", hops[0].ResolvedAddress)
+	assert.Equal(t, model.MtrStats{Min: 0.176, Avg: 0.2, Max: 0.226, JMax: 0.2, JAvg: 0.1, Total: 3, Rcv: 3}, *hops[0].Stats)
+	assert.Equal(t, []float64{0.176, 0.216, 0.226}, hops[0].Timings.RTTs())
+	assert.Equal(t, []int{199524}, hops[1].ASN)
 }
 
 func testGetHttp(t *testing.T) {
@@ -597,7 +604,7 @@ func testGetHttp(t *testing.T) {
 	defer server.Close()
 	client.ApiUrl = server.URL
 
-	res, err := client.GetAPI("abcd")
+	res, err := client.GetAPI(context.Background(), "abcd")
 	if err != nil {
 		t.Error(err)
 	}
@@ -621,15 +628,106 @@ func testGetHttp(t *testing.T) {
 
 	assert.Equal(t, "HTTP", res.Results[0].Result.RawOutput)
 	assert.Equal(t, "finished", res.Results[0].Result.Status)
-	assert.IsType(t, json.RawMessage{}, res.Results[0].Result.TimingsRaw)
-
-	// Test timings
-	timings, _ := client.DecodeTimings("dns", res.Results[0].Result.TimingsRaw)
-	assert.Nil(t, timings.Arr)
-	assert.Equal(t, float64(583), timings.Interface["total"])
-	assert.Equal(t, float64(18), timings.Interface["download"])
-	assert.Equal(t, float64(450), timings.Interface["firstByte"])
-	assert.Equal(t, float64(24), timings.Interface["dns"])
-	assert.Equal(t, float64(70), timings.Interface["tls"])
-	assert.Equal(t, float64(19), timings.Interface["tcp"])
+	assert.Equal(t, &model.Timings{Total: ms(583), DNS: ms(24), TCP: ms(19), TLS: ms(70), FirstByte: ms(450), Download: ms(18)}, res.Results[0].Result.Timings)
+}
+
+func TestApiToken(t *testing.T) {
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		w.Write([]byte(`{"id":"abcd"}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	_, err := client.GetAPI(context.Background(), "abcd")
+	assert.NoError(t, err)
+	assert.Equal(t, "", authHeader)
+
+	client.ApiToken = "secret"
+	defer func() { client.ApiToken = "" }()
+
+	_, err = client.GetAPI(context.Background(), "abcd")
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer secret", authHeader)
+}
+
+func TestWaitForResultsCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"abcd","status":"in-progress"}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	c, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := client.WaitForResults(c, "abcd")
+	assert.Equal(t, client.ErrInterrupted, err)
+}
+
+func TestGetAPITimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	c, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetAPI(c, "abcd")
+	assert.Equal(t, client.ErrTimeout, err)
+}
+
+// The dry run prints the body posted to the API
+func TestMeasurementBody(t *testing.T) {
+	m := model.PostMeasurement{
+		Type:              "ping",
+		Target:            "jsdelivr.com",
+		Limit:             2,
+		Locations:         []model.Locations{{Magic: "Germany", Limit: 2}},
+		Options:           &model.MeasurementOptions{Packets: 5},
+		InProgressUpdates: true,
+	}
+	body, err := client.MeasurementBody(m)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"limit":2,"locations":[{"magic":"Germany","limit":2}],"type":"ping","target":"jsdelivr.com","measurementOptions":{"packets":5},"inProgressUpdates":true}`, string(body))
+
+	var posted []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"abcd","probesCount":2}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	_, err = client.PostAPI(context.Background(), m)
+	assert.NoError(t, err)
+	assert.Equal(t, string(body), string(posted))
+}
+
+func TestPostRawAPI(t *testing.T) {
+	var posted []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	// Options unknown to the CLI are posted as is
+	body := `{"type":"ping","target":"jsdelivr.com","measurementOptions":{"newOption":true}}`
+	res, err := client.PostRawAPI(context.Background(), []byte(body))
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", res.ID)
+	assert.Equal(t, body, string(posted))
+
+	_, err = client.PostRawAPI(context.Background(), []byte(`[1]`))
+	assert.EqualError(t, err, "err: the measurement must be a JSON object")
 }
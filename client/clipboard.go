@@ -0,0 +1,65 @@
+package client
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Artifacts copied to the clipboard with --copy
+const (
+	CopyUrl      = "url"
+	CopyJson     = "json"
+	CopyMarkdown = "markdown"
+)
+
+// CopyArtifacts are the values accepted by --copy
+var CopyArtifacts = []string{CopyUrl, CopyJson, CopyMarkdown}
+
+// Commands writing their stdin to the clipboard of every OS, the first installed one is used
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux":   {{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}},
+}
+
+// ClipboardCommand returns the command writing to the clipboard on goos, ok is false if none is installed
+func ClipboardCommand(goos string, lookPath func(string) (string, error)) (cmd []string, ok bool) {
+	candidates, found := clipboardCommands[goos]
+	if !found {
+		// Other unixes use the same tools as linux
+		candidates = clipboardCommands["linux"]
+	}
+	for _, c := range candidates {
+		if _, err := lookPath(c[0]); err == nil {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// WriteClipboard copies text to the system clipboard, replaced in tests
+var WriteClipboard = writeSystemClipboard
+
+// writeSystemClipboard runs the clipboard command of the OS. Without one, e.g. over SSH, the terminal is asked to
+// copy the text with the OSC 52 escape sequence
+func writeSystemClipboard(text string) error {
+	cmd, ok := ClipboardCommand(runtime.GOOS, exec.LookPath)
+	if !ok {
+		o, err := os.Stdout.Stat()
+		if err != nil || o.Mode()&os.ModeCharDevice != os.ModeCharDevice {
+			return errors.New("err: no clipboard available - install wl-copy, xclip or xsel")
+		}
+		CopyToClipboard(text)
+		return nil
+	}
+
+	c := exec.Command(cmd[0], cmd[1:]...)
+	c.Stdin = strings.NewReader(text)
+	if err := c.Run(); err != nil {
+		return errors.New("err: failed to copy to the clipboard with " + cmd[0])
+	}
+	return nil
+}
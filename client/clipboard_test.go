@@ -0,0 +1,42 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClipboardCommand(t *testing.T) {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	cmd, ok := client.ClipboardCommand("darwin", installed("pbcopy"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"pbcopy"}, cmd)
+
+	cmd, ok = client.ClipboardCommand("windows", installed("clip"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"clip"}, cmd)
+
+	cmd, ok = client.ClipboardCommand("linux", installed("xsel", "xclip"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"xclip", "-selection", "clipboard"}, cmd)
+
+	cmd, ok = client.ClipboardCommand("freebsd", installed("xsel"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"xsel", "--clipboard", "--input"}, cmd)
+
+	_, ok = client.ClipboardCommand("linux", installed())
+	assert.False(t, ok)
+}
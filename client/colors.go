@@ -0,0 +1,59 @@
+package client
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/pterm/pterm"
+)
+
+var (
+	// Latencies below LatencyGood milliseconds are shown in green, below LatencyWarn in yellow and above in red
+	LatencyGood = 50.0
+	LatencyWarn = 150.0
+
+	good   = lipgloss.NewStyle().Foreground(lipgloss.Color("#17D4A7"))
+	warn   = lipgloss.NewStyle().Foreground(lipgloss.Color("#F5A623"))
+	bad    = lipgloss.NewStyle().Foreground(lipgloss.Color("#E5484D"))
+	failed = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#E5484D"))
+	dimmed = lipgloss.NewStyle().Faint(true)
+)
+
+// ColorEnabled returns false if colors are disabled with noColor or the NO_COLOR environment variable,
+// or if stdout is not a terminal
+func ColorEnabled(noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	o, err := os.Stdout.Stat()
+	return err == nil && o.Mode()&os.ModeCharDevice == os.ModeCharDevice
+}
+
+// SetColor enables or disables the colors of every view
+func SetColor(enabled bool) {
+	if enabled {
+		lipgloss.SetColorProfile(termenv.ColorProfile())
+		pterm.EnableColor()
+		return
+	}
+	lipgloss.SetColorProfile(termenv.Ascii)
+	pterm.DisableColor()
+}
+
+// Render a latency in milliseconds in the color of its severity, values that are not numbers are left as is
+func colorLatency(v interface{}, format string) string {
+	text := fmt.Sprintf(format, v)
+	ms, ok := v.(float64)
+	if !ok {
+		return text
+	}
+	switch {
+	case ms < LatencyGood:
+		return good.Render(text)
+	case ms < LatencyWarn:
+		return warn.Render(text)
+	}
+	return bad.Render(text)
+}
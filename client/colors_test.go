@@ -0,0 +1,34 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorEnabled(t *testing.T) {
+	assert.False(t, ColorEnabled(true))
+
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, ColorEnabled(false))
+
+	// Stdout of the tests is not a terminal
+	t.Setenv("NO_COLOR", "")
+	assert.False(t, ColorEnabled(false))
+}
+
+func TestColorLatency(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { SetColor(false) })
+
+	assert.Equal(t, good.Render("10 ms"), colorLatency(10.0, "%v ms"))
+	assert.Equal(t, warn.Render("50 ms"), colorLatency(50.0, "%v ms"))
+	assert.Equal(t, bad.Render("150.500 ms"), colorLatency(150.5, "%.3f ms"))
+	assert.NotEqual(t, good.Render("10 ms"), bad.Render("10 ms"))
+	assert.Equal(t, "<nil> ms", colorLatency(nil, "%v ms"))
+
+	SetColor(false)
+	assert.Equal(t, "10 ms", colorLatency(10.0, "%v ms"))
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// KeyMetric returns the main latency of a result in milliseconds: the average rtt for ping and the total time for dns and http.
+// Other measurement types have no single latency value.
+func KeyMetric(cmd string, result model.MeasurementResponse) (float64, bool) {
+	switch cmd {
+	case "ping":
+		if result.Result.Stats == nil {
+			return 0, false
+		}
+		return msValue(result.Result.Stats.Avg)
+	case "dns", "http":
+		return result.Result.Timings.TotalMs()
+	}
+	return 0, false
+}
+
+// Formatted key metric of a result with the packet loss for ping, falling back to its status
+func metricCell(cmd string, result model.MeasurementResponse) string {
+	if v, ok := KeyMetric(cmd, result); ok {
+		if loss, ok := pingLoss(result); ok {
+			return fmt.Sprintf("%.2f ms, %v%% loss", v, loss)
+		}
+		return fmt.Sprintf("%.2f ms", v)
+	}
+	if result.Result.Status != "" {
+		return result.Result.Status
+	}
+	return "-"
+}
+
+// CompareResults renders a table aligning the results of two measurements by probe, with the latency delta between both runs
+func CompareResults(cmd string, a, b model.GetMeasurement, aTitle, bTitle string) string {
+	var order []string
+	left := map[string]model.MeasurementResponse{}
+	right := map[string]model.MeasurementResponse{}
+
+	for _, result := range a.Results {
+		label := probeLabel(result.Probe)
+		if _, ok := left[label]; !ok {
+			order = append(order, label)
+		}
+		left[label] = result
+	}
+	for _, result := range b.Results {
+		label := probeLabel(result.Probe)
+		_, inLeft := left[label]
+		_, inRight := right[label]
+		if !inLeft && !inRight {
+			order = append(order, label)
+		}
+		right[label] = result
+	}
+
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "PROBE\t%s\t%s\tDELTA\n", aTitle, bTitle)
+
+	for _, label := range order {
+		l, hasLeft := left[label]
+		r, hasRight := right[label]
+
+		leftCell, rightCell, delta := "-", "-", "-"
+		if hasLeft {
+			leftCell = metricCell(cmd, l)
+		}
+		if hasRight {
+			rightCell = metricCell(cmd, r)
+		}
+		if hasLeft && hasRight {
+			lv, lok := KeyMetric(cmd, l)
+			rv, rok := KeyMetric(cmd, r)
+			if lok && rok {
+				delta = fmt.Sprintf("%+.2f ms", rv-lv)
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", label, leftCell, rightCell, delta)
+	}
+
+	w.Flush()
+	return strings.TrimSpace(output.String())
+}
@@ -0,0 +1,54 @@
+package client_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pingResult(city string, avg float64) model.MeasurementResponse {
+	return model.MeasurementResponse{
+		Probe:  model.ProbeData{City: city, Country: "DE", ASN: 1},
+		Result: model.ResultData{Status: "finished", Stats: &model.PingStats{Avg: ms(avg)}},
+	}
+}
+
+func ms(v float64) *float64 {
+	return &v
+}
+
+// Hops decoded from the JSON returned by the API
+func hops(raw string) []model.Hop {
+	var h []model.Hop
+	if err := json.Unmarshal([]byte(raw), &h); err != nil {
+		panic(err)
+	}
+	return h
+}
+
+func TestCompareResults(t *testing.T) {
+	a := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 10), pingResult("Munich", 20)}}
+	b := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Munich", 15.5), pingResult("Hamburg", 5)}}
+
+	assert.Equal(t, `PROBE               A                  B                  DELTA
+Berlin, DE, ASN:1   10.00 ms, 0% loss  -                  -
+Munich, DE, ASN:1   20.00 ms, 0% loss  15.50 ms, 0% loss  -4.50 ms
+Hamburg, DE, ASN:1  -                  5.00 ms, 0% loss   -`, client.CompareResults("ping", a, b, "A", "B"))
+}
+
+func TestKeyMetric(t *testing.T) {
+	v, ok := client.KeyMetric("ping", pingResult("Berlin", 10))
+	assert.True(t, ok)
+	assert.Equal(t, 10.0, v)
+
+	v, ok = client.KeyMetric("http", model.MeasurementResponse{Result: model.ResultData{Timings: &model.Timings{Total: ms(42)}}})
+	assert.True(t, ok)
+	assert.Equal(t, 42.0, v)
+
+	_, ok = client.KeyMetric("traceroute", model.MeasurementResponse{})
+	assert.False(t, ok)
+}
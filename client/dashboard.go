@@ -0,0 +1,324 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"atomicgo.dev/keyboard"
+	"atomicgo.dev/keyboard/keys"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/pterm/pterm"
+)
+
+var (
+	pane     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#17D4A7"))
+	selected = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#17D4A7"))
+	dim      = lipgloss.NewStyle().Faint(true)
+)
+
+// Dashboard is the state of the interactive terminal UI showing a live measurement
+type Dashboard struct {
+	mu       sync.Mutex
+	ctx      model.Context
+	id       string
+	data     model.GetMeasurement
+	selected int
+	// latency switches the output pane from the raw output to the stats of the probe
+	latency bool
+	status  string
+}
+
+func NewDashboard(ctx model.Context) *Dashboard {
+	return &Dashboard{ctx: ctx, latency: ctx.Latency}
+}
+
+// Update replaces the displayed measurement
+func (d *Dashboard) Update(id string, data model.GetMeasurement) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.id = id
+	d.data = data
+	if d.selected >= len(data.Results) {
+		d.selected = 0
+	}
+}
+
+// HandleKey applies a key binding, returns true if the dashboard should be closed or the measurement run again
+func (d *Dashboard) HandleKey(key keys.Key) (quit bool, rerun bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch key.Code {
+	case keys.CtrlC, keys.Escape:
+		return true, false
+	case keys.Up:
+		if d.selected > 0 {
+			d.selected--
+		}
+	case keys.Down:
+		if d.selected < len(d.data.Results)-1 {
+			d.selected++
+		}
+	case keys.RuneKey:
+		switch key.String() {
+		case "q":
+			return true, false
+		case "k":
+			if d.selected > 0 {
+				d.selected--
+			}
+		case "j":
+			if d.selected < len(d.data.Results)-1 {
+				d.selected++
+			}
+		case "r":
+			d.status = "Running the measurement again..."
+			return false, true
+		case "f":
+			d.latency = !d.latency
+		case "c":
+			if d.id != "" {
+				CopyToClipboard(ShareUrl(d.id))
+				d.status = "Copied " + ShareUrl(d.id)
+			}
+		}
+	}
+	return false, false
+}
+
+// SetStatus sets the message shown at the bottom of the dashboard
+func (d *Dashboard) SetStatus(status string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status = status
+}
+
+// Render draws the probe list, the output of the selected probe and the aggregate stats in a w x h terminal
+func (d *Dashboard) Render(w, h int) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cmd := measurementType(d.data, d.ctx)
+
+	// Borders take 2 columns and 2 lines per pane, the footer 4 lines
+	listW := w / 3
+	outW := w - listW - 4
+	listW -= 4
+	paneH := h - 2 - 4 - 2
+	if listW < 10 || outW < 10 || paneH < 3 {
+		return "Terminal too small for the dashboard"
+	}
+
+	// Probe list
+	var list []string
+	for i, result := range d.data.Results {
+		line := probeLabel(result.Probe)
+		if v, ok := KeyMetric(cmd, result); ok {
+			line += fmt.Sprintf(" %.1f ms", v)
+		} else if result.Result.Status != "" {
+			line += " " + result.Result.Status
+		}
+		line = truncate(line, listW-2)
+		if i == d.selected {
+			list = append(list, selected.Render("> "+line))
+		} else {
+			list = append(list, "  "+line)
+		}
+	}
+	if len(list) > paneH {
+		start := d.selected - paneH + 1
+		if start < 0 {
+			start = 0
+		}
+		list = list[start : start+paneH]
+	}
+
+	// Output of the selected probe
+	output := "Waiting for results..."
+	if d.selected < len(d.data.Results) {
+		result := d.data.Results[d.selected]
+		if d.latency {
+			output = statsOutput(cmd, result)
+		} else {
+			output = strings.TrimSpace(result.Result.RawOutput)
+		}
+	}
+	output = sliceOutput(output, outW, paneH+2)
+
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		pane.Width(listW).Height(paneH).Render(strings.Join(list, "\n")),
+		pane.Width(outW).Height(paneH).Render(output),
+	)
+
+	view := "raw"
+	if d.latency {
+		view = "latency"
+	}
+	footer := []string{
+		truncate(fmt.Sprintf("%s %s from %s | %s | %s", cmd, d.ctx.Target, d.ctx.From, d.data.Status, aggregateLine(cmd, d.data)), w),
+		truncate(d.status, w),
+		dim.Render(truncate(fmt.Sprintf("↑/↓ select  r re-run  f format (%s)  c copy share URL  q quit", view), w)),
+	}
+
+	return panes + "\n" + strings.Join(footer, "\n")
+}
+
+// One line aggregate of every probe of the measurement
+func aggregateLine(cmd string, data model.GetMeasurement) string {
+	finished := 0
+	var values []float64
+	for _, result := range data.Results {
+		if result.Result.Status == "finished" {
+			finished++
+		}
+		if v, ok := KeyMetric(cmd, result); ok {
+			values = append(values, v)
+		}
+	}
+
+	line := fmt.Sprintf("%d/%d probes finished", finished, len(data.Results))
+	if len(values) > 0 {
+		sort.Float64s(values)
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		line += fmt.Sprintf(" | min/avg/max %.1f/%.1f/%.1f ms", values[0], sum/float64(len(values)), values[len(values)-1])
+	}
+	return line
+}
+
+// Stats and timings of a result, one value per line
+func statsOutput(cmd string, result model.MeasurementResponse) string {
+	values := map[string]interface{}{}
+	if s := result.Result.Stats; s != nil {
+		for k, v := range map[string]*float64{"min": s.Min, "avg": s.Avg, "max": s.Max} {
+			values[k] = msOrNil(v)
+		}
+		values["total"] = s.Total
+		values["rcv"] = s.Rcv
+		values["drop"] = s.Drop
+		values["loss"] = s.Loss
+	}
+	if cmd == "ping" {
+		if rtt, ok := PingRttStats(result); ok {
+			values["p50"] = rtt.P50
+			values["p90"] = rtt.P90
+			values["p99"] = rtt.P99
+			values["jitter"] = rtt.Jitter
+		}
+	} else {
+		for k, v := range result.Result.Timings.Phases() {
+			if v != nil {
+				values[k] = *v
+			}
+		}
+	}
+	if len(values) == 0 {
+		return "No stats available"
+	}
+
+	names := make([]string, 0, len(values))
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for i, k := range names {
+		lines[i] = fmt.Sprintf("%s: %v", k, values[k])
+	}
+	return strings.Join(lines, "\n")
+}
+
+func truncate(s string, w int) string {
+	r := []rune(s)
+	if len(r) > w {
+		return string(r[:w])
+	}
+	return s
+}
+
+// RunDashboard displays the measurement returned by post in the interactive dashboard until the user quits,
+// post is called again when the user asks to re-run the measurement
+func RunDashboard(c context.Context, ctx model.Context, post func() (string, error)) error {
+	d := NewDashboard(ctx)
+
+	id, err := post()
+	if err != nil {
+		return err
+	}
+
+	area, _ := pterm.DefaultArea.WithFullscreen().Start()
+	defer area.Stop()
+
+	// The area is redrawn from the poller and the keyboard callback
+	var drawMu sync.Mutex
+	redraw := func() {
+		drawMu.Lock()
+		defer drawMu.Unlock()
+		w, h, _ := pterm.GetTerminalSize()
+		area.Update(d.Render(w, h))
+	}
+
+	// Poll the current measurement, every re-run cancels the poller of the previous one
+	var genMu sync.Mutex
+	cancelGen := func() {}
+	watch := func(genCtx context.Context, id string) {
+		if genCtx.Err() != nil {
+			return
+		}
+		d.Update(id, model.GetMeasurement{})
+		redraw()
+		// Read the stream until it is closed, even once cancelled, so its goroutine can exit
+		for update := range StreamResults(genCtx, id) {
+			if genCtx.Err() != nil {
+				continue
+			}
+			if update.Err != nil {
+				d.SetStatus(update.Err.Error())
+			} else {
+				d.Update(id, update.Data)
+			}
+			redraw()
+		}
+	}
+	start := func(id string) {
+		genCtx, cancel := context.WithCancel(c)
+		genMu.Lock()
+		cancelGen()
+		cancelGen = cancel
+		genMu.Unlock()
+		go watch(genCtx, id)
+	}
+	defer func() {
+		genMu.Lock()
+		cancelGen()
+		genMu.Unlock()
+	}()
+	start(id)
+
+	return keyboard.Listen(func(key keys.Key) (bool, error) {
+		quit, rerun := d.HandleKey(key)
+		if quit {
+			return true, nil
+		}
+		if rerun {
+			redraw()
+			id, err := post()
+			if err != nil {
+				d.SetStatus(err.Error())
+			} else {
+				d.SetStatus("")
+				start(id)
+			}
+		}
+		redraw()
+		return false, nil
+	})
+}
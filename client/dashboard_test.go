@@ -0,0 +1,47 @@
+package client_test
+
+import (
+	"testing"
+
+	"atomicgo.dev/keyboard/keys"
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardRender(t *testing.T) {
+	d := client.NewDashboard(model.Context{Cmd: "ping", Target: "google.com", From: "Germany"})
+
+	berlin := pingResult("Berlin", 10)
+	berlin.Result.RawOutput = "PING google.com from Berlin"
+	munich := pingResult("Munich", 20)
+	munich.Result.RawOutput = "PING google.com from Munich"
+	d.Update("abcd", model.GetMeasurement{Type: "ping", Status: "finished", Results: []model.MeasurementResponse{berlin, munich}})
+
+	out := d.Render(100, 20)
+	assert.Contains(t, out, "> Berlin, DE, ASN:1 10.0 ms")
+	assert.Contains(t, out, "  Munich, DE, ASN:1 20.0 ms")
+	assert.Contains(t, out, "PING google.com from Berlin")
+	assert.Contains(t, out, "ping google.com from Germany | finished | 2/2 probes finished | min/avg/max 10.0/15.0/20.0 ms")
+
+	quit, rerun := d.HandleKey(keys.Key{Code: keys.Down})
+	assert.False(t, quit)
+	assert.False(t, rerun)
+	out = d.Render(100, 20)
+	assert.Contains(t, out, "> Munich, DE, ASN:1 20.0 ms")
+	assert.Contains(t, out, "PING google.com from Munich")
+
+	d.HandleKey(keys.Key{Code: keys.RuneKey, Runes: []rune{'f'}})
+	out = d.Render(100, 20)
+	assert.Contains(t, out, "avg: 20")
+	assert.Contains(t, out, "f format (latency)")
+
+	_, rerun = d.HandleKey(keys.Key{Code: keys.RuneKey, Runes: []rune{'r'}})
+	assert.True(t, rerun)
+
+	quit, _ = d.HandleKey(keys.Key{Code: keys.RuneKey, Runes: []rune{'q'}})
+	assert.True(t, quit)
+
+	assert.Equal(t, "Terminal too small for the dashboard", d.Render(20, 5))
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// DiagnoseSteps are the measurement types run by diagnose, in the order they are run
+var DiagnoseSteps = []string{"ping", "traceroute", "dns", "http"}
+
+// Outcome of one diagnose step for a probe
+type diagnoseCell struct {
+	text string
+	ok   bool
+}
+
+// Outcome of the dns step, failed when no record is returned
+func diagnoseDns(result model.MeasurementResponse) diagnoseCell {
+	if result.Result.Status != "finished" {
+		return diagnoseCell{"FAIL (" + result.Result.Status + ")", false}
+	}
+	if len(result.Result.Answers) == 0 {
+		return diagnoseCell{"FAIL (no answer)", false}
+	}
+	if v, ok := KeyMetric("dns", result); ok {
+		return diagnoseCell{fmt.Sprintf("ok (%.0f ms)", v), true}
+	}
+	return diagnoseCell{"ok", true}
+}
+
+// Outcome of the ping step, failed when every packet is lost
+func diagnosePing(result model.MeasurementResponse) diagnoseCell {
+	loss, hasLoss := pingLoss(result)
+	if result.Result.Status != "finished" || !hasLoss {
+		return diagnoseCell{"FAIL (" + result.Result.Status + ")", false}
+	}
+	if loss >= 100 {
+		return diagnoseCell{"FAIL (100% loss)", false}
+	}
+	avg, _ := KeyMetric("ping", result)
+	return diagnoseCell{fmt.Sprintf("%.1f ms, %v%% loss", avg, loss), true}
+}
+
+// Outcome of the traceroute step, failed when the last hop is not the target
+func diagnoseTraceroute(result model.MeasurementResponse) diagnoseCell {
+	hops := result.Result.Hops
+	if result.Result.Status != "finished" || len(hops) == 0 {
+		return diagnoseCell{"FAIL (" + result.Result.Status + ")", false}
+	}
+	last := hops[len(hops)-1]
+	if result.Result.ResolvedAddress != "" && last.ResolvedAddress != result.Result.ResolvedAddress {
+		return diagnoseCell{fmt.Sprintf("stops at hop %d", len(hops)), false}
+	}
+	return diagnoseCell{fmt.Sprintf("%d hops", len(hops)), true}
+}
+
+// Outcome of the http step, failed without a response or with an error status code
+func diagnoseHttp(result model.MeasurementResponse) diagnoseCell {
+	if result.Result.Status != "finished" || result.Result.StatusCode == 0 {
+		return diagnoseCell{"FAIL (" + result.Result.Status + ")", false}
+	}
+	text := fmt.Sprint(result.Result.StatusCode)
+	if v, ok := KeyMetric("http", result); ok {
+		text += fmt.Sprintf(" (%.0f ms)", v)
+	}
+	if result.Result.StatusCode >= 400 {
+		return diagnoseCell{"FAIL " + text, false}
+	}
+	return diagnoseCell{text, true}
+}
+
+// diagnoseVerdict names the layer where the first failure occurs, from the bottom of the stack
+func diagnoseVerdict(cells map[string]diagnoseCell) string {
+	dns, hasDns := cells["dns"]
+	ping, hasPing := cells["ping"]
+	trace, hasTrace := cells["traceroute"]
+	http, hasHttp := cells["http"]
+
+	switch {
+	case hasDns && !dns.ok:
+		return "DNS resolution fails"
+	case hasHttp && http.ok:
+		if hasPing && !ping.ok {
+			return "OK (ICMP is filtered)"
+		}
+		return "OK"
+	case hasPing && !ping.ok:
+		if hasTrace && !trace.ok {
+			return "No connectivity, the path " + trace.text
+		}
+		return "No connectivity"
+	case hasHttp:
+		return "HTTP layer fails"
+	}
+	return "OK"
+}
+
+// FormatDiagnosis renders one row per probe with the outcome of every diagnose step and the layer where it fails
+func FormatDiagnosis(results map[string]model.GetMeasurement) string {
+	var order []string
+	probes := map[string]map[string]diagnoseCell{}
+
+	for _, step := range DiagnoseSteps {
+		data, ok := results[step]
+		if !ok {
+			continue
+		}
+		for _, result := range data.Results {
+			label := probeLabel(result.Probe)
+			if _, ok := probes[label]; !ok {
+				order = append(order, label)
+				probes[label] = map[string]diagnoseCell{}
+			}
+
+			switch step {
+			case "ping":
+				probes[label][step] = diagnosePing(result)
+			case "traceroute":
+				probes[label][step] = diagnoseTraceroute(result)
+			case "dns":
+				probes[label][step] = diagnoseDns(result)
+			case "http":
+				probes[label][step] = diagnoseHttp(result)
+			}
+		}
+	}
+
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROBE\tDNS\tPING\tTRACEROUTE\tHTTP\tDIAGNOSIS")
+
+	for _, label := range order {
+		cells := probes[label]
+		row := []string{label}
+		for _, step := range []string{"dns", "ping", "traceroute", "http"} {
+			if c, ok := cells[step]; ok {
+				row = append(row, c.text)
+			} else {
+				row = append(row, "-")
+			}
+		}
+		row = append(row, diagnoseVerdict(cells))
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	w.Flush()
+	return strings.TrimSpace(output.String())
+}
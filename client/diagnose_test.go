@@ -0,0 +1,42 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDiagnosis(t *testing.T) {
+	berlin := model.ProbeData{City: "Berlin", Country: "DE", ASN: 1}
+	munich := model.ProbeData{City: "Munich", Country: "DE", ASN: 2}
+	paris := model.ProbeData{City: "Paris", Country: "FR", ASN: 3}
+
+	results := map[string]model.GetMeasurement{
+		"dns": {Results: []model.MeasurementResponse{
+			{Probe: berlin, Result: model.ResultData{Status: "finished", Answers: []model.DnsAnswer{{Value: "1.1.1.1"}}, Timings: &model.Timings{Total: ms(12)}}},
+			{Probe: munich, Result: model.ResultData{Status: "finished", Answers: []model.DnsAnswer{{Value: "1.1.1.1"}}, Timings: &model.Timings{Total: ms(8)}}},
+			{Probe: paris, Result: model.ResultData{Status: "finished"}},
+		}},
+		"ping": {Results: []model.MeasurementResponse{
+			{Probe: berlin, Result: model.ResultData{Status: "finished", Stats: &model.PingStats{Avg: ms(10)}}},
+			{Probe: munich, Result: model.ResultData{Status: "finished", Stats: &model.PingStats{Loss: 100}}},
+			{Probe: paris, Result: model.ResultData{Status: "finished", Stats: &model.PingStats{Loss: 100}}},
+		}},
+		"traceroute": {Results: []model.MeasurementResponse{
+			{Probe: berlin, Result: model.ResultData{Status: "finished", ResolvedAddress: "1.1.1.1", Hops: hops(`[{"resolvedAddress":"10.0.0.1"},{"resolvedAddress":"1.1.1.1"}]`)}},
+			{Probe: munich, Result: model.ResultData{Status: "finished", ResolvedAddress: "1.1.1.1", Hops: hops(`[{"resolvedAddress":"10.0.0.1"}]`)}},
+		}},
+		"http": {Results: []model.MeasurementResponse{
+			{Probe: berlin, Result: model.ResultData{Status: "finished", StatusCode: 503, Timings: &model.Timings{Total: ms(40)}}},
+			{Probe: munich, Result: model.ResultData{Status: "failed"}},
+		}},
+	}
+
+	assert.Equal(t, `PROBE              DNS               PING              TRACEROUTE      HTTP              DIAGNOSIS
+Berlin, DE, ASN:1  ok (12 ms)        10.0 ms, 0% loss  2 hops          FAIL 503 (40 ms)  HTTP layer fails
+Munich, DE, ASN:2  ok (8 ms)         FAIL (100% loss)  stops at hop 1  FAIL (failed)     No connectivity, the path stops at hop 1
+Paris, FR, ASN:3   FAIL (no answer)  FAIL (100% loss)  -               -                 DNS resolution fails`, client.FormatDiagnosis(results))
+}
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// DiffPair is a probe of the first measurement aligned with a probe of the second one, one of them is nil when the
+// probe is missing from a measurement
+type DiffPair struct {
+	Label string
+	A, B  *model.MeasurementResponse
+}
+
+// AlignResults pairs the results of two measurements by probe. Results left without a pair, e.g. when the second
+// measurement did not run on the same probes, are then paired by country and network.
+func AlignResults(a, b model.GetMeasurement) []DiffPair {
+	var pairs []DiffPair
+	byLabel := map[string]int{}
+	for i := range a.Results {
+		label := probeLabel(a.Results[i].Probe)
+		byLabel[label] = len(pairs)
+		pairs = append(pairs, DiffPair{Label: label, A: &a.Results[i]})
+	}
+
+	var unmatched []*model.MeasurementResponse
+	for i := range b.Results {
+		idx, ok := byLabel[probeLabel(b.Results[i].Probe)]
+		if ok && pairs[idx].B == nil {
+			pairs[idx].B = &b.Results[i]
+			continue
+		}
+		unmatched = append(unmatched, &b.Results[i])
+	}
+
+	network := func(p model.ProbeData) string { return fmt.Sprintf("%s, ASN:%d", p.Country, p.ASN) }
+	for _, r := range unmatched {
+		found := false
+		for i := range pairs {
+			if pairs[i].B == nil && pairs[i].A != nil && network(pairs[i].A.Probe) == network(r.Probe) {
+				pairs[i].B = r
+				pairs[i].Label = pairs[i].A.Probe.City + " / " + r.Probe.City + ", " + network(r.Probe)
+				found = true
+				break
+			}
+		}
+		if !found {
+			pairs = append(pairs, DiffPair{Label: probeLabel(r.Probe), B: r})
+		}
+	}
+	return pairs
+}
+
+// Format a metric of both measurements with its delta, metrics missing from both are empty
+func diffCell(a, b map[string]float64, key, unit string, precision int) string {
+	av, aok := a[key]
+	bv, bok := b[key]
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', precision, 64) }
+	switch {
+	case aok && bok:
+		return fmt.Sprintf("%s → %s%s (%s)", format(av), format(bv), unit, signed(bv-av, precision))
+	case aok:
+		return format(av) + unit + " → -"
+	case bok:
+		return "- → " + format(bv) + unit
+	}
+	return ""
+}
+
+func signed(v float64, precision int) string {
+	s := strconv.FormatFloat(v, 'f', precision, 64)
+	if v >= 0 {
+		return "+" + s
+	}
+	return s
+}
+
+// Compare the metrics of a probe in both measurements: 1 if the second one regressed, -1 if it improved
+func diffTrend(a, b *model.MeasurementResponse, am, bm map[string]float64) int {
+	aok, bok := a.Result.Status == "finished", b.Result.Status == "finished"
+	if aok != bok {
+		if aok {
+			return 1
+		}
+		return -1
+	}
+
+	trend := 0
+	if av, ok := am["loss"]; ok {
+		if bv, ok := bm["loss"]; ok && bv != av {
+			if bv > av {
+				return 1
+			}
+			trend = -1
+		}
+	}
+	av, aok := am["latency"]
+	bv, bok := bm["latency"]
+	if aok && bok && av > 0 {
+		if (bv-av)/av >= significantChange {
+			return 1
+		}
+		if (av-bv)/av >= significantChange {
+			trend = -1
+		}
+	}
+	return trend
+}
+
+// Metrics of a probe compared by diff
+func diffMetrics(cmd string, result *model.MeasurementResponse) map[string]float64 {
+	if result == nil {
+		return map[string]float64{}
+	}
+	metrics := resultMetrics(cmd, *result)
+	if v, ok := trendMetric(cmd, *result); ok {
+		metrics["latency"] = v
+	}
+	return metrics
+}
+
+// DiffResults renders the status, latency, packet loss and hop count of every probe of two measurements of the same
+// type side by side with their deltas. Probes that regressed, i.e. stopped finishing or lost packets or got at least
+// 20% slower, are highlighted.
+func DiffResults(cmd string, a, b model.GetMeasurement, ctx model.Context) string {
+	hasLoss := cmd == "ping" || cmd == "mtr"
+	hasHops := cmd == "traceroute" || cmd == "mtr"
+	hasLatency := cmd != "traceroute"
+
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	header := "PROBE\tSTATUS"
+	if hasLatency {
+		header += "\tLATENCY"
+	}
+	if hasLoss {
+		header += "\tLOSS"
+	}
+	if hasHops {
+		header += "\tHOPS"
+	}
+	fmt.Fprintln(w, header+"\tCHANGE")
+
+	regressed, improved := 0, 0
+	for _, p := range AlignResults(a, b) {
+		am, bm := diffMetrics(cmd, p.A), diffMetrics(cmd, p.B)
+
+		var status string
+		switch {
+		case p.A != nil && p.B != nil && p.A.Result.Status == p.B.Result.Status:
+			status = p.A.Result.Status
+		case p.A != nil && p.B != nil:
+			status = p.A.Result.Status + " → " + p.B.Result.Status
+		case p.A != nil:
+			status = p.A.Result.Status + " → -"
+		default:
+			status = "- → " + p.B.Result.Status
+		}
+
+		row := p.Label + "\t" + status
+		if hasLatency {
+			row += "\t" + diffCell(am, bm, "latency", " ms", 2)
+		}
+		if hasLoss {
+			row += "\t" + diffCell(am, bm, "loss", "%", 1)
+		}
+		if hasHops {
+			row += "\t" + diffCell(am, bm, "hops", "", 0)
+		}
+
+		change := ""
+		switch {
+		case p.B == nil:
+			change = "missing"
+		case p.A == nil:
+			change = "new"
+		default:
+			switch diffTrend(p.A, p.B, am, bm) {
+			case 1:
+				regressed++
+				change = "regression ▲"
+				if !ctx.CI {
+					change = worse.Render(change)
+				}
+			case -1:
+				improved++
+				change = "improvement ▼"
+				if !ctx.CI {
+					change = better.Render(change)
+				}
+			}
+		}
+		fmt.Fprintln(w, row+"\t"+change)
+	}
+	w.Flush()
+
+	output.WriteString(fmt.Sprintf("\n%d probes regressed, %d improved", regressed, improved))
+	return output.String()
+}
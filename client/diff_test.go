@@ -0,0 +1,47 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlignResults(t *testing.T) {
+	munich := pingResult("Munich", 20)
+	munich.Probe.ASN = 2
+	a := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 10), munich, pingResult("Hamburg", 5)}}
+	b := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Frankfurt", 12), pingResult("Berlin", 11), pingResult("Paris", 30)}}
+	b.Results[2].Probe.Country = "FR"
+
+	pairs := client.AlignResults(a, b)
+	labels := make([]string, len(pairs))
+	for i, p := range pairs {
+		labels[i] = p.Label
+	}
+	assert.Equal(t, []string{"Berlin, DE, ASN:1", "Munich, DE, ASN:2", "Hamburg / Frankfurt, DE, ASN:1", "Paris, FR, ASN:1"}, labels)
+	assert.Equal(t, "Berlin", pairs[0].B.Probe.City)
+	assert.Nil(t, pairs[1].B)
+	assert.Nil(t, pairs[3].A)
+}
+
+func TestDiffResults(t *testing.T) {
+	lossy := pingResult("Munich", 20)
+	lossy.Result.Stats.Loss = 10.0
+	failed := pingResult("Hamburg", 0)
+	failed.Result.Status = "failed"
+	failed.Result.Stats = nil
+
+	a := model.GetMeasurement{Type: "ping", Results: []model.MeasurementResponse{pingResult("Berlin", 10), pingResult("Munich", 20), pingResult("Hamburg", 5), pingResult("Leipzig", 30)}}
+	b := model.GetMeasurement{Type: "ping", Results: []model.MeasurementResponse{pingResult("Berlin", 15), lossy, failed, pingResult("Leipzig", 20)}}
+
+	assert.Equal(t, `PROBE               STATUS             LATENCY                    LOSS                 CHANGE
+Berlin, DE, ASN:1   finished           10.00 → 15.00 ms (+5.00)   0.0 → 0.0% (+0.0)    regression ▲
+Munich, DE, ASN:1   finished           20.00 → 20.00 ms (+0.00)   0.0 → 10.0% (+10.0)  regression ▲
+Hamburg, DE, ASN:1  finished → failed  5.00 ms → -                0.0% → -             regression ▲
+Leipzig, DE, ASN:1  finished           30.00 → 20.00 ms (-10.00)  0.0 → 0.0% (+0.0)    improvement ▼
+
+3 probes regressed, 1 improved`, client.DiffResults("ping", a, b, model.Context{CI: true}))
+}
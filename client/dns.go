@@ -0,0 +1,251 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Dig-like line of a dns answer
+func formatAnswer(a model.DnsAnswer) string {
+	return fmt.Sprintf("%s\t%d\t%s\t%s\t%s", a.Name, a.TTL, a.Class, a.Type, a.Value)
+}
+
+// FilterAnswers returns the answers of the given record types, every answer without types
+func FilterAnswers(answers []model.DnsAnswer, types []string) []model.DnsAnswer {
+	if len(types) == 0 {
+		return answers
+	}
+	var res []model.DnsAnswer
+	for _, a := range answers {
+		for _, t := range types {
+			if strings.EqualFold(a.Type, t) {
+				res = append(res, a)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// FormatDnsAnswers renders the answer section of every probe as dig-like lines, only the types of the answer filter
+func FormatDnsAnswers(data model.GetMeasurement, ctx model.Context) (string, error) {
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+		if result.Result.Status != "" && result.Result.Status != "finished" {
+			output.WriteString("Probe " + result.Result.Status + "\n\n")
+			continue
+		}
+
+		answers := FilterAnswers(result.Result.Answers, ctx.AnswerFilter)
+		if len(answers) == 0 {
+			status := result.Result.StatusCodeName
+			if status == "" || status == "NOERROR" {
+				status = "No answers"
+			}
+			output.WriteString(status + "\n\n")
+			continue
+		}
+		for _, a := range answers {
+			output.WriteString(formatAnswer(a) + "\n")
+		}
+		output.WriteString("\n")
+	}
+
+	return strings.TrimSpace(output.String()), nil
+}
+
+// FormatDnsShort renders only the values of the answers like dig +short, one per line. The values are grouped under
+// the header of their probe when there are several.
+func FormatDnsShort(data model.GetMeasurement, ctx model.Context) (string, error) {
+	var output strings.Builder
+	headers := len(data.Results) > 1
+	for _, result := range data.Results {
+		if headers {
+			output.WriteString(generateHeader(result, ctx) + "\n")
+		}
+		if result.Result.Status != "" && result.Result.Status != "finished" {
+			if headers {
+				output.WriteString("Probe " + result.Result.Status + "\n")
+			}
+		} else {
+			for _, a := range FilterAnswers(result.Result.Answers, ctx.AnswerFilter) {
+				output.WriteString(a.Value + "\n")
+			}
+		}
+		if headers {
+			output.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSpace(output.String()), nil
+}
+
+// FormatDnsGroups renders the answers of several dns measurements of the same target run from the same probes, e.g. one
+// per record type or resolver, grouped by probe and then by measurement label
+func FormatDnsGroups(labels []string, measurements []model.GetMeasurement, ctx model.Context) string {
+	var order []string
+	headers := map[string]model.MeasurementResponse{}
+	byProbe := map[string][]*model.MeasurementResponse{}
+
+	for i, data := range measurements {
+		for j := range data.Results {
+			result := &data.Results[j]
+			label := probeLabel(result.Probe)
+			if _, ok := byProbe[label]; !ok {
+				order = append(order, label)
+				headers[label] = *result
+				byProbe[label] = make([]*model.MeasurementResponse, len(measurements))
+			}
+			byProbe[label][i] = result
+		}
+	}
+
+	var output strings.Builder
+	for _, label := range order {
+		output.WriteString(generateHeader(headers[label], ctx) + "\n")
+		for i, result := range byProbe[label] {
+			line := labels[i]
+			if result != nil {
+				if v, ok := KeyMetric("dns", *result); ok {
+					line += fmt.Sprintf(" (%v ms)", v)
+				}
+			}
+			output.WriteString(line + "\n")
+			switch {
+			case result == nil:
+				output.WriteString("  no result from this probe\n")
+			case result.Result.Status != "" && result.Result.Status != "finished":
+				output.WriteString("  probe " + result.Result.Status + "\n")
+			case len(FilterAnswers(result.Result.Answers, ctx.AnswerFilter)) == 0:
+				status := result.Result.StatusCodeName
+				if status == "" {
+					status = "no answers"
+				}
+				output.WriteString("  " + status + "\n")
+			default:
+				for _, a := range FilterAnswers(result.Result.Answers, ctx.AnswerFilter) {
+					output.WriteString("  " + formatAnswer(a) + "\n")
+				}
+			}
+		}
+		output.WriteString("\n")
+	}
+
+	return strings.TrimSpace(output.String())
+}
+
+// Role of the name server answering a hop of the delegation path
+func hopRole(i int, hops []model.Hop) string {
+	switch {
+	case i == len(hops)-1:
+		return "authoritative"
+	case i == 0:
+		return "root"
+	case i == 1:
+		return "TLD"
+	}
+	return "delegation"
+}
+
+// FormatDnsTrace renders the delegation path (root, TLD, authoritative) followed by the query on every probe
+func FormatDnsTrace(data model.GetMeasurement, ctx model.Context) (string, error) {
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+
+		hops := result.Result.Hops
+		if len(hops) == 0 {
+			output.WriteString("No delegation path, run the measurement with --trace\n\n")
+			continue
+		}
+
+		for i, hop := range hops {
+			line := fmt.Sprintf("%d. %s (%s)", i+1, hop.Resolver, hopRole(i, hops))
+			if total, ok := hop.Timings.TotalMs(); ok {
+				line += fmt.Sprintf(" %v ms", total)
+			}
+			output.WriteString(line + "\n")
+
+			// Group the records of the hop by name and type, e.g. "com. NS a.gtld-servers.net., b.gtld-servers.net."
+			var keys []string
+			values := map[string][]string{}
+			for _, a := range hop.Answers {
+				key := a.Name + " " + a.Type
+				if _, ok := values[key]; !ok {
+					keys = append(keys, key)
+				}
+				values[key] = append(values[key], a.Value)
+			}
+			for _, key := range keys {
+				output.WriteString("   -> " + key + " " + strings.Join(values[key], ", ") + "\n")
+			}
+		}
+		output.WriteString("\n")
+	}
+
+	return strings.TrimSpace(output.String()), nil
+}
+
+var digFlags = regexp.MustCompile(`(?m)^;; flags:([^;]*);`)
+
+// DnssecStatus is the DNSSEC state of a dns result. The API does not let the probes request DNSSEC records, so only
+// the AD flag of the resolver is known, signatures are never part of the answers
+type DnssecStatus struct {
+	// AD is the Authenticated Data flag set by a validating resolver
+	AD bool
+}
+
+// Verdict summarizes the DNSSEC state of a result
+func (s DnssecStatus) Verdict() string {
+	if s.AD {
+		return "validated"
+	}
+	return "unsigned or not validated by the resolver"
+}
+
+// CheckDnssec reads the header flags of the dig output
+func CheckDnssec(result model.MeasurementResponse) DnssecStatus {
+	var status DnssecStatus
+	if m := digFlags.FindStringSubmatch(result.Result.RawOutput); m != nil {
+		for _, flag := range strings.Fields(m[1]) {
+			if flag == "ad" {
+				status.AD = true
+			}
+		}
+	}
+	return status
+}
+
+// yesNo renders a boolean for humans
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// FormatDnssec renders the answers of every probe with its AD flag and DNSSEC verdict
+func FormatDnssec(data model.GetMeasurement, ctx model.Context) (string, error) {
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+		if result.Result.Status != "" && result.Result.Status != "finished" {
+			output.WriteString("Probe " + result.Result.Status + "\n\n")
+			continue
+		}
+
+		for _, a := range FilterAnswers(result.Result.Answers, ctx.AnswerFilter) {
+			output.WriteString(formatAnswer(a) + "\n")
+		}
+
+		status := CheckDnssec(result)
+		output.WriteString(fmt.Sprintf("AD flag: %s\n", yesNo(status.AD)))
+		output.WriteString(fmt.Sprintf("DNSSEC: %s\n\n", status.Verdict()))
+	}
+
+	return strings.TrimSpace(output.String()), nil
+}
@@ -0,0 +1,123 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDnsGroups(t *testing.T) {
+	probe := model.ProbeData{Continent: "EU", Country: "NL", City: "Amsterdam", ASN: 60404, Network: "Liteserver"}
+	a := model.GetMeasurement{Results: []model.MeasurementResponse{{
+		Probe: probe,
+		Result: model.ResultData{Status: "finished", Timings: &model.Timings{Total: ms(15)}, Answers: []model.DnsAnswer{
+			{Name: "jsdelivr.com.", Type: "A", TTL: 30, Class: "IN", Value: "92.223.84.84"},
+		}},
+	}}}
+	mx := model.GetMeasurement{Results: []model.MeasurementResponse{{
+		Probe:  probe,
+		Result: model.ResultData{Status: "finished", StatusCodeName: "NOERROR"},
+	}}}
+
+	assert.Equal(t, "> EU, NL, Amsterdam, ASN:60404, Liteserver\nA (15 ms)\n  jsdelivr.com.\t30\tIN\tA\t92.223.84.84\nMX\n  NOERROR",
+		client.FormatDnsGroups([]string{"A", "MX"}, []model.GetMeasurement{a, mx}, model.Context{CI: true}))
+}
+
+func TestFormatDnsTrace(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		{
+			Probe: model.ProbeData{Continent: "EU", Country: "NL", City: "Amsterdam", ASN: 60404, Network: "Liteserver"},
+			Result: model.ResultData{Status: "finished", Hops: hops(`[
+				{"resolver": "185.31.172.240", "answers": [{"name": ".", "type": "NS", "value": "a.root-servers.net."}, {"name": ".", "type": "NS", "value": "b.root-servers.net."}], "timings": {"total": 1}},
+				{"resolver": "a.root-servers.net", "answers": [{"name": "com.", "type": "NS", "value": "a.gtld-servers.net."}], "timings": {"total": 12}},
+				{"resolver": "a.gtld-servers.net", "answers": [{"name": "jsdelivr.com.", "type": "A", "value": "92.223.84.84"}], "timings": {"total": 20}}
+			]`)},
+		},
+		{
+			Probe: model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+		},
+	}}
+
+	output, err := client.FormatDnsTrace(data, model.Context{CI: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `> EU, NL, Amsterdam, ASN:60404, Liteserver
+1. 185.31.172.240 (root) 1 ms
+   -> . NS a.root-servers.net., b.root-servers.net.
+2. a.root-servers.net (TLD) 12 ms
+   -> com. NS a.gtld-servers.net.
+3. a.gtld-servers.net (authoritative) 20 ms
+   -> jsdelivr.com. A 92.223.84.84
+
+> EU, DE, Berlin, ASN:1, Network
+No delegation path, run the measurement with --trace`, output)
+}
+
+func TestCheckDnssec(t *testing.T) {
+	validated := model.MeasurementResponse{Result: model.ResultData{
+		RawOutput: ";; ->>HEADER<<- opcode: QUERY, status: NOERROR, id: 1\n;; flags: qr rd ra ad; QUERY: 1, ANSWER: 2\n",
+		Answers:   []model.DnsAnswer{{Type: "A"}},
+	}}
+	status := client.CheckDnssec(validated)
+	assert.Equal(t, client.DnssecStatus{AD: true}, status)
+	assert.Equal(t, "validated", status.Verdict())
+
+	notValidated := model.MeasurementResponse{Result: model.ResultData{
+		RawOutput: ";; flags: qr rd ra; QUERY: 1\n",
+	}}
+	assert.Equal(t, "unsigned or not validated by the resolver", client.CheckDnssec(notValidated).Verdict())
+}
+
+func TestFormatDnsShort(t *testing.T) {
+	answers := []model.DnsAnswer{
+		{Name: "cdn.jsdelivr.net.", Type: "CNAME", TTL: 300, Class: "IN", Value: "jsdelivr.map.fastly.net."},
+		{Name: "jsdelivr.map.fastly.net.", Type: "A", TTL: 30, Class: "IN", Value: "151.101.1.229"},
+		{Name: "jsdelivr.map.fastly.net.", Type: "A", TTL: 30, Class: "IN", Value: "151.101.65.229"},
+	}
+	amsterdam := model.MeasurementResponse{
+		Probe:  model.ProbeData{Continent: "EU", Country: "NL", City: "Amsterdam", ASN: 60404, Network: "Liteserver"},
+		Result: model.ResultData{Status: "finished", Answers: answers},
+	}
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{amsterdam}}
+
+	output, err := client.FormatDnsShort(data, model.Context{CI: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "jsdelivr.map.fastly.net.\n151.101.1.229\n151.101.65.229", output)
+
+	output, err = client.FormatDnsShort(data, model.Context{CI: true, AnswerFilter: []string{"A"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "151.101.1.229\n151.101.65.229", output)
+
+	// Several probes are printed under their header
+	data.Results = append(data.Results, model.MeasurementResponse{
+		Probe:  model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+		Result: model.ResultData{Status: "failed"},
+	})
+	output, err = client.FormatDnsShort(data, model.Context{CI: true, AnswerFilter: []string{"CNAME"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `> EU, NL, Amsterdam, ASN:60404, Liteserver
+jsdelivr.map.fastly.net.
+
+> EU, DE, Berlin, ASN:1, Network
+Probe failed`, output)
+}
+
+func TestFormatDnsAnswers(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{{
+		Probe: model.ProbeData{Continent: "EU", Country: "NL", City: "Amsterdam", ASN: 60404, Network: "Liteserver"},
+		Result: model.ResultData{Status: "finished", StatusCodeName: "NOERROR", Answers: []model.DnsAnswer{
+			{Name: "cdn.jsdelivr.net.", Type: "CNAME", TTL: 300, Class: "IN", Value: "jsdelivr.map.fastly.net."},
+			{Name: "jsdelivr.map.fastly.net.", Type: "A", TTL: 30, Class: "IN", Value: "151.101.1.229"},
+		}},
+	}}}
+
+	output, err := client.FormatDnsAnswers(data, model.Context{CI: true, AnswerFilter: []string{"CNAME"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "> EU, NL, Amsterdam, ASN:60404, Liteserver\ncdn.jsdelivr.net.\t300\tIN\tCNAME\tjsdelivr.map.fastly.net.", output)
+
+	output, err = client.FormatDnsAnswers(data, model.Context{CI: true, AnswerFilter: []string{"AAAA"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "> EU, NL, Amsterdam, ASN:60404, Liteserver\nNo answers", output)
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/jsdelivr/globalping-cli/pkg/globalping"
+)
+
+// Error types returned by the API
+const (
+	ErrorTypeNoProbes   = globalping.ErrorTypeNoProbes
+	ErrorTypeValidation = globalping.ErrorTypeValidation
+	ErrorTypeApi        = globalping.ErrorTypeApi
+	ErrorTypeRateLimit  = globalping.ErrorTypeRateLimit
+	ErrorTypeNotFound   = globalping.ErrorTypeNotFound
+)
+
+// APIError is an error response of the API, its message is replaced with the explanation shown by the CLI
+type APIError = globalping.APIError
+
+// IsUsageError returns true if err is an APIError caused by the input of the user
+func IsUsageError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Usage()
+}
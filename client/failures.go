@@ -0,0 +1,78 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Exit code policies of --fail-on-probe-errors
+const (
+	// FailPolicyAny fails when at least one probe did not finish
+	FailPolicyAny = "any"
+	// FailPolicyAll only fails when no probe finished, partial results are accepted
+	FailPolicyAll = "all"
+	// FailPolicyNone never fails because of probe errors, e.g. to override the config file
+	FailPolicyNone = "none"
+)
+
+// FailPolicies are the values accepted by --fail-on-probe-errors
+var FailPolicies = []string{FailPolicyAny, FailPolicyAll, FailPolicyNone}
+
+// ProbeFailed returns true if the probe reported a failure or went offline, results still in progress are not failed
+func ProbeFailed(result model.MeasurementResponse) bool {
+	switch result.Result.Status {
+	case "", "finished", "in-progress":
+		return false
+	}
+	return true
+}
+
+// FailedProbes returns the number of probes of a measurement that failed
+func FailedProbes(data model.GetMeasurement) int {
+	n := 0
+	for _, result := range data.Results {
+		if ProbeFailed(result) {
+			n++
+		}
+	}
+	return n
+}
+
+// probeErrorsTolerated returns true if the probes that did not finish do not fail the measurement under the given
+// --fail-on-probe-errors policy
+func probeErrorsTolerated(data model.GetMeasurement, policy string) bool {
+	switch policy {
+	case FailPolicyNone:
+		return true
+	case FailPolicyAll:
+		return FailedProbes(data) < len(data.Results)
+	}
+	return false
+}
+
+// FailureReason explains why a probe did not finish, the output of the failed command if any
+func FailureReason(result model.MeasurementResponse) string {
+	if reason := strings.TrimSpace(result.Result.RawOutput); reason != "" {
+		return reason
+	}
+	switch result.Result.Status {
+	case "offline":
+		return "the probe went offline"
+	case "in-progress":
+		return "the probe did not finish in time"
+	}
+	return ""
+}
+
+// Output of a probe, the raw output of the command or why the probe failed, rendered apart from the results
+func resultOutput(result model.MeasurementResponse) string {
+	if !ProbeFailed(result) {
+		return strings.TrimSpace(result.Result.RawOutput)
+	}
+	line := "Probe " + result.Result.Status
+	if reason := FailureReason(result); reason != "" {
+		line += ": " + reason
+	}
+	return bad.Render(line)
+}
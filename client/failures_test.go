@@ -0,0 +1,91 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailureReason(t *testing.T) {
+	result := func(status, output string) model.MeasurementResponse {
+		return model.MeasurementResponse{Result: model.ResultData{Status: status, RawOutput: output}}
+	}
+	assert.Equal(t, "ping: unknown host example.invalid", client.FailureReason(result("failed", "ping: unknown host example.invalid\n")))
+	assert.Equal(t, "the probe went offline", client.FailureReason(result("offline", "")))
+	assert.Equal(t, "the probe did not finish in time", client.FailureReason(result("in-progress", "")))
+	assert.Equal(t, "", client.FailureReason(result("failed", "")))
+
+	assert.True(t, client.ProbeFailed(result("offline", "")))
+	assert.False(t, client.ProbeFailed(result("in-progress", "")))
+	assert.False(t, client.ProbeFailed(result("finished", "")))
+}
+
+func TestFormatCIFailedProbe(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		{
+			Probe:  model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+			Result: model.ResultData{Status: "finished", RawOutput: "PING example.com\n"},
+		},
+		{
+			Probe:  model.ProbeData{Continent: "EU", Country: "FR", City: "Paris", ASN: 2, Network: "Network"},
+			Result: model.ResultData{Status: "failed", RawOutput: "The measurement command timed out."},
+		},
+		{
+			Probe:  model.ProbeData{Continent: "EU", Country: "IT", City: "Rome", ASN: 3, Network: "Network"},
+			Result: model.ResultData{Status: "offline"},
+		},
+		{
+			Probe:  model.ProbeData{Continent: "EU", Country: "ES", City: "Madrid", ASN: 4, Network: "Network"},
+			Result: model.ResultData{Status: "in-progress", RawOutput: "PING example.com\n"},
+		},
+	}}
+
+	assert.Equal(t, `> EU, DE, Berlin, ASN:1, Network
+PING example.com
+
+> EU, FR, Paris, ASN:2, Network
+Probe failed: The measurement command timed out.
+
+> EU, IT, Rome, ASN:3, Network
+Probe offline: the probe went offline
+
+> EU, ES, Madrid, ASN:4, Network
+PING example.com
+Incomplete: the probe did not finish in time`, client.FormatCI(data, model.Context{CI: true}))
+}
+
+func TestCheckThresholdsFailPolicy(t *testing.T) {
+	failed := pingResult("Munich", 0)
+	failed.Result.Status = "failed"
+	failed.Result.RawOutput = "ping: unknown host example.invalid\nsecond line"
+	partial := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 10), failed}}
+	allFailed := model.GetMeasurement{Results: []model.MeasurementResponse{failed}}
+
+	assert.Equal(t, []client.Violation{
+		{Probe: "Munich, DE, ASN:1", Reason: "probe failed: ping: unknown host example.invalid"},
+	}, client.CheckThresholds("ping", partial, model.Thresholds{FailOnProbeErrors: client.FailPolicyAny}))
+
+	assert.Empty(t, client.CheckThresholds("ping", partial, model.Thresholds{FailOnProbeErrors: client.FailPolicyAll}))
+	assert.Len(t, client.CheckThresholds("ping", allFailed, model.Thresholds{FailOnProbeErrors: client.FailPolicyAll}), 1)
+	assert.Nil(t, client.CheckThresholds("ping", partial, model.Thresholds{FailOnProbeErrors: client.FailPolicyNone}))
+}
+
+func TestFailPolicyNoneWithThresholds(t *testing.T) {
+	failed := pingResult("Munich", 0)
+	failed.Result.Status = "failed"
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 10), pingResult("Paris", 150), failed}}
+	th := model.Thresholds{MaxLatency: 100 * time.Millisecond, FailOnProbeErrors: client.FailPolicyNone}
+
+	violations := client.CheckThresholds("ping", data, th)
+	assert.Equal(t, []client.Violation{{Probe: "Paris, DE, ASN:1", Reason: "latency 150.00 ms exceeds 100ms"}}, violations)
+	assert.Equal(t, "PASS Berlin, DE, ASN:1\nFAIL Paris, DE, ASN:1: latency 150.00 ms exceeds 100ms\nPASS Munich, DE, ASN:1", client.FormatChecks("ping", data, th))
+	assert.NotContains(t, client.GithubAnnotations("ping", "example.com", data, th), "Munich")
+
+	th.MaxLatency = 200 * time.Millisecond
+	assert.Empty(t, client.CheckThresholds("ping", data, th))
+	assert.False(t, client.MeasurementFailed(data, nil, th.FailOnProbeErrors))
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Formatter renders a finished measurement in a specific output format
+type Formatter func(data model.GetMeasurement, ctx model.Context) (string, error)
+
+// Output formats selectable with the --format flag
+var formatters = map[string]Formatter{
+	"prometheus": FormatPrometheus,
+	"table":      FormatTable,
+	"dnssec":     FormatDnssec,
+	"junit":      FormatJUnit,
+	"markdown":   FormatMarkdown,
+	"tls":        FormatTLS,
+	"trace":      FormatDnsTrace,
+	"answers":    FormatDnsAnswers,
+	"short":      FormatDnsShort,
+	"waterfall":  FormatWaterfall,
+	"cdn":        FormatCDN,
+	"aspath":     FormatASPath,
+	"brief":      FormatBrief,
+	"geojson":    FormatGeoJSON,
+	"html":       FormatHTML,
+}
+
+// FormatNames returns the supported --format values in alphabetical order
+func FormatNames() []string {
+	res := make([]string, 0, len(formatters))
+	for name := range formatters {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// ValidFormat checks if the format is supported, an empty format selects the default output
+func ValidFormat(format string) bool {
+	if format == "" {
+		return true
+	}
+	_, ok := formatters[format]
+	return ok
+}
+
+// measurementType returns the type of a measurement, the command of the context if the API did not send it
+func measurementType(data model.GetMeasurement, ctx model.Context) string {
+	if data.Type != "" {
+		return data.Type
+	}
+	return ctx.Cmd
+}
+
+// FormatTable renders the hops of mtr and traceroute measurements as tables
+func FormatTable(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+
+	switch cmd {
+	case "mtr":
+		return FormatMtrTable(data, ctx)
+	case "traceroute":
+		return FormatTracerouteTable(data, ctx)
+	}
+	return "", fmt.Errorf("err: the table format is not supported for %s measurements", cmd)
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// GeoJSON types of the probe results, see RFC 7946
+type geoFeatureCollection struct {
+	Type     string       `json:"type"`
+	Features []geoFeature `json:"features"`
+}
+
+type geoFeature struct {
+	Type string `json:"type"`
+	// Geometry is null for probes without coordinates
+	Geometry   *geoPoint              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoPoint struct {
+	Type string `json:"type"`
+	// Coordinates are the longitude then the latitude
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// FormatGeoJSON renders a FeatureCollection with one point per probe, the location and metrics of the probe are
+// properties, e.g. to display the results in geojson.io, Kepler.gl or a Grafana Geomap panel
+func FormatGeoJSON(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+
+	collection := geoFeatureCollection{Type: "FeatureCollection", Features: []geoFeature{}}
+	for _, result := range data.Results {
+		p := result.Probe
+		properties := map[string]interface{}{
+			"measurement": data.ID,
+			"type":        cmd,
+			"target":      ctx.Target,
+			"status":      result.Result.Status,
+			"continent":   p.Continent,
+			"country":     p.Country,
+			"city":        p.City,
+			"asn":         p.ASN,
+			"network":     p.Network,
+		}
+		if p.State != "" {
+			properties["state"] = p.State
+		}
+		// Metrics are flat properties so map styles can use them directly
+		for k, v := range resultMetrics(cmd, result) {
+			properties[k] = v
+		}
+
+		feature := geoFeature{Type: "Feature", Properties: properties}
+		if p.Latitude != 0 || p.Longitude != 0 {
+			feature.Geometry = &geoPoint{Type: "Point", Coordinates: [2]float64{p.Longitude, p.Latitude}}
+		}
+		collection.Features = append(collection.Features, feature)
+	}
+
+	b, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return "", errors.New("err: failed to encode the results as GeoJSON")
+	}
+	return string(b), nil
+}
@@ -0,0 +1,70 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatGeoJSON(t *testing.T) {
+	berlin := pingResult("Berlin", 10)
+	berlin.Probe.Continent = "EU"
+	berlin.Probe.Network = "Deutsche Telekom AG"
+	berlin.Probe.Latitude = 52.52
+	berlin.Probe.Longitude = 13.41
+	unknown := pingResult("Nowhere", 20)
+	data := model.GetMeasurement{ID: "abcd", Type: "ping", Results: []model.MeasurementResponse{berlin, unknown}}
+
+	output, err := client.FormatGeoJSON(data, model.Context{Target: "jsdelivr.com"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "geometry": {"type": "Point", "coordinates": [13.41, 52.52]},
+      "properties": {
+        "measurement": "abcd", "type": "ping", "target": "jsdelivr.com", "status": "finished",
+        "continent": "EU", "country": "DE", "city": "Berlin", "asn": 1, "network": "Deutsche Telekom AG",
+        "avg": 10, "loss": 0
+      }
+    },
+    {
+      "type": "Feature",
+      "geometry": null,
+      "properties": {
+        "measurement": "abcd", "type": "ping", "target": "jsdelivr.com", "status": "finished",
+        "continent": "", "country": "DE", "city": "Nowhere", "asn": 1, "network": "",
+        "avg": 20, "loss": 0
+      }
+    }
+  ]
+}`, output)
+}
+
+func TestFormatGeoJSONEmpty(t *testing.T) {
+	output, err := client.FormatGeoJSON(model.GetMeasurement{}, model.Context{Cmd: "ping"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type": "FeatureCollection", "features": []}`, output)
+}
+
+func TestOutputFileGeoJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.geojson")
+	data := model.GetMeasurement{Type: "ping", Results: []model.MeasurementResponse{pingResult("Berlin", 10)}}
+
+	_, err := client.OutputFile(context.Background(), "abcd", data, model.Context{Cmd: "ping", Output: path})
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var collection map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &collection))
+	assert.Equal(t, "FeatureCollection", collection["type"])
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Escape annotation data as required by GitHub workflow commands
+func githubEscape(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// Escape annotation properties, which additionally cannot contain : and ,
+func githubEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// GithubAnnotations returns ::error annotations for probes failing the thresholds and ::warning annotations
+// for probes with packet loss or HTTP error responses that are still within the thresholds
+func GithubAnnotations(cmd string, target string, data model.GetMeasurement, th model.Thresholds) string {
+	tolerate := probeErrorsTolerated(data, th.FailOnProbeErrors)
+	var lines []string
+	for _, result := range data.Results {
+		label := probeLabel(result.Probe)
+		title := githubEscapeProperty("globalping " + cmd + " " + target)
+
+		reasons := resultViolations(cmd, result, th, tolerate)
+		for _, reason := range reasons {
+			lines = append(lines, fmt.Sprintf("::error title=%s::%s", title, githubEscape(label+": "+reason)))
+		}
+		if len(reasons) > 0 {
+			continue
+		}
+
+		if loss, ok := pingLoss(result); ok && loss > 0 {
+			lines = append(lines, fmt.Sprintf("::warning title=%s::%s", title, githubEscape(fmt.Sprintf("%s: packet loss %v%%", label, loss))))
+		}
+		if cmd == "http" && th.ExpectStatus == 0 && result.Result.StatusCode >= 400 {
+			lines = append(lines, fmt.Sprintf("::warning title=%s::%s", title, githubEscape(fmt.Sprintf("%s: status code %d", label, result.Result.StatusCode))))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GithubSummary renders the Markdown job summary table of a measurement
+func GithubSummary(cmd string, target string, data model.GetMeasurement, th model.Thresholds) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("### globalping %s %s\n\n", cmd, target))
+	output.WriteString("| Probe | Result | Status |\n| --- | --- | --- |\n")
+
+	tolerate := probeErrorsTolerated(data, th.FailOnProbeErrors)
+	for _, result := range data.Results {
+		status := "✅"
+		if reasons := resultViolations(cmd, result, th, tolerate); len(reasons) > 0 {
+			status = "❌ " + strings.Join(reasons, ", ")
+		}
+		output.WriteString(fmt.Sprintf("| %s | %s | %s |\n", probeLabel(result.Probe), metricCell(cmd, result), status))
+	}
+
+	return output.String()
+}
+
+// AppendGithubSummary appends the job summary to the file referenced by GITHUB_STEP_SUMMARY, if set
+func AppendGithubSummary(summary string) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("err: failed to open job summary file: %s", path)
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(summary + "\n")
+	if err != nil {
+		return fmt.Errorf("err: failed to write job summary file: %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package client_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGithubAnnotations(t *testing.T) {
+	lossy := pingResult("Hamburg", 20)
+	lossy.Result.Stats.Loss = 33.33
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 20), pingResult("Munich", 150), lossy}}
+	th := model.Thresholds{MaxLatency: 100 * time.Millisecond}
+
+	assert.Equal(t, `::error title=globalping ping google.com::Munich, DE, ASN:1: latency 150.00 ms exceeds 100ms
+::warning title=globalping ping google.com::Hamburg, DE, ASN:1: packet loss 33.33%25`, client.GithubAnnotations("ping", "google.com", data, th))
+}
+
+func TestGithubAnnotationsHttp(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		{Probe: model.ProbeData{City: "Berlin", Country: "DE", ASN: 1}, Result: model.ResultData{Status: "finished", StatusCode: 404}},
+	}}
+
+	assert.Equal(t, `::warning title=globalping http https%3A//jsdelivr.com::Berlin, DE, ASN:1: status code 404`, client.GithubAnnotations("http", "https://jsdelivr.com", data, model.Thresholds{}))
+	assert.Equal(t, `::error title=globalping http https%3A//jsdelivr.com::Berlin, DE, ASN:1: status code 404, expected 200`, client.GithubAnnotations("http", "https://jsdelivr.com", data, model.Thresholds{ExpectStatus: 200}))
+}
+
+func TestGithubSummary(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 20), pingResult("Munich", 150)}}
+	summary := client.GithubSummary("ping", "google.com", data, model.Thresholds{MaxLatency: 100 * time.Millisecond})
+
+	assert.Equal(t, `### globalping ping google.com
+
+| Probe | Result | Status |
+| --- | --- | --- |
+| Berlin, DE, ASN:1 | 20.00 ms, 0% loss | ✅ |
+| Munich, DE, ASN:1 | 150.00 ms, 0% loss | ❌ latency 150.00 ms exceeds 100ms |
+`, summary)
+
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+	assert.NoError(t, client.AppendGithubSummary("a"))
+	assert.NoError(t, client.AppendGithubSummary("b"))
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\n", string(b))
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// GrafanaRun is one measurement of the series exported to Grafana
+type GrafanaRun struct {
+	Time   time.Time
+	Target string
+	Data   model.GetMeasurement
+}
+
+// Plugin of the TestData datasource bundled with Grafana, its CSV content scenario embeds the data in the dashboard
+const grafanaTestData = "grafana-testdata-datasource"
+
+// Latency of a probe plotted in Grafana, mtr has no key metric but the average rtt of the last hop is comparable
+func trendMetric(cmd string, result model.MeasurementResponse) (float64, bool) {
+	if v, ok := KeyMetric(cmd, result); ok {
+		return v, true
+	}
+	v, ok := resultMetrics(cmd, result)["avg"]
+	return v, ok
+}
+
+// Escape a CSV cell
+func csvCell(s string) string {
+	if strings.ContainsAny(s, ",\"\n") {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	return s
+}
+
+// CSV of one panel: a row per run with the median latency and the latency of every probe, probes missing from
+// a run are left empty
+func grafanaCsv(cmd string, runs []GrafanaRun) (string, bool) {
+	type row struct {
+		time   time.Time
+		median float64
+		values map[string]float64
+	}
+
+	var rows []row
+	var probes []string
+	seen := map[string]bool{}
+	for _, run := range runs {
+		r := row{time: run.Time, values: map[string]float64{}}
+		var sorted []float64
+		for _, result := range run.Data.Results {
+			v, ok := trendMetric(cmd, result)
+			if !ok {
+				continue
+			}
+			p := result.Probe
+			name := fmt.Sprintf("%s, %s, AS%d", p.City, p.Country, p.ASN)
+			if !seen[name] {
+				seen[name] = true
+				probes = append(probes, name)
+			}
+			r.values[name] = v
+			sorted = append(sorted, v)
+		}
+		if len(sorted) == 0 {
+			continue
+		}
+		sort.Float64s(sorted)
+		r.median = Percentile(sorted, 50)
+		rows = append(rows, r)
+	}
+	if len(rows) == 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString("time,median")
+	for _, p := range probes {
+		b.WriteString("," + csvCell(p))
+	}
+	for _, r := range rows {
+		b.WriteString("\n" + r.time.UTC().Format(time.RFC3339) + "," + strconv.FormatFloat(r.median, 'f', 3, 64))
+		for _, p := range probes {
+			b.WriteString(",")
+			if v, ok := r.values[p]; ok {
+				b.WriteString(strconv.FormatFloat(v, 'f', 3, 64))
+			}
+		}
+	}
+	return b.String(), true
+}
+
+// GrafanaDashboard converts a series of measurements into a dashboard importable in Grafana, with one time series
+// panel per measurement type and target plotting the median latency and the latency of every probe.
+// The data is embedded with the TestData datasource, no database is needed. Series without a latency, e.g. traceroute,
+// have no panel.
+func GrafanaDashboard(title string, runs []GrafanaRun) ([]byte, error) {
+	type group struct {
+		cmd    string
+		target string
+		runs   []GrafanaRun
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Time.Before(runs[j].Time) })
+	var groups []*group
+	byKey := map[string]*group{}
+	for _, run := range runs {
+		key := run.Data.Type + " " + run.Target
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{cmd: run.Data.Type, target: run.Target}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.runs = append(g.runs, run)
+	}
+
+	datasource := map[string]string{"type": grafanaTestData, "uid": "${DS_TESTDATA}"}
+	panels := []map[string]interface{}{}
+	for _, g := range groups {
+		csv, ok := grafanaCsv(g.cmd, g.runs)
+		if !ok {
+			continue
+		}
+		panels = append(panels, map[string]interface{}{
+			"id":         len(panels) + 1,
+			"type":       "timeseries",
+			"title":      strings.TrimSpace(g.cmd + " " + g.target),
+			"datasource": datasource,
+			"gridPos":    map[string]int{"h": 9, "w": 24, "x": 0, "y": len(panels) * 9},
+			"fieldConfig": map[string]interface{}{
+				"defaults":  map[string]interface{}{"unit": "ms"},
+				"overrides": []interface{}{},
+			},
+			"targets": []map[string]interface{}{{
+				"refId":      "A",
+				"datasource": datasource,
+				"scenarioId": "csv_content",
+				"csvContent": csv,
+			}},
+		})
+	}
+	if len(panels) == 0 {
+		return nil, errors.New("err: no measurement with a latency to export")
+	}
+
+	dashboard := map[string]interface{}{
+		"__inputs": []map[string]string{{
+			"name":       "DS_TESTDATA",
+			"label":      "TestData",
+			"type":       "datasource",
+			"pluginId":   grafanaTestData,
+			"pluginName": "TestData",
+		}},
+		"title":         title,
+		"tags":          []string{"globalping"},
+		"schemaVersion": 36,
+		"editable":      true,
+		"time": map[string]string{
+			"from": runs[0].Time.UTC().Format(time.RFC3339),
+			"to":   runs[len(runs)-1].Time.UTC().Format(time.RFC3339),
+		},
+		"panels": panels,
+	}
+
+	b, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return nil, errors.New("err: failed to encode the Grafana dashboard")
+	}
+	return b, nil
+}
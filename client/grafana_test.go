@@ -0,0 +1,61 @@
+package client_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrafanaDashboard(t *testing.T) {
+	start := time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC)
+	ping := func(at time.Duration, results ...model.MeasurementResponse) client.GrafanaRun {
+		return client.GrafanaRun{Time: start.Add(at), Target: "jsdelivr.com", Data: model.GetMeasurement{Type: "ping", Results: results}}
+	}
+	traceroute := client.GrafanaRun{Time: start, Target: "jsdelivr.com", Data: model.GetMeasurement{Type: "traceroute", Results: []model.MeasurementResponse{{}}}}
+
+	b, err := client.GrafanaDashboard("Globalping", []client.GrafanaRun{
+		ping(time.Minute, pingResult("Berlin", 12), pingResult("Munich", 30)),
+		ping(0, pingResult("Berlin", 10), pingResult("Hamburg", 20)),
+		traceroute,
+	})
+	assert.NoError(t, err)
+
+	var dashboard struct {
+		Title  string `json:"title"`
+		Inputs []struct {
+			PluginID string `json:"pluginId"`
+		} `json:"__inputs"`
+		Time   map[string]string `json:"time"`
+		Panels []struct {
+			Type    string `json:"type"`
+			Title   string `json:"title"`
+			Targets []struct {
+				ScenarioID string `json:"scenarioId"`
+				CsvContent string `json:"csvContent"`
+			} `json:"targets"`
+		} `json:"panels"`
+	}
+	assert.NoError(t, json.Unmarshal(b, &dashboard))
+	assert.Equal(t, "Globalping", dashboard.Title)
+	assert.Equal(t, "grafana-testdata-datasource", dashboard.Inputs[0].PluginID)
+	assert.Equal(t, map[string]string{"from": "2023-10-01T10:00:00Z", "to": "2023-10-01T10:01:00Z"}, dashboard.Time)
+
+	// Traceroute has no latency to plot
+	assert.Len(t, dashboard.Panels, 1)
+	assert.Equal(t, "timeseries", dashboard.Panels[0].Type)
+	assert.Equal(t, "ping jsdelivr.com", dashboard.Panels[0].Title)
+	assert.Equal(t, "csv_content", dashboard.Panels[0].Targets[0].ScenarioID)
+	assert.Equal(t, `time,median,"Berlin, DE, AS1","Hamburg, DE, AS1","Munich, DE, AS1"
+2023-10-01T10:00:00Z,15.000,10.000,20.000,
+2023-10-01T10:01:00Z,21.000,12.000,,30.000`, dashboard.Panels[0].Targets[0].CsvContent)
+}
+
+func TestGrafanaDashboardWithoutLatency(t *testing.T) {
+	_, err := client.GrafanaDashboard("Globalping", []client.GrafanaRun{{Data: model.GetMeasurement{Type: "traceroute"}}})
+	assert.EqualError(t, err, "err: no measurement with a latency to export")
+}
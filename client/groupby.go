@@ -0,0 +1,133 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// GroupByKeys are the values accepted by --group-by
+var GroupByKeys = []string{"country", "continent", "asn", "network"}
+
+// Group of a probe with its label, e.g. DE (Germany) or AS3320 Deutsche Telekom AG
+func probeGroup(by string, p model.ProbeData) (key, label string) {
+	switch by {
+	case "continent":
+		if name, ok := Continents[p.Continent]; ok {
+			return p.Continent, fmt.Sprintf("%s (%s)", p.Continent, name)
+		}
+		return p.Continent, p.Continent
+	case "asn":
+		key = "AS" + strconv.Itoa(p.ASN)
+		return key, strings.TrimSpace(key + " " + p.Network)
+	case "network":
+		return p.Network, p.Network
+	}
+	if name, ok := Countries[p.Country]; ok {
+		return p.Country, fmt.Sprintf("%s (%s)", p.Country, name)
+	}
+	return p.Country, p.Country
+}
+
+// GroupStats are the metrics aggregated over the probes of a group
+type GroupStats struct {
+	Label  string
+	Probes int
+	// Finished is the number of probes that finished, the success rate is Finished / Probes
+	Finished int
+	// Latencies of the probes with a latency, sorted
+	Latencies []float64
+	// Sent and Lost are the ping packets of the group
+	Sent, Lost int
+}
+
+// Mean returns the mean latency of the group, ok is false if no probe has a latency
+func (g GroupStats) Mean() (float64, bool) {
+	if len(g.Latencies) == 0 {
+		return 0, false
+	}
+	sum := 0.0
+	for _, v := range g.Latencies {
+		sum += v
+	}
+	return sum / float64(len(g.Latencies)), true
+}
+
+// GroupResults aggregates the results of a measurement by country, continent, asn or network, the groups with the
+// most probes first
+func GroupResults(cmd string, data model.GetMeasurement, by string) []GroupStats {
+	var groups []*GroupStats
+	byKey := map[string]*GroupStats{}
+	for _, result := range data.Results {
+		key, label := probeGroup(by, result.Probe)
+		g, ok := byKey[key]
+		if !ok {
+			g = &GroupStats{Label: label}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+
+		g.Probes++
+		if !ProbeFailed(result) && result.Result.Status != "in-progress" {
+			g.Finished++
+		}
+		if v, ok := probeLatency(cmd, result); ok {
+			g.Latencies = append(g.Latencies, v)
+		}
+		if cmd == "ping" && result.Result.Stats != nil {
+			g.Sent += result.Result.Stats.Total
+			g.Lost += result.Result.Stats.Drop
+		}
+	}
+
+	res := make([]GroupStats, len(groups))
+	for i, g := range groups {
+		sort.Float64s(g.Latencies)
+		res[i] = *g
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Probes != res[j].Probes {
+			return res[i].Probes > res[j].Probes
+		}
+		return res[i].Label < res[j].Label
+	})
+	return res
+}
+
+// FormatGroups renders the metrics of every group of probes as a table: mean and median latency, packet loss for
+// ping and the share of probes that finished
+func FormatGroups(data model.GetMeasurement, ctx model.Context) string {
+	cmd := measurementType(data, ctx)
+	by := ctx.GroupBy
+
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	header := strings.ToUpper(by) + "\tPROBES\tSUCCESS\tMEAN\tMEDIAN"
+	if cmd == "ping" {
+		header += "\tLOSS"
+	}
+	fmt.Fprintln(w, header)
+
+	for _, g := range GroupResults(cmd, data, by) {
+		mean, median := "-", "-"
+		if v, ok := g.Mean(); ok {
+			mean = fmt.Sprintf("%.2f ms", v)
+			median = fmt.Sprintf("%.2f ms", Percentile(g.Latencies, 50))
+		}
+		row := fmt.Sprintf("%s\t%d\t%.0f%%\t%s\t%s", g.Label, g.Probes, float64(g.Finished)/float64(g.Probes)*100, mean, median)
+		if cmd == "ping" {
+			if g.Sent > 0 {
+				row += fmt.Sprintf("\t%.2f%%", float64(g.Lost)/float64(g.Sent)*100)
+			} else {
+				row += "\t-"
+			}
+		}
+		fmt.Fprintln(w, row)
+	}
+	w.Flush()
+	return strings.TrimRight(output.String(), "\n")
+}
@@ -0,0 +1,54 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatGroups(t *testing.T) {
+	probe := func(city, country, continent string, asn int, avg float64, total, drop int) model.MeasurementResponse {
+		r := pingResult(city, avg)
+		r.Probe.Country = country
+		r.Probe.Continent = continent
+		r.Probe.ASN = asn
+		r.Probe.Network = "Network " + country
+		r.Result.Stats.Total = total
+		r.Result.Stats.Drop = drop
+		return r
+	}
+	failed := probe("Hamburg", "DE", "EU", 3320, 0, 0, 0)
+	failed.Result.Status = "failed"
+	failed.Result.Stats = nil
+	data := model.GetMeasurement{Type: "ping", Results: []model.MeasurementResponse{
+		probe("Paris", "FR", "EU", 12322, 15, 3, 0),
+		probe("Berlin", "DE", "EU", 3320, 10, 3, 1),
+		probe("Munich", "DE", "EU", 3320, 30, 3, 0),
+		failed,
+		probe("Tokyo", "JP", "AS", 2516, 200, 3, 0),
+	}}
+
+	assert.Equal(t, `COUNTRY       PROBES  SUCCESS  MEAN       MEDIAN     LOSS
+DE (Germany)  3       67%      20.00 ms   20.00 ms   16.67%
+FR (France)   1       100%     15.00 ms   15.00 ms   0.00%
+JP (Japan)    1       100%     200.00 ms  200.00 ms  0.00%`, client.FormatGroups(data, model.Context{GroupBy: "country"}))
+
+	assert.Equal(t, `CONTINENT    PROBES  SUCCESS  MEAN       MEDIAN     LOSS
+EU (Europe)  4       75%      18.33 ms   15.00 ms   11.11%
+AS (Asia)    1       100%     200.00 ms  200.00 ms  0.00%`, client.FormatGroups(data, model.Context{GroupBy: "continent"}))
+
+	groups := client.GroupResults("ping", data, "asn")
+	assert.Equal(t, "AS3320 Network DE", groups[0].Label)
+	assert.Equal(t, 3, groups[0].Probes)
+	assert.Equal(t, 2, groups[0].Finished)
+
+	dns := model.GetMeasurement{Type: "dns", Results: []model.MeasurementResponse{{
+		Probe:  model.ProbeData{Country: "DE", Network: "Hetzner"},
+		Result: model.ResultData{Status: "finished", Timings: &model.Timings{Total: ms(12)}},
+	}}}
+	assert.Equal(t, `NETWORK  PROBES  SUCCESS  MEAN      MEDIAN
+Hetzner  1       100%     12.00 ms  12.00 ms`, client.FormatGroups(dns, model.Context{GroupBy: "network"}))
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Header is a response header with every distinct value it was sent with
+type Header struct {
+	Name   string
+	Values []string
+}
+
+// ParseHeaders returns the response headers of an http result in the order they were received. Repeated headers are
+// merged under the first spelling of their name and identical values are kept once. Results without raw headers fall
+// back to the decoded headers sorted by name.
+func ParseHeaders(result model.MeasurementResponse) []Header {
+	var headers []Header
+	index := map[string]int{}
+	add := func(name, value string) {
+		key := strings.ToLower(name)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(headers)
+			headers = append(headers, Header{Name: name, Values: []string{value}})
+			return
+		}
+		for _, v := range headers[i].Values {
+			if v == value {
+				return
+			}
+		}
+		headers[i].Values = append(headers[i].Values, value)
+	}
+
+	if result.Result.RawHeaders != "" {
+		for _, line := range strings.Split(result.Result.RawHeaders, "\n") {
+			name, value, ok := strings.Cut(line, ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				continue
+			}
+			add(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		return headers
+	}
+
+	names := make([]string, 0, len(result.Result.Headers))
+	for name := range result.Result.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch v := result.Result.Headers[name].(type) {
+		case []interface{}:
+			for _, value := range v {
+				add(name, fmt.Sprint(value))
+			}
+		default:
+			add(name, fmt.Sprint(v))
+		}
+	}
+	return headers
+}
+
+// Values of a header matched case-insensitively
+func findHeader(headers []Header, name string) ([]string, bool) {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Values, true
+		}
+	}
+	return nil, false
+}
+
+// Headers reporting whether a cache served the response, in order of preference
+var cacheStatusHeaders = []string{"Cache-Status", "CF-Cache-Status", "X-Cache", "X-Cache-Status", "X-Proxy-Cache", "Cache"}
+
+// Name and values of the first cache status header of a response
+func cacheStatus(headers []Header) (string, string, bool) {
+	for _, name := range cacheStatusHeaders {
+		if values, ok := findHeader(headers, name); ok {
+			return name, strings.Join(values, ", "), true
+		}
+	}
+	return "", "", false
+}
+
+// AnalyzeHeaders returns a line per quick check of the response of a probe: the CDN that served it, its cache status
+// and age, compression, HSTS policy and redirect target
+func AnalyzeHeaders(result model.MeasurementResponse) []string {
+	headers := ParseHeaders(result)
+
+	cache := "not reported"
+	if name, value, ok := cacheStatus(headers); ok {
+		cache = fmt.Sprintf("%s (%s)", value, name)
+	}
+	if age, ok := findHeader(headers, "Age"); ok {
+		cache += fmt.Sprintf(", age %ss", age[0])
+	}
+
+	compression := "none"
+	if values, ok := findHeader(headers, "Content-Encoding"); ok {
+		compression = strings.Join(values, ", ")
+	}
+
+	hsts := "missing"
+	if values, ok := findHeader(headers, "Strict-Transport-Security"); ok {
+		hsts = values[0]
+	} else if result.Result.TLS == nil {
+		hsts = "missing, the response was not sent over https"
+	}
+
+	cdn := "not detected"
+	if info, ok := DetectCDN(result); ok {
+		cdn = info.CDN
+		if info.POP != "" {
+			cdn += ", POP " + info.POP
+		}
+	}
+
+	lines := []string{"CDN: " + cdn, "Cache: " + cache, "Compression: " + compression, "HSTS: " + hsts}
+	if code := result.Result.StatusCode; code >= 300 && code < 400 {
+		target := "no Location header"
+		if values, ok := findHeader(headers, "Location"); ok {
+			target = "to " + values[0]
+		}
+		lines = append(lines, fmt.Sprintf("Redirect: %d %s", code, target))
+	}
+	return lines
+}
+
+// FormatHeaderAnalysis renders the response headers of every probe as a table followed by their analysis
+func FormatHeaderAnalysis(data model.GetMeasurement, ctx model.Context) string {
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+		headers := ParseHeaders(result)
+		if len(headers) == 0 {
+			output.WriteString(fmt.Sprintf("No response headers, probe %s\n\n", result.Result.Status))
+			continue
+		}
+
+		w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+		for _, h := range headers {
+			// Every value of a repeated header is on its own line
+			for i, v := range h.Values {
+				name := h.Name
+				if i > 0 {
+					name = ""
+				}
+				fmt.Fprintf(w, "%s\t%s\n", name, v)
+			}
+		}
+		w.Flush()
+
+		output.WriteString("\n")
+		for _, line := range AnalyzeHeaders(result) {
+			output.WriteString(line + "\n")
+		}
+		output.WriteString("\n")
+	}
+	return strings.TrimSpace(output.String())
+}
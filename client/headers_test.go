@@ -0,0 +1,85 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHeaders(t *testing.T) {
+	result := model.MeasurementResponse{Result: model.ResultData{
+		RawHeaders: "Server: nginx\nCache: MISS\nX-ID: am3-up-gc88\ncache: MISS\nX-ID: td2-up-gc10",
+		// Ignored when the raw headers are known
+		Headers: map[string]interface{}{"server": "nginx"},
+	}}
+	assert.Equal(t, []client.Header{
+		{Name: "Server", Values: []string{"nginx"}},
+		{Name: "Cache", Values: []string{"MISS"}},
+		{Name: "X-ID", Values: []string{"am3-up-gc88", "td2-up-gc10"}},
+	}, client.ParseHeaders(result))
+
+	result = model.MeasurementResponse{Result: model.ResultData{
+		Headers: map[string]interface{}{"x-id": []interface{}{"a", "b", "a"}, "age": "12"},
+	}}
+	assert.Equal(t, []client.Header{
+		{Name: "age", Values: []string{"12"}},
+		{Name: "x-id", Values: []string{"a", "b"}},
+	}, client.ParseHeaders(result))
+}
+
+func TestAnalyzeHeaders(t *testing.T) {
+	cached := model.MeasurementResponse{Result: model.ResultData{
+		StatusCode: 200,
+		TLS:        &model.TLSCertificate{Authorized: true},
+		RawHeaders: "Content-Encoding: br\nCF-Cache-Status: HIT\nAge: 120\nStrict-Transport-Security: max-age=31536000; includeSubDomains",
+	}}
+	assert.Equal(t, []string{
+		"CDN: Cloudflare",
+		"Cache: HIT (CF-Cache-Status), age 120s",
+		"Compression: br",
+		"HSTS: max-age=31536000; includeSubDomains",
+	}, client.AnalyzeHeaders(cached))
+
+	redirect := model.MeasurementResponse{Result: model.ResultData{
+		StatusCode: 301,
+		RawHeaders: "Location: https://www.jsdelivr.com/",
+	}}
+	assert.Equal(t, []string{
+		"CDN: not detected",
+		"Cache: not reported",
+		"Compression: none",
+		"HSTS: missing, the response was not sent over https",
+		"Redirect: 301 to https://www.jsdelivr.com/",
+	}, client.AnalyzeHeaders(redirect))
+}
+
+func TestFormatHeaderAnalysis(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		{
+			Probe: model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+			Result: model.ResultData{Status: "finished", StatusCode: 200, TLS: &model.TLSCertificate{},
+				RawHeaders: "Content-Type: text/html\nX-Cache: HIT\nX-Served-By: cache-fra1\nX-Served-By: cache-ber2"},
+		},
+		{
+			Probe:  model.ProbeData{Continent: "EU", Country: "FR", City: "Paris", ASN: 2, Network: "Network"},
+			Result: model.ResultData{Status: "failed"},
+		},
+	}}
+
+	assert.Equal(t, `> EU, DE, Berlin, ASN:1, Network
+Content-Type  text/html
+X-Cache       HIT
+X-Served-By   cache-fra1
+              cache-ber2
+
+CDN: Fastly
+Cache: HIT (X-Cache)
+Compression: none
+HSTS: missing
+
+> EU, FR, Paris, ASN:2, Network
+No response headers, probe failed`, client.FormatHeaderAnalysis(data, model.Context{CI: true}))
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// APIHealth is the outcome of a request to the root of the API
+type APIHealth struct {
+	Url        string
+	StatusCode int
+	Latency    time.Duration
+}
+
+// Return the base URL of the API, e.g. https://api.globalping.io/v1
+func baseUrl() string {
+	return strings.TrimSuffix(ApiUrl, "/measurements")
+}
+
+// CheckAPI sends one request to the root of the API and measures the time until the response headers are received,
+// any response means the API is reachable
+func CheckAPI(c context.Context) (APIHealth, error) {
+	health := APIHealth{Url: baseUrl()}
+
+	start := time.Now()
+	status, err := api(health.Url, "").Reachable(c)
+	if err != nil {
+		return health, requestError(err, "err: the API is unreachable")
+	}
+
+	health.StatusCode = status
+	health.Latency = time.Since(start)
+	return health, nil
+}
@@ -0,0 +1,33 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAPI(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL + "/v1/measurements"
+
+	health, err := client.CheckAPI(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/v1", health.Url)
+	assert.Equal(t, "/v1", path)
+	assert.Equal(t, http.StatusNotFound, health.StatusCode)
+	assert.Greater(t, health.Latency.Nanoseconds(), int64(0))
+
+	server.Close()
+	_, err = client.CheckAPI(context.Background())
+	assert.EqualError(t, err, "err: the API is unreachable")
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// InfluxExporter pushes results to the write endpoint of InfluxDB v2, also accepted by Telegraf's influxdb_v2_listener
+type InfluxExporter struct {
+	Url    string
+	Org    string
+	Bucket string
+	Token  string
+	// BatchSize is the maximum number of lines sent in one request
+	BatchSize int
+	// Retries is the number of additional attempts of a failed request
+	Retries int
+	// Backoff is the delay before the first retry, doubled for every following one
+	Backoff time.Duration
+}
+
+// NewInfluxExporter creates an exporter with the default batching and retry settings
+func NewInfluxExporter(url, org, bucket, token string) *InfluxExporter {
+	return &InfluxExporter{
+		Url:       url,
+		Org:       org,
+		Bucket:    bucket,
+		Token:     token,
+		BatchSize: 500,
+		Retries:   3,
+		Backoff:   time.Second,
+	}
+}
+
+// Escape a tag key or value of the line protocol
+func influxEscape(v string) string {
+	return strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `).Replace(v)
+}
+
+// InfluxLines converts every probe result of a measurement into a line of the InfluxDB line protocol
+func InfluxLines(data model.GetMeasurement, ctx model.Context, now time.Time) []string {
+	var lines []string
+	for _, r := range LogRecords(data, ctx, now) {
+		tags := []string{
+			"globalping_" + r.Type,
+			"target=" + influxEscape(r.Target),
+			"continent=" + influxEscape(r.Probe.Continent),
+			"country=" + influxEscape(r.Probe.Country),
+			"city=" + influxEscape(r.Probe.City),
+			"asn=" + strconv.Itoa(r.Probe.ASN),
+		}
+		if r.Probe.Network != "" {
+			tags = append(tags, "network="+influxEscape(r.Probe.Network))
+		}
+		// Empty tag values are not allowed
+		filtered := tags[:1]
+		for _, t := range tags[1:] {
+			if !strings.HasSuffix(t, "=") {
+				filtered = append(filtered, t)
+			}
+		}
+
+		success := 0
+		if r.Status == "finished" {
+			success = 1
+		}
+		fields := []string{fmt.Sprintf("success=%di", success)}
+		keys := make([]string, 0, len(r.Metrics))
+		for k := range r.Metrics {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fields = append(fields, k+"="+strconv.FormatFloat(r.Metrics[k], 'g', -1, 64))
+		}
+
+		lines = append(lines, strings.Join(filtered, ",")+" "+strings.Join(fields, ",")+" "+strconv.FormatInt(r.Timestamp.UnixNano(), 10))
+	}
+	return lines
+}
+
+// Push sends the lines in batches, retrying failed requests with an exponential backoff
+func (e *InfluxExporter) Push(lines []string) error {
+	if e.Url == "" || e.Bucket == "" {
+		return errors.New("err: the InfluxDB url and bucket are required")
+	}
+
+	size := e.BatchSize
+	if size <= 0 {
+		size = len(lines)
+	}
+	for start := 0; start < len(lines); start += size {
+		end := start + size
+		if end > len(lines) {
+			end = len(lines)
+		}
+		err := e.write(strings.Join(lines[start:end], "\n"))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Send one batch, client errors are not retried since the same request would fail again
+func (e *InfluxExporter) write(body string) error {
+	q := url.Values{}
+	q.Set("bucket", e.Bucket)
+	q.Set("precision", "ns")
+	if e.Org != "" {
+		q.Set("org", e.Org)
+	}
+	endpoint := strings.TrimRight(e.Url, "/") + "/api/v2/write?" + q.Encode()
+
+	backoff := e.Backoff
+	var lastErr error
+	for attempt := 0; attempt <= e.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		req, err := http.NewRequest("POST", endpoint, strings.NewReader(body))
+		if err != nil {
+			return errors.New("err: failed to create request - please report this bug")
+		}
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		if e.Token != "" {
+			req.Header.Set("Authorization", "Token "+e.Token)
+		}
+
+		client := httpClient()
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = errors.New("err: failed to push the results to InfluxDB")
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("err: InfluxDB responded with status %d", resp.StatusCode)
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return lastErr
+		}
+	}
+	return lastErr
+}
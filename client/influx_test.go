@@ -0,0 +1,68 @@
+package client_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfluxLines(t *testing.T) {
+	result := pingResult("New York", 10)
+	result.Probe.Network = "Example, Inc."
+	data := model.GetMeasurement{Type: "ping", Results: []model.MeasurementResponse{result}}
+
+	lines := client.InfluxLines(data, model.Context{Target: "google.com"}, time.Unix(1700000000, 0))
+	assert.Equal(t, []string{
+		`globalping_ping,target=google.com,country=DE,city=New\ York,asn=1,network=Example\,\ Inc. success=1i,avg=10,loss=0 1700000000000000000`,
+	}, lines)
+}
+
+func TestInfluxExporterPush(t *testing.T) {
+	var bodies []string
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/write", r.URL.Path)
+		assert.Equal(t, "globalping", r.URL.Query().Get("bucket"))
+		assert.Equal(t, "Token secret", r.Header.Get("Authorization"))
+
+		// The first request fails and is retried
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	e := client.NewInfluxExporter(server.URL, "acme", "globalping", "secret")
+	e.BatchSize = 2
+	e.Backoff = 0
+
+	assert.NoError(t, e.Push([]string{"a", "b", "c"}))
+	assert.Equal(t, []string{"a\nb", "c"}, bodies)
+}
+
+func TestInfluxExporterClientError(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	e := client.NewInfluxExporter(server.URL, "acme", "globalping", "wrong")
+	e.Backoff = 0
+
+	assert.EqualError(t, e.Push([]string{"a"}), "err: InfluxDB responded with status 401")
+	assert.Equal(t, 1, requests)
+}
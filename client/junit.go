@@ -0,0 +1,74 @@
+package client
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// FormatJUnit renders one test case per probe, failing the probes that do not finish or exceed the thresholds
+func FormatJUnit(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+
+	suite := junitTestSuite{
+		Name:      "globalping " + cmd + " " + ctx.Target,
+		Tests:     len(data.Results),
+		Timestamp: data.CreatedAt,
+	}
+
+	tolerate := probeErrorsTolerated(data, ctx.Thresholds.FailOnProbeErrors)
+	for _, result := range data.Results {
+		tc := junitTestCase{
+			Name:      probeLabel(result.Probe),
+			ClassName: "globalping." + cmd,
+			SystemOut: strings.TrimSpace(result.Result.RawOutput),
+		}
+		if v, ok := KeyMetric(cmd, result); ok {
+			tc.Time = v / 1000
+		}
+
+		reasons := resultViolations(cmd, result, ctx.Thresholds, tolerate)
+		if len(reasons) > 0 {
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: reasons[0],
+				Text:    strings.Join(reasons, "\n"),
+			}
+		}
+
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	b, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(b), nil
+}
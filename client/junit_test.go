@@ -0,0 +1,36 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatJUnit(t *testing.T) {
+	fast := pingResult("Berlin", 20)
+	fast.Result.RawOutput = "PING google.com"
+	data := model.GetMeasurement{
+		Type:      "ping",
+		CreatedAt: "2023-02-17T18:11:52.825Z",
+		Results:   []model.MeasurementResponse{fast, pingResult("Munich", 150)},
+	}
+	ctx := model.Context{Target: "google.com", Thresholds: model.Thresholds{MaxLatency: 100 * time.Millisecond}}
+
+	output, err := client.FormatJUnit(data, ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="globalping ping google.com" tests="2" failures="1" timestamp="2023-02-17T18:11:52.825Z">
+    <testcase name="Berlin, DE, ASN:1" classname="globalping.ping" time="0.02">
+      <system-out>PING google.com</system-out>
+    </testcase>
+    <testcase name="Munich, DE, ASN:1" classname="globalping.ping" time="0.15">
+      <failure message="latency 150.00 ms exceeds 100ms">latency 150.00 ms exceeds 100ms</failure>
+    </testcase>
+  </testsuite>
+</testsuites>`, output)
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/pkg/globalping"
+)
+
+var LimitsApiUrl = "https://api.globalping.io/v1/limits"
+
+var (
+	lastRateLimitMu sync.Mutex
+	lastRateLimit   model.RateLimit
+)
+
+// LastRateLimit returns the rate limit headers of the latest measurement request, measurements posted at the same
+// time get their own headers in their PostResponse or APIError
+func LastRateLimit() model.RateLimit {
+	lastRateLimitMu.Lock()
+	defer lastRateLimitMu.Unlock()
+	return lastRateLimit
+}
+
+func setLastRateLimit(rl model.RateLimit) {
+	lastRateLimitMu.Lock()
+	defer lastRateLimitMu.Unlock()
+	lastRateLimit = rl
+}
+
+// Explain an error of a request rejected because of the rate limit
+func rateLimitMessage(rl model.RateLimit) string {
+	if !rl.Set {
+		return "err: rate limit exceeded - please try again later"
+	}
+
+	msg := fmt.Sprintf("err: rate limit exceeded - %d of %d measurements remaining, resets in %s", rl.Remaining, rl.Limit, time.Duration(rl.Reset)*time.Second)
+	if rl.CreditsRemaining > 0 {
+		msg += fmt.Sprintf(" (%d credits remaining)", rl.CreditsRemaining)
+	}
+	return msg
+}
+
+// Get the current rate limits and credits from Globalping API
+func GetLimits(c context.Context) (model.Limits, error) {
+	data, err := api(LimitsApiUrl, "limits").Limits(c)
+	if err != nil {
+		var apiErr *APIError
+		switch {
+		case errors.As(err, &apiErr):
+			return model.Limits{}, errors.New("err: failed to fetch limits - please try again later")
+		case errors.Is(err, globalping.ErrInvalidResponse):
+			return model.Limits{}, errors.New("invalid limits format returned")
+		}
+		return model.Limits{}, requestError(err, "err: request failed")
+	}
+	return data, nil
+}
+
+// MaxLimit is the maximum number of probes of a measurement, and of every location of a measurement, accepted by the API
+const MaxLimit = 500
+
+// ValidateLimit checks the number of probes of a measurement against the maximums of the API before it is posted
+func ValidateLimit(m model.PostMeasurement) error {
+	if m.Limit < 1 || m.Limit > MaxLimit {
+		return fmt.Errorf("the limit must be between 1 and %d probes, got %d", MaxLimit, m.Limit)
+	}
+	for _, l := range m.Locations {
+		if l.Limit < 0 || l.Limit > MaxLimit {
+			return fmt.Errorf("the limit of %s must be between 1 and %d probes, got %d", locationName(l), MaxLimit, l.Limit)
+		}
+	}
+	return nil
+}
+
+// Name of a location as given on the command line, or its first filter
+func locationName(l model.Locations) string {
+	for _, v := range []string{l.Magic, l.City, l.State, l.Country, l.Region, l.Continent, l.Network} {
+		if v != "" {
+			return v
+		}
+	}
+	if l.ASN != 0 {
+		return "AS" + strconv.Itoa(l.ASN)
+	}
+	return "world"
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
+
+// CostPreview describes the probes the measurements would use and their cost, with the remaining rate limit and
+// credits when they are known. The limit is a maximum, fewer probes are used when fewer match the locations.
+func CostPreview(measurements []model.PostMeasurement, limits *model.Limits) string {
+	var output strings.Builder
+	output.WriteString("Dry run, no measurement was created\n")
+
+	total := 0
+	for _, m := range measurements {
+		from := make([]string, len(m.Locations))
+		for i, l := range m.Locations {
+			from[i] = locationName(l)
+			if l.Limit > 0 {
+				from[i] += ":" + strconv.Itoa(l.Limit)
+			}
+		}
+		output.WriteString(fmt.Sprintf("  %s %s from %s: up to %s\n", m.Type, m.Target, strings.Join(from, ", "), plural(m.Limit, "probe")))
+		total += m.Limit
+	}
+	output.WriteString(fmt.Sprintf("Cost: up to %s, 1 per probe\n", plural(total, "credit")))
+
+	if limits != nil {
+		create := limits.RateLimit.Measurements.Create
+		output.WriteString(fmt.Sprintf("Remaining: %d of %d measurements", create.Remaining, create.Limit))
+		if limits.Credits != nil {
+			output.WriteString(fmt.Sprintf(", %d credits", limits.Credits.Remaining))
+		}
+		output.WriteString("\n")
+	}
+	return strings.TrimSpace(output.String())
+}
@@ -0,0 +1,94 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "250")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "90")
+		w.Header().Set("X-Credits-Remaining", "12")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"type":"too_many_requests","message":"Too Many Requests"}}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	_, err := client.PostAPI(context.Background(), opts)
+	assert.EqualError(t, err, "err: rate limit exceeded - 0 of 250 measurements remaining, resets in 1m30s (12 credits remaining)")
+	assert.False(t, client.IsUsageError(err))
+	var apiErr *client.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 250, apiErr.RateLimit.Limit)
+	assert.True(t, apiErr.RateLimit.Set)
+	assert.Equal(t, 250, client.LastRateLimit().Limit)
+}
+
+func TestPostRateLimitedNoHeaders(t *testing.T) {
+	server := generateServerError(`{}`, http.StatusTooManyRequests)
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	_, err := client.PostAPI(context.Background(), opts)
+	assert.EqualError(t, err, "err: rate limit exceeded - please try again later")
+}
+
+func TestGetLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rateLimit":{"measurements":{"create":{"type":"ip","limit":250,"remaining":240,"reset":1800}}},"credits":{"remaining":500}}`))
+	}))
+	defer server.Close()
+	client.LimitsApiUrl = server.URL
+
+	limits, err := client.GetLimits(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "ip", limits.RateLimit.Measurements.Create.Type)
+	assert.Equal(t, 250, limits.RateLimit.Measurements.Create.Limit)
+	assert.Equal(t, 240, limits.RateLimit.Measurements.Create.Remaining)
+	assert.Equal(t, 1800, limits.RateLimit.Measurements.Create.Reset)
+	assert.Equal(t, 500, limits.Credits.Remaining)
+}
+
+func TestValidateLimit(t *testing.T) {
+	assert.NoError(t, client.ValidateLimit(model.PostMeasurement{Limit: 500, Locations: []model.Locations{{Magic: "Europe"}}}))
+	assert.EqualError(t, client.ValidateLimit(model.PostMeasurement{Limit: 0}), "the limit must be between 1 and 500 probes, got 0")
+	assert.EqualError(t, client.ValidateLimit(model.PostMeasurement{Limit: 501}), "the limit must be between 1 and 500 probes, got 501")
+	assert.EqualError(t, client.ValidateLimit(model.PostMeasurement{
+		Limit:     600,
+		Locations: []model.Locations{{Magic: "Germany", Limit: 100}, {Country: "US", Limit: 600}},
+	}), "the limit must be between 1 and 500 probes, got 600")
+	assert.EqualError(t, client.ValidateLimit(model.PostMeasurement{
+		Limit:     10,
+		Locations: []model.Locations{{Country: "US", Limit: 600}},
+	}), "the limit of US must be between 1 and 500 probes, got 600")
+}
+
+func TestCostPreview(t *testing.T) {
+	measurements := []model.PostMeasurement{
+		{Type: "ping", Target: "jsdelivr.com", Limit: 5, Locations: []model.Locations{{Magic: "Germany", Limit: 2}, {Magic: "Japan", Limit: 3}}},
+		{Type: "ping", Target: "google.com", Limit: 1, Locations: []model.Locations{{Country: "FR"}}},
+	}
+
+	assert.Equal(t, `Dry run, no measurement was created
+  ping jsdelivr.com from Germany:2, Japan:3: up to 5 probes
+  ping google.com from FR: up to 1 probe
+Cost: up to 6 credits, 1 per probe`, client.CostPreview(measurements, nil))
+
+	var limits model.Limits
+	limits.RateLimit.Measurements.Create = model.RateLimitDetails{Limit: 250, Remaining: 240}
+	limits.Credits = &model.CreditLimits{Remaining: 1000}
+	assert.Equal(t, `Dry run, no measurement was created
+  ping google.com from FR: up to 1 probe
+Cost: up to 1 credit, 1 per probe
+Remaining: 240 of 250 measurements, 1000 credits`, client.CostPreview(measurements[1:], &limits))
+}
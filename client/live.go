@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// LiveLog turns the snapshots of an in-progress measurement into an append-only log: only the lines of the raw
+// output that are new or changed since the previous snapshot are printed, so it works in pipes and CI logs as well as
+// in a terminal. Lines are prefixed with their probe when there are several.
+type LiveLog struct {
+	ctx model.Context
+	// Lines of the raw output of every probe already printed
+	printed [][]string
+}
+
+func NewLiveLog(ctx model.Context) *LiveLog {
+	return &LiveLog{ctx: ctx}
+}
+
+// Indexes of the lines of cur that are new or differ from the line at the same position in prev
+func changedLines(prev, cur []string) []int {
+	var changed []int
+	for i, line := range cur {
+		if i >= len(prev) || prev[i] != line {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
+
+// Update returns the lines to print for a snapshot of the measurement. The last line of a probe still in progress
+// may be incomplete, it is held back until the probe sends more output or finishes.
+func (l *LiveLog) Update(data model.GetMeasurement, changed []int) string {
+	for len(l.printed) < len(data.Results) {
+		l.printed = append(l.printed, nil)
+	}
+	// Held back lines of the probes are flushed once the measurement is over, even if their output did not change
+	if data.Status != "in-progress" {
+		changed = make([]int, len(data.Results))
+		for i := range changed {
+			changed[i] = i
+		}
+	}
+
+	var output strings.Builder
+	for _, i := range changed {
+		result := data.Results[i]
+		raw := strings.TrimRight(result.Result.RawOutput, "\n")
+		if raw == "" {
+			continue
+		}
+
+		lines := strings.Split(raw, "\n")
+		if result.Result.Status == "in-progress" && !strings.HasSuffix(result.Result.RawOutput, "\n") {
+			lines = lines[:len(lines)-1]
+		}
+
+		prefix := ""
+		if len(data.Results) > 1 {
+			prefix = fmt.Sprintf("[%s, %s] ", result.Probe.City, result.Probe.Country)
+		}
+		diff := changedLines(l.printed[i], lines)
+		if l.printed[i] == nil && len(lines) > 0 {
+			output.WriteString(generateHeader(result, l.ctx) + "\n")
+		}
+		for _, n := range diff {
+			output.WriteString(prefix + lines[n] + "\n")
+		}
+		if len(lines) > 0 {
+			l.printed[i] = lines
+		}
+	}
+	return output.String()
+}
+
+// LiveLogResults prints the output of the probes line by line as it arrives and returns the final state of the
+// measurement, which must be posted with InProgressUpdates to get partial output
+func LiveLogResults(c context.Context, id string, ctx model.Context) (model.GetMeasurement, error) {
+	l := NewLiveLog(ctx)
+	var data model.GetMeasurement
+	for update := range StreamResults(c, id) {
+		if update.Err != nil {
+			return model.GetMeasurement{}, update.Err
+		}
+		data = update.Data
+		fmt.Print(l.Update(update.Data, update.Changed))
+	}
+	return data, nil
+}
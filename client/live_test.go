@@ -0,0 +1,41 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func liveResult(city, status, raw string) model.MeasurementResponse {
+	return model.MeasurementResponse{
+		Probe:  model.ProbeData{Continent: "EU", Country: "DE", City: city, ASN: 3320, Network: "Telekom"},
+		Result: model.ResultData{Status: status, RawOutput: raw},
+	}
+}
+
+func TestLiveLog(t *testing.T) {
+	l := client.NewLiveLog(model.Context{CI: true})
+
+	// The incomplete last line is held back
+	data := model.GetMeasurement{Status: "in-progress", Results: []model.MeasurementResponse{
+		liveResult("Berlin", "in-progress", "traceroute to jsdelivr.com\n 1  10.0.0.1  1 ms\n 2  10.0"),
+	}}
+	assert.Equal(t, "> EU, DE, Berlin, ASN:3320, Telekom\ntraceroute to jsdelivr.com\n 1  10.0.0.1  1 ms\n", l.Update(data, []int{0}))
+
+	// Only new and changed lines are printed
+	data.Results[0].Result.RawOutput = "traceroute to jsdelivr.com\n 1  10.0.0.1  2 ms\n 2  10.0.0.2  5 ms\n"
+	assert.Equal(t, " 1  10.0.0.1  2 ms\n 2  10.0.0.2  5 ms\n", l.Update(data, []int{0}))
+
+	// Several probes are prefixed with their location
+	data.Results = append(data.Results, liveResult("Munich", "in-progress", "traceroute to jsdelivr.com\n"))
+	assert.Equal(t, "> EU, DE, Munich, ASN:3320, Telekom\n[Munich, DE] traceroute to jsdelivr.com\n", l.Update(data, []int{1}))
+
+	// Held back lines are flushed once the measurement is finished
+	data.Status = "finished"
+	data.Results[1] = liveResult("Munich", "finished", "traceroute to jsdelivr.com\n 1  10.1.0.1  3 ms")
+	data.Results[0].Result.Status = "finished"
+	assert.Equal(t, "[Munich, DE]  1  10.1.0.1  3 ms\n", l.Update(data, nil))
+}
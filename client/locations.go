@@ -0,0 +1,342 @@
+package client
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Continents maps the continent code of a probe to its name, both are accepted as a location
+var Continents = map[string]string{
+	"AF": "Africa",
+	"AN": "Antarctica",
+	"AS": "Asia",
+	"EU": "Europe",
+	"NA": "North America",
+	"OC": "Oceania",
+	"SA": "South America",
+}
+
+// Regions are the geographic regions of the United Nations accepted as a location, e.g. "Western Europe"
+var Regions = []string{
+	"Northern Africa", "Eastern Africa", "Middle Africa", "Southern Africa", "Western Africa",
+	"Caribbean", "Central America", "South America", "Northern America",
+	"Central Asia", "Eastern Asia", "South-eastern Asia", "Southern Asia", "Western Asia",
+	"Eastern Europe", "Northern Europe", "Southern Europe", "Western Europe",
+	"Australia and New Zealand", "Melanesia", "Micronesia", "Polynesia",
+}
+
+// LocationKeywords returns the sorted magic location keywords: continents, regions and countries, with the cities,
+// US states, networks, ASNs and tags of the online probes
+func LocationKeywords(probes []model.Probe) []string {
+	seen := map[string]bool{"world": true}
+	add := func(k string) {
+		if k != "" {
+			seen[k] = true
+		}
+	}
+
+	for _, name := range Continents {
+		add(name)
+	}
+	for _, name := range Regions {
+		add(name)
+	}
+	for _, name := range Countries {
+		add(name)
+	}
+	for _, p := range probes {
+		add(p.Location.Region)
+		add(p.Location.City)
+		add(p.Location.State)
+		add(p.Location.Network)
+		if p.Location.ASN != 0 {
+			add("AS" + strconv.Itoa(p.Location.ASN))
+		}
+		for _, tag := range p.Tags {
+			add(tag)
+		}
+	}
+
+	keywords := make([]string, 0, len(seen))
+	for k := range seen {
+		keywords = append(keywords, k)
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		return strings.ToLower(keywords[i]) < strings.ToLower(keywords[j])
+	})
+	return keywords
+}
+
+// Location categories of OnlineLocations, in the order they are listed
+const (
+	CategoryContinent      = "continent"
+	CategoryRegion         = "region"
+	CategoryCountry        = "country"
+	CategoryState          = "US state"
+	CategoryCloud          = "cloud"
+	CategoryEyeballNetwork = "eyeball network"
+	CategoryNetwork        = "network"
+	CategoryTag            = "tag"
+)
+
+// LocationCategories lists every category of OnlineLocations in order
+var LocationCategories = []string{
+	CategoryContinent, CategoryRegion, CategoryCountry, CategoryState,
+	CategoryCloud, CategoryEyeballNetwork, CategoryNetwork, CategoryTag,
+}
+
+// Tags of the probes hosted by a cloud provider start with one of these prefixes, e.g. aws-eu-central-1
+var cloudPrefixes = []string{"aws-", "gcp-", "azure-", "oci-", "alibaba-"}
+
+// Location is a magic location keyword with the number of online probes it matches
+type Location struct {
+	Keyword  string `json:"keyword"`
+	Category string `json:"category"`
+	Probes   int    `json:"probes"`
+}
+
+// OnlineLocations returns the locations of the online probes by category: continents, regions, countries, US states,
+// cloud regions, networks of residential ISPs, other networks and tags, then by descending number of probes
+func OnlineLocations(probes []model.Probe) []Location {
+	counts := map[Location]int{}
+	count := func(category, keyword string) {
+		if keyword != "" {
+			counts[Location{Keyword: keyword, Category: category}]++
+		}
+	}
+
+	for _, p := range probes {
+		count(CategoryContinent, Continents[p.Location.Continent])
+		count(CategoryRegion, p.Location.Region)
+		count(CategoryCountry, Countries[p.Location.Country])
+		if p.Location.Country == "US" {
+			count(CategoryState, p.Location.State)
+		}
+		if hasTag(p.Tags, "eyeball-network") {
+			count(CategoryEyeballNetwork, p.Location.Network)
+		} else {
+			count(CategoryNetwork, p.Location.Network)
+		}
+		for _, tag := range p.Tags {
+			if isCloudTag(tag) {
+				count(CategoryCloud, tag)
+			} else {
+				count(CategoryTag, tag)
+			}
+		}
+	}
+
+	order := map[string]int{}
+	for i, c := range LocationCategories {
+		order[c] = i
+	}
+	locations := make([]Location, 0, len(counts))
+	for l, n := range counts {
+		l.Probes = n
+		locations = append(locations, l)
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		a, b := locations[i], locations[j]
+		if a.Category != b.Category {
+			return order[a.Category] < order[b.Category]
+		}
+		if a.Probes != b.Probes {
+			return a.Probes > b.Probes
+		}
+		return a.Keyword < b.Keyword
+	})
+	return locations
+}
+
+func isCloudTag(tag string) bool {
+	for _, prefix := range cloudPrefixes {
+		if strings.HasPrefix(tag, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Common names of countries accepted as a location in addition to the names of Countries
+var countryAliases = map[string]string{
+	"uk":      "GB",
+	"usa":     "US",
+	"america": "US",
+}
+
+// Return the lowercase keywords a probe is selected by: continent, region, country, state, city, ASN and tags
+func probeKeywords(p model.Probe) []string {
+	keywords := []string{
+		p.Location.Continent, Continents[p.Location.Continent], p.Location.Region,
+		p.Location.Country, Countries[p.Location.Country], p.Location.State, p.Location.City,
+		"as" + strconv.Itoa(p.Location.ASN),
+	}
+	keywords = append(keywords, p.Tags...)
+	for i, k := range keywords {
+		keywords[i] = strings.ToLower(k)
+	}
+	return keywords
+}
+
+// Check if a probe matches every filter of a location combined with +, e.g. Germany+AS3320
+func matchesProbe(location string, p model.Probe) bool {
+	keywords := probeKeywords(p)
+	for _, filter := range strings.Split(location, "+") {
+		filter = strings.ToLower(strings.TrimSpace(filter))
+		if code, ok := countryAliases[filter]; ok {
+			filter = strings.ToLower(code)
+		}
+
+		matched := filter == "world" || strings.Contains(strings.ToLower(p.Location.Network), filter)
+		for _, k := range keywords {
+			if k == filter {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// UnmatchedLocations returns the locations no online probe matches, the API would answer them with no_probes_found
+func UnmatchedLocations(locations []model.Locations, probes []model.Probe) []string {
+	var unmatched []string
+	for _, l := range locations {
+		matched := false
+		for _, p := range probes {
+			if matchesProbe(l.Magic, p) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, l.Magic)
+		}
+	}
+	return unmatched
+}
+
+// SuggestLocations returns up to n locations of the online probes closest to a mistyped location, tags are left out
+func SuggestLocations(location string, probes []model.Probe, n int) []string {
+	location = strings.ToLower(location)
+	// Allow about one typo every three characters
+	maxDistance := len(location)/3 + 1
+
+	type suggestion struct {
+		keyword  string
+		distance int
+	}
+	seen := map[string]bool{}
+	var suggestions []suggestion
+	for _, p := range probes {
+		keywords := []string{
+			Continents[p.Location.Continent], p.Location.Region, Countries[p.Location.Country], p.Location.State,
+			p.Location.City, p.Location.Network, "AS" + strconv.Itoa(p.Location.ASN),
+		}
+		for _, k := range keywords {
+			if k == "" || seen[k] {
+				continue
+			}
+			seen[k] = true
+			if d := levenshtein(location, strings.ToLower(k)); d <= maxDistance {
+				suggestions = append(suggestions, suggestion{k, d})
+			}
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].keyword < suggestions[j].keyword
+	})
+
+	var keywords []string
+	for i := 0; i < len(suggestions) && i < n; i++ {
+		keywords = append(keywords, suggestions[i].keyword)
+	}
+	return keywords
+}
+
+// Number of single character insertions, deletions or substitutions turning a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j] + 1
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if prev[j-1]+cost < cur[j] {
+				cur[j] = prev[j-1] + cost
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// ParseLocations converts a comma separated list of locations into the locations of a measurement, a location group
+// can set its own limit, e.g. Germany:3
+func ParseLocations(from string) []model.Locations {
+	fromArr := strings.Split(from, ",")
+	locations := make([]model.Locations, len(fromArr))
+	for i, v := range fromArr {
+		v = strings.TrimSpace(v)
+		locations[i] = model.Locations{
+			Magic: v,
+		}
+		if idx := strings.LastIndex(v, ":"); idx > 0 {
+			if limit, err := strconv.Atoi(v[idx+1:]); err == nil && limit > 0 {
+				locations[i] = model.Locations{
+					Magic: strings.TrimSpace(v[:idx]),
+					Limit: limit,
+				}
+			}
+		}
+	}
+	return locations
+}
+
+// ApplyLocationLimits sets the limit of a measurement to the total of its location groups when one of them has its own
+// limit, the groups without their own limit use the limit of the measurement, e.g. Germany:2,Japan with a limit of 3
+// requests 2 probes in Germany and 3 in Japan
+func ApplyLocationLimits(m model.PostMeasurement) model.PostMeasurement {
+	grouped := false
+	for _, l := range m.Locations {
+		if l.Limit > 0 {
+			grouped = true
+		}
+	}
+	if !grouped {
+		return m
+	}
+
+	locations := make([]model.Locations, len(m.Locations))
+	total := 0
+	for i, l := range m.Locations {
+		if l.Limit == 0 {
+			l.Limit = m.Limit
+		}
+		locations[i] = l
+		total += l.Limit
+	}
+	m.Locations = locations
+	m.Limit = total
+	return m
+}
@@ -0,0 +1,105 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationKeywords(t *testing.T) {
+	probes := []model.Probe{
+		{Location: model.ProbeLocation{Continent: "EU", Region: "Western Europe", Country: "DE", City: "Frankfurt", ASN: 3320, Network: "Deutsche Telekom AG"}, Tags: []string{"eyeball-network"}},
+		{Location: model.ProbeLocation{Continent: "NA", Region: "Northern America", Country: "US", State: "TX", City: "Dallas", ASN: 16509, Network: "Amazon.com, Inc."}, Tags: []string{"aws-us-east-1"}},
+	}
+
+	keywords := client.LocationKeywords(probes)
+	for _, k := range []string{"world", "Europe", "Western Europe", "Germany", "Frankfurt", "TX", "Dallas", "AS3320", "Deutsche Telekom AG", "eyeball-network", "aws-us-east-1"} {
+		assert.Contains(t, keywords, k)
+	}
+	assert.Less(t, indexOf(keywords, "AS16509"), indexOf(keywords, "aws-us-east-1"))
+
+	// Static keywords are returned without probes
+	assert.Contains(t, client.LocationKeywords(nil), "South America")
+}
+
+func indexOf(values []string, v string) int {
+	for i, value := range values {
+		if value == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestOnlineLocations(t *testing.T) {
+	probes := []model.Probe{
+		{Location: model.ProbeLocation{Continent: "EU", Country: "DE", Network: "Deutsche Telekom AG"}},
+		{Location: model.ProbeLocation{Continent: "EU", Country: "FR", Network: "Amazon.com, Inc."}, Tags: []string{"aws-eu-west-3", "datacenter-network"}},
+		{Location: model.ProbeLocation{Continent: "EU", Country: "DE", Network: "Deutsche Telekom AG"}},
+	}
+
+	assert.Equal(t, []client.Location{
+		{Keyword: "Europe", Category: client.CategoryContinent, Probes: 3},
+		{Keyword: "Germany", Category: client.CategoryCountry, Probes: 2},
+		{Keyword: "France", Category: client.CategoryCountry, Probes: 1},
+		{Keyword: "aws-eu-west-3", Category: client.CategoryCloud, Probes: 1},
+		{Keyword: "Deutsche Telekom AG", Category: client.CategoryNetwork, Probes: 2},
+		{Keyword: "Amazon.com, Inc.", Category: client.CategoryNetwork, Probes: 1},
+		{Keyword: "datacenter-network", Category: client.CategoryTag, Probes: 1},
+	}, client.OnlineLocations(probes))
+}
+
+func TestOnlineLocationsCategories(t *testing.T) {
+	probes := []model.Probe{
+		{Location: model.ProbeLocation{Continent: "NA", Region: "Northern America", Country: "US", State: "TX", Network: "Comcast"}, Tags: []string{"eyeball-network"}},
+		{Location: model.ProbeLocation{Continent: "NA", Region: "Northern America", Country: "CA", State: "QC", Network: "OVH SAS"}},
+	}
+
+	assert.Equal(t, []client.Location{
+		{Keyword: "North America", Category: client.CategoryContinent, Probes: 2},
+		{Keyword: "Northern America", Category: client.CategoryRegion, Probes: 2},
+		{Keyword: "Canada", Category: client.CategoryCountry, Probes: 1},
+		{Keyword: "United States", Category: client.CategoryCountry, Probes: 1},
+		{Keyword: "TX", Category: client.CategoryState, Probes: 1},
+		{Keyword: "Comcast", Category: client.CategoryEyeballNetwork, Probes: 1},
+		{Keyword: "OVH SAS", Category: client.CategoryNetwork, Probes: 1},
+		{Keyword: "eyeball-network", Category: client.CategoryTag, Probes: 1},
+	}, client.OnlineLocations(probes))
+}
+
+var onlineProbes = []model.Probe{
+	{Location: model.ProbeLocation{Continent: "EU", Region: "Western Europe", Country: "DE", City: "Frankfurt", ASN: 3320, Network: "Deutsche Telekom AG"}},
+	{Location: model.ProbeLocation{Continent: "NA", Region: "Northern America", Country: "US", State: "TX", City: "Dallas", ASN: 16509, Network: "Amazon.com, Inc."}, Tags: []string{"aws-us-east-1"}},
+}
+
+func TestUnmatchedLocations(t *testing.T) {
+	locations := []model.Locations{
+		{Magic: "world"}, {Magic: "Europe"}, {Magic: "eu"}, {Magic: "western europe"}, {Magic: "Germany"},
+		{Magic: "usa"}, {Magic: "TX"}, {Magic: "frankfurt"}, {Magic: "AS3320"}, {Magic: "telekom"},
+		{Magic: "aws-us-east-1"}, {Magic: "Germany+AS3320"},
+		{Magic: "frankfrut"}, {Magic: "Germany+AS16509"}, {Magic: "Japan"},
+	}
+
+	assert.Equal(t, []string{"frankfrut", "Germany+AS16509", "Japan"}, client.UnmatchedLocations(locations, onlineProbes))
+}
+
+func TestSuggestLocations(t *testing.T) {
+	assert.Equal(t, []string{"Frankfurt"}, client.SuggestLocations("frankfrut", onlineProbes, 3))
+	assert.Equal(t, []string{"Germany"}, client.SuggestLocations("Germny", onlineProbes, 3))
+	assert.Empty(t, client.SuggestLocations("xyzxyzxyz", onlineProbes, 3))
+	assert.Equal(t, []string{"Dallas"}, client.SuggestLocations("dalas", onlineProbes, 3))
+	assert.Equal(t, []string{"AS3320"}, client.SuggestLocations("as332", onlineProbes, 1))
+}
+
+func TestApplyLocationLimits(t *testing.T) {
+	m := client.ApplyLocationLimits(model.PostMeasurement{Limit: 3, Locations: client.ParseLocations("Germany:2,Japan")})
+	assert.Equal(t, 5, m.Limit)
+	assert.Equal(t, []model.Locations{{Magic: "Germany", Limit: 2}, {Magic: "Japan", Limit: 3}}, m.Locations)
+
+	m = client.ApplyLocationLimits(model.PostMeasurement{Limit: 3, Locations: client.ParseLocations("Germany,Japan")})
+	assert.Equal(t, 3, m.Limit)
+	assert.Equal(t, []model.Locations{{Magic: "Germany"}, {Magic: "Japan"}}, m.Locations)
+}
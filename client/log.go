@@ -0,0 +1,99 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"regexp"
+)
+
+// LogLevel selects the messages written to the log
+type LogLevel int
+
+const (
+	// LevelQuiet only prints the final results, without progress or status messages
+	LevelQuiet LogLevel = iota
+	LevelNormal
+	// LevelVerbose also logs request payloads, polling attempts, retries and rate limit headers
+	LevelVerbose
+	// LevelDebug also dumps the raw HTTP traffic, with credentials redacted
+	LevelDebug
+)
+
+// Level of the messages written to Log
+var Level = LevelNormal
+
+// Log receives the messages of the enabled levels, stderr by default so the results on stdout can be piped
+var Log io.Writer = os.Stderr
+
+// Logf writes a message to Log if the level is enabled
+func Logf(level LogLevel, format string, args ...interface{}) {
+	if Level < level {
+		return
+	}
+	fmt.Fprintf(Log, format+"\n", args...)
+}
+
+// Credentials sent or received in headers are never written to the log
+var secretHeader = regexp.MustCompile(`(?im)^((?:Proxy-)?Authorization|Cookie|Set-Cookie|X-Api-Key):[^\r\n]*`)
+
+// Redact replaces the value of the headers holding credentials in a dump of an HTTP request or response
+func Redact(dump string) string {
+	return secretHeader.ReplaceAllString(dump, "$1: [redacted]")
+}
+
+// Dump the raw request at the debug level, the body is kept readable for the request
+func debugRequest(req *http.Request) {
+	if Level < LevelDebug {
+		return
+	}
+	dump, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		Logf(LevelDebug, "failed to dump the request: %s", err)
+		return
+	}
+	Logf(LevelDebug, "> %s", Redact(string(dump)))
+}
+
+// Dump the raw response at the debug level, the body is kept readable for the caller
+func debugResponse(resp *http.Response) {
+	if Level < LevelDebug {
+		return
+	}
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		Logf(LevelDebug, "failed to dump the response: %s", err)
+		return
+	}
+	Logf(LevelDebug, "< %s", Redact(string(dump)))
+}
+
+// Transport dumping every request and response at the debug level
+type debugTransport struct {
+	base http.RoundTripper
+}
+
+func (t debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	debugRequest(req)
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		debugResponse(resp)
+	}
+	return resp, err
+}
+
+// Return a copy of the client dumping the HTTP traffic at the debug level, or the client itself at other levels
+func debugClient(c *http.Client) *http.Client {
+	if Level < LevelDebug {
+		return c
+	}
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	dc := *c
+	dc.Transport = debugTransport{base: base}
+	return &dc
+}
@@ -0,0 +1,77 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func captureLog(t *testing.T, level client.LogLevel) *bytes.Buffer {
+	var buf bytes.Buffer
+	log, prev := client.Log, client.Level
+	client.Log, client.Level = &buf, level
+	t.Cleanup(func() { client.Log, client.Level = log, prev })
+	return &buf
+}
+
+func TestLogf(t *testing.T) {
+	buf := captureLog(t, client.LevelNormal)
+	client.Logf(client.LevelNormal, "shown %d", 1)
+	client.Logf(client.LevelVerbose, "hidden")
+	assert.Equal(t, "shown 1\n", buf.String())
+
+	buf = captureLog(t, client.LevelQuiet)
+	client.Logf(client.LevelNormal, "hidden")
+	assert.Empty(t, buf.String())
+}
+
+func TestRedact(t *testing.T) {
+	dump := "GET /v1/measurements/abcd HTTP/1.1\r\nHost: api.globalping.io\r\nAuthorization: Bearer secret\r\nSet-Cookie: id=secret\r\n\r\n"
+	assert.Equal(t, "GET /v1/measurements/abcd HTTP/1.1\r\nHost: api.globalping.io\r\nAuthorization: [redacted]\r\nSet-Cookie: [redacted]\r\n\r\n", client.Redact(dump))
+}
+
+func TestVerboseLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	buf := captureLog(t, client.LevelVerbose)
+	res, err := client.PostAPI(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 99, res.RateLimit.Remaining)
+	assert.Contains(t, buf.String(), "POST "+server.URL+`/measurements {"limit":0`)
+	assert.Contains(t, buf.String(), "Rate limit: 99 of 100 remaining")
+	assert.NotContains(t, buf.String(), "HTTP/1.1")
+}
+
+func TestDebugLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"abcd","status":"finished","probesCount":1}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+	token := client.ApiToken
+	client.ApiToken = "secret"
+	t.Cleanup(func() { client.ApiToken = token })
+
+	buf := captureLog(t, client.LevelDebug)
+	res, err := client.GetAPI(context.Background(), "abcd")
+	assert.NoError(t, err)
+	// The body is still readable after the dump
+	assert.Equal(t, "finished", res.Status)
+	assert.Contains(t, buf.String(), "> GET /measurements/abcd HTTP/1.1")
+	assert.Contains(t, buf.String(), "Authorization: [redacted]")
+	assert.Contains(t, buf.String(), `< HTTP/1.1 200 OK`)
+	assert.NotContains(t, buf.String(), "secret")
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Escape the characters that would break a Markdown table cell
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// Format a value of the ping stats in milliseconds
+func markdownMs(v *float64) string {
+	if v != nil {
+		return fmt.Sprintf("%.2f ms", *v)
+	}
+	return "-"
+}
+
+// FormatMarkdown renders a GitHub-flavored Markdown table with one row per probe and the key metrics of the measurement type
+func FormatMarkdown(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("### globalping %s %s\n\n", cmd, markdownEscape(ctx.Target)))
+
+	switch cmd {
+	case "ping":
+		output.WriteString("| Location | Network | Min | Avg | Max | Loss |\n| --- | --- | ---: | ---: | ---: | ---: |\n")
+	case "dns":
+		output.WriteString("| Location | Network | Total | Status |\n| --- | --- | ---: | --- |\n")
+	case "http":
+		output.WriteString("| Location | Network | Total | Status code | Status |\n| --- | --- | ---: | ---: | --- |\n")
+	default:
+		output.WriteString("| Location | Network | Status |\n| --- | --- | --- |\n")
+	}
+
+	for _, result := range data.Results {
+		p := result.Probe
+		location := p.City + ", " + p.Country
+		if p.State != "" {
+			location = p.City + ", " + p.State + ", " + p.Country
+		}
+		cells := []string{location, fmt.Sprintf("%s (AS%d)", p.Network, p.ASN)}
+
+		switch cmd {
+		case "ping":
+			s := pingStats(result)
+			loss := "-"
+			if v, ok := pingLoss(result); ok {
+				loss = fmt.Sprintf("%v%%", v)
+			}
+			cells = append(cells, markdownMs(s.Min), markdownMs(s.Avg), markdownMs(s.Max), loss)
+		case "dns", "http":
+			total := "-"
+			if v, ok := KeyMetric(cmd, result); ok {
+				total = fmt.Sprintf("%.2f ms", v)
+			}
+			cells = append(cells, total)
+			if cmd == "http" {
+				code := "-"
+				if result.Result.StatusCode != 0 {
+					code = fmt.Sprint(result.Result.StatusCode)
+				}
+				cells = append(cells, code)
+			}
+			cells = append(cells, result.Result.Status)
+		default:
+			cells = append(cells, result.Result.Status)
+		}
+
+		for i := range cells {
+			cells[i] = markdownEscape(cells[i])
+		}
+		output.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	if data.ID != "" {
+		output.WriteString(fmt.Sprintf("\n[View the results on globalping.io](%s)\n", ShareUrl(data.ID)))
+	}
+
+	return strings.TrimSuffix(output.String(), "\n"), nil
+}
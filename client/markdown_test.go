@@ -0,0 +1,43 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMarkdownPing(t *testing.T) {
+	berlin := pingResult("Berlin", 20)
+	berlin.Probe.Network = "Hetzner | Online"
+	berlin.Result.Stats.Min = ms(19.5)
+	berlin.Result.Stats.Max = ms(21.0)
+	data := model.GetMeasurement{ID: "abcd", Type: "ping", Results: []model.MeasurementResponse{berlin}}
+
+	output, err := client.FormatMarkdown(data, model.Context{Target: "google.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, `### globalping ping google.com
+
+| Location | Network | Min | Avg | Max | Loss |
+| --- | --- | ---: | ---: | ---: | ---: |
+| Berlin, DE | Hetzner \| Online (AS1) | 19.50 ms | 20.00 ms | 21.00 ms | 0% |
+
+[View the results on globalping.io](https://globalping.io?measurement=abcd)`, output)
+}
+
+func TestFormatMarkdownHttp(t *testing.T) {
+	data := model.GetMeasurement{Type: "http", Results: []model.MeasurementResponse{{
+		Probe:  model.ProbeData{City: "Dallas", State: "TX", Country: "US", ASN: 2, Network: "Network"},
+		Result: model.ResultData{Status: "finished", StatusCode: 200, Timings: &model.Timings{Total: ms(42)}},
+	}}}
+
+	output, err := client.FormatMarkdown(data, model.Context{Target: "jsdelivr.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, `### globalping http jsdelivr.com
+
+| Location | Network | Total | Status code | Status |
+| --- | --- | ---: | ---: | --- |
+| Dallas, TX, US | Network (AS2) | 42.00 ms | 200 | finished |`, output)
+}
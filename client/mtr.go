@@ -0,0 +1,143 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Stats of an mtr hop, zero if the probe did not report them
+func hopStats(hop model.Hop) model.MtrStats {
+	if hop.Stats == nil {
+		return model.MtrStats{}
+	}
+	return *hop.Stats
+}
+
+// Host column of a hop, the hostname with the address like the native mtr
+func hopHost(hostname, address string) string {
+	switch {
+	case address == "":
+		return "???"
+	case hostname == "" || hostname == address:
+		return address
+	}
+	return fmt.Sprintf("%s (%s)", hostname, address)
+}
+
+// ASN column of a hop, several ASNs are joined
+func hopASN(asn []int) string {
+	if len(asn) == 0 {
+		return "-"
+	}
+	values := make([]string, len(asn))
+	for i, a := range asn {
+		values[i] = fmt.Sprintf("AS%d", a)
+	}
+	return strings.Join(values, ",")
+}
+
+// FormatMtrTable renders the hops of every probe of an mtr measurement as a table like the native mtr report
+func FormatMtrTable(data model.GetMeasurement, ctx model.Context) (string, error) {
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+
+		hops := result.Result.Hops
+		if len(hops) == 0 {
+			output.WriteString("No hops\n\n")
+			continue
+		}
+
+		w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Hop\tHost\tASN\tLoss%\tSnt\tAvg\tBest\tWrst\tStDev\tJitter")
+		for i, hop := range hops {
+			s := hopStats(hop)
+			fmt.Fprintf(w, "%d.\t%s\t%s\t%.1f%%\t%d\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\n",
+				i+1, hopHost(hop.ResolvedHostname, hop.ResolvedAddress), hopASN(hop.ASN), s.Loss, s.Total, s.Avg, s.Min, s.Max, s.StDev, s.JAvg)
+		}
+		w.Flush()
+		output.WriteString("\n")
+	}
+
+	return strings.TrimRight(output.String(), "\n"), nil
+}
+
+// LocalizeLoss returns the index of the hop where the packet loss reaching the destination begins, the first hop of
+// the last run of hops all losing packets. Loss at an intermediate hop that does not carry on to the following hops is
+// routers rate limiting their replies and is ignored. ok is false when the destination has no loss
+func LocalizeLoss(hops []model.Hop) (index int, ok bool) {
+	if len(hops) == 0 || hopStats(hops[len(hops)-1]).Loss == 0 {
+		return 0, false
+	}
+	index = len(hops) - 1
+	for index > 0 && hopStats(hops[index-1]).Loss > 0 {
+		index--
+	}
+	return index, true
+}
+
+// FormatLossAnalysis renders where the packet loss of every probe of an mtr measurement begins, followed by the
+// suspected hops grouped by the number of probes losing packets from them
+func FormatLossAnalysis(data model.GetMeasurement, ctx model.Context) string {
+	type suspect struct {
+		hop    string
+		asn    string
+		probes []string
+	}
+	var suspects []*suspect
+	byHop := map[string]*suspect{}
+
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+
+		hops := result.Result.Hops
+		if len(hops) == 0 {
+			output.WriteString(fmt.Sprintf("No hops, probe %s\n\n", result.Result.Status))
+			continue
+		}
+		i, ok := LocalizeLoss(hops)
+		if !ok {
+			output.WriteString("No loss at the destination\n\n")
+			continue
+		}
+
+		hop := hops[i]
+		host := hopHost(hop.ResolvedHostname, hop.ResolvedAddress)
+		asn := hopASN(hop.ASN)
+		output.WriteString(fmt.Sprintf("Loss begins at hop %d, %s %s, %.1f%% loss at the destination\n\n",
+			i+1, host, asn, hopStats(hops[len(hops)-1]).Loss))
+
+		// Unanswered hops are grouped by their position as they have no address
+		key := host
+		if hop.ResolvedAddress == "" {
+			key = fmt.Sprintf("%s (hop %d)", host, i+1)
+		}
+		s, found := byHop[key]
+		if !found {
+			s = &suspect{hop: key, asn: asn}
+			byHop[key] = s
+			suspects = append(suspects, s)
+		}
+		s.probes = append(s.probes, probeLabel(result.Probe))
+	}
+
+	if len(suspects) == 0 {
+		output.WriteString("No probe lost packets to the destination")
+		return output.String()
+	}
+
+	sort.SliceStable(suspects, func(i, j int) bool { return len(suspects[i].probes) > len(suspects[j].probes) })
+	output.WriteString("Suspected hops:\n")
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HOP\tASN\tPROBES")
+	for _, s := range suspects {
+		fmt.Fprintf(w, "%s\t%s\t%d of %d: %s\n", s.hop, s.asn, len(s.probes), len(data.Results), strings.Join(s.probes, "; "))
+	}
+	w.Flush()
+	return strings.TrimRight(output.String(), "\n")
+}
@@ -0,0 +1,83 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMtrTable(t *testing.T) {
+	data := model.GetMeasurement{Type: "mtr", Results: []model.MeasurementResponse{{
+		Probe: model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+		Result: model.ResultData{Status: "finished", Hops: hops(`[
+			{"resolvedAddress": "10.0.0.1", "resolvedHostname": "10.0.0.1", "asn": [], "stats": {"min": 0.4, "avg": 0.5, "max": 0.7, "stDev": 0.1, "jAvg": 0.2, "total": 3, "rcv": 3, "loss": 0}},
+			{"resolvedAddress": "", "asn": [], "stats": {"total": 3, "loss": 100}},
+			{"resolvedAddress": "142.250.185.78", "resolvedHostname": "fra16s52-in-f14.1e100.net", "asn": [15169], "stats": {"min": 10.1, "avg": 10.52, "max": 11, "stDev": 0.35, "jAvg": 0.4, "total": 3, "rcv": 3, "loss": 0}}
+		]`)},
+	}}}
+
+	output, err := client.FormatTable(data, model.Context{CI: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `> EU, DE, Berlin, ASN:1, Network
+Hop  Host                                        ASN      Loss%   Snt  Avg   Best  Wrst  StDev  Jitter
+1.   10.0.0.1                                    -        0.0%    3    0.5   0.4   0.7   0.1    0.2
+2.   ???                                         -        100.0%  3    0.0   0.0   0.0   0.0    0.0
+3.   fra16s52-in-f14.1e100.net (142.250.185.78)  AS15169  0.0%    3    10.5  10.1  11.0  0.3    0.4`, output)
+
+	_, err = client.FormatTable(model.GetMeasurement{Type: "ping"}, model.Context{})
+	assert.EqualError(t, err, "err: the table format is not supported for ping measurements")
+}
+
+func TestFormatLossAnalysis(t *testing.T) {
+	probe := func(city string, asn int) model.ProbeData {
+		return model.ProbeData{Continent: "EU", Country: "DE", City: city, ASN: asn, Network: "Network"}
+	}
+	data := model.GetMeasurement{Type: "mtr", Results: []model.MeasurementResponse{
+		{
+			// The loss of hop 2 stops at hop 3, it is rate limiting
+			Probe: probe("Berlin", 1),
+			Result: model.ResultData{Status: "finished", Hops: hops(`[
+				{"resolvedAddress": "10.0.0.1", "stats": {"loss": 0}},
+				{"resolvedAddress": "203.0.113.1", "asn": [3320], "stats": {"loss": 40}},
+				{"resolvedAddress": "198.51.100.1", "resolvedHostname": "be2.cogentco.com", "asn": [174], "stats": {"loss": 0}},
+				{"resolvedAddress": "198.51.100.9", "asn": [174], "stats": {"loss": 20}},
+				{"resolvedAddress": "1.1.1.1", "asn": [13335], "stats": {"loss": 30}}
+			]`)},
+		},
+		{
+			Probe: probe("Munich", 2),
+			Result: model.ResultData{Status: "finished", Hops: hops(`[
+				{"resolvedAddress": "10.0.0.1", "stats": {"loss": 0}},
+				{"resolvedAddress": "198.51.100.9", "asn": [174], "stats": {"loss": 10}},
+				{"resolvedAddress": "1.1.1.1", "asn": [13335], "stats": {"loss": 10}}
+			]`)},
+		},
+		{
+			Probe: probe("Hamburg", 3),
+			Result: model.ResultData{Status: "finished", Hops: hops(`[
+				{"resolvedAddress": "10.0.0.1", "stats": {"loss": 50}},
+				{"resolvedAddress": "1.1.1.1", "asn": [13335], "stats": {"loss": 0}}
+			]`)},
+		},
+		{Probe: probe("Cologne", 4), Result: model.ResultData{Status: "failed"}},
+	}}
+
+	assert.Equal(t, `> EU, DE, Berlin, ASN:1, Network
+Loss begins at hop 4, 198.51.100.9 AS174, 30.0% loss at the destination
+
+> EU, DE, Munich, ASN:2, Network
+Loss begins at hop 2, 198.51.100.9 AS174, 10.0% loss at the destination
+
+> EU, DE, Hamburg, ASN:3, Network
+No loss at the destination
+
+> EU, DE, Cologne, ASN:4, Network
+No hops, probe failed
+
+Suspected hops:
+HOP           ASN    PROBES
+198.51.100.9  AS174  2 of 4: Berlin, DE, ASN:1; Munich, DE, ASN:2`, client.FormatLossAnalysis(data, model.Context{CI: true}))
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// LogRecord is the normalized result of one probe appended to the NDJSON log
+type LogRecord struct {
+	Timestamp time.Time          `json:"timestamp"`
+	ID        string             `json:"id"`
+	Type      string             `json:"type"`
+	Target    string             `json:"target"`
+	Probe     model.ProbeData    `json:"probe"`
+	Status    string             `json:"status"`
+	Metrics   map[string]float64 `json:"metrics"`
+}
+
+// resultMetrics returns the numeric metrics of a probe result, the same keys across runs of a measurement type
+func resultMetrics(cmd string, result model.MeasurementResponse) map[string]float64 {
+	metrics := map[string]float64{}
+	switch cmd {
+	case "ping":
+		if s := result.Result.Stats; s != nil {
+			for key, v := range map[string]*float64{"min": s.Min, "avg": s.Avg, "max": s.Max, "loss": &s.Loss} {
+				if v != nil {
+					metrics[key] = *v
+				}
+			}
+		}
+	case "dns", "http":
+		if v, ok := KeyMetric(cmd, result); ok {
+			metrics["total"] = v
+		}
+		if result.Result.StatusCode != 0 {
+			metrics["statusCode"] = float64(result.Result.StatusCode)
+		}
+	case "traceroute":
+		if hops := result.Result.Hops; len(hops) > 0 {
+			metrics["hops"] = float64(len(hops))
+		}
+	case "mtr":
+		if hops := result.Result.Hops; len(hops) > 0 {
+			last := hopStats(hops[len(hops)-1])
+			metrics["hops"] = float64(len(hops))
+			metrics["loss"] = last.Loss
+			metrics["avg"] = last.Avg
+		}
+	}
+	return metrics
+}
+
+// LogRecords normalizes every probe result of a measurement
+func LogRecords(data model.GetMeasurement, ctx model.Context, now time.Time) []LogRecord {
+	cmd := measurementType(data, ctx)
+
+	records := make([]LogRecord, 0, len(data.Results))
+	for _, result := range data.Results {
+		records = append(records, LogRecord{
+			Timestamp: now.UTC(),
+			ID:        data.ID,
+			Type:      cmd,
+			Target:    ctx.Target,
+			Probe:     result.Probe,
+			Status:    result.Result.Status,
+			Metrics:   resultMetrics(cmd, result),
+		})
+	}
+	return records
+}
+
+// AppendNdjson appends one JSON line per probe result to the file at path, creating it if needed
+func AppendNdjson(path string, data model.GetMeasurement, ctx model.Context) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return errors.New("err: failed to create the log directory")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("err: failed to open %s", path)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, r := range LogRecords(data, ctx, time.Now()) {
+		err = enc.Encode(r)
+		if err != nil {
+			return fmt.Errorf("err: failed to write %s", path)
+		}
+	}
+	return nil
+}
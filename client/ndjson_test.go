@@ -0,0 +1,45 @@
+package client_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogRecords(t *testing.T) {
+	data := model.GetMeasurement{ID: "abcd", Type: "ping", Results: []model.MeasurementResponse{pingResult("Berlin", 10)}}
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	records := client.LogRecords(data, model.Context{Target: "google.com"}, now)
+	assert.Equal(t, []client.LogRecord{{
+		Timestamp: now,
+		ID:        "abcd",
+		Type:      "ping",
+		Target:    "google.com",
+		Probe:     model.ProbeData{City: "Berlin", Country: "DE", ASN: 1},
+		Status:    "finished",
+		Metrics:   map[string]float64{"avg": 10, "loss": 0},
+	}}, records)
+}
+
+func TestAppendNdjson(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "ping.ndjson")
+	data := model.GetMeasurement{ID: "abcd", Type: "ping", Results: []model.MeasurementResponse{pingResult("Berlin", 10), pingResult("Munich", 20)}}
+
+	assert.NoError(t, client.AppendNdjson(path, data, model.Context{Target: "google.com"}))
+	assert.NoError(t, client.AppendNdjson(path, data, model.Context{Target: "google.com"}))
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Len(t, lines, 4)
+	assert.Contains(t, lines[1], `"city":"Munich"`)
+	assert.Contains(t, lines[1], `"metrics":{"avg":20,"loss":0}`)
+}
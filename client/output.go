@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// TargetOutput returns the file written by --output for one of several targets, the target is added before the
+// extension, e.g. results-google.com.json
+func TargetOutput(path string, target string) string {
+	ext := filepath.Ext(path)
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(target, "_"), "_")
+	return strings.TrimSuffix(path, ext) + "-" + name + ext
+}
+
+// OutputFile writes a finished measurement to the file selected with --output and returns a short summary to print,
+// a .json file defaults to the JSON output, a .geojson file to the GeoJSON format and a .html file to the HTML report
+// when no other output is selected
+func OutputFile(c context.Context, id string, data model.GetMeasurement, ctx model.Context) (string, error) {
+	// Files never contain colors
+	ctx.CI = true
+	if ctx.Format == "" && !ctx.JsonOutput && !ctx.Latency && strings.EqualFold(filepath.Ext(ctx.Output), ".json") {
+		ctx.JsonOutput = true
+	}
+	if ctx.Format == "" && !ctx.JsonOutput && !ctx.Latency {
+		switch strings.ToLower(filepath.Ext(ctx.Output)) {
+		case ".geojson":
+			ctx.Format = "geojson"
+		case ".html":
+			ctx.Format = "html"
+		}
+	}
+
+	output, err := RenderFinished(c, id, data, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(filepath.Dir(ctx.Output), 0o755)
+	if err != nil {
+		return "", errors.New("err: failed to create the output directory")
+	}
+	err = os.WriteFile(ctx.Output, []byte(output+"\n"), 0o644)
+	if err != nil {
+		return "", fmt.Errorf("err: failed to write %s", ctx.Output)
+	}
+
+	summary := fmt.Sprintf("Results of %d probes saved to %s", len(data.Results), ctx.Output)
+	if !ctx.Summary {
+		summary += "\n" + AggregateSummary(measurementType(data, ctx), data)
+	}
+	return summary, nil
+}
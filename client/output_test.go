@@ -0,0 +1,35 @@
+package client_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports", "results.txt")
+	result := pingResult("Berlin", 10)
+	result.Probe.Continent = "EU"
+	result.Probe.Network = "Deutsche Telekom AG"
+	result.Result.RawOutput = "PING google.com (142.250.185.78) 56(84) bytes of data.\n"
+	data := model.GetMeasurement{Type: "ping", Results: []model.MeasurementResponse{result}}
+
+	summary, err := client.OutputFile(context.Background(), "abcd", data, model.Context{Cmd: "ping", Output: path})
+	assert.NoError(t, err)
+	assert.Equal(t, "Results of 1 probes saved to "+path+"\nSummary of 1 probes: min 10.00 ms, median 10.00 ms, p95 10.00 ms, max 10.00 ms", summary)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "> EU, DE, Berlin, ASN:1, Deutsche Telekom AG\nPING google.com (142.250.185.78) 56(84) bytes of data.\n", string(b))
+}
+
+func TestTargetOutput(t *testing.T) {
+	assert.Equal(t, "out/results-google.com.json", client.TargetOutput("out/results.json", "google.com"))
+	assert.Equal(t, "results-https_example.com_path", client.TargetOutput("results", "https://example.com/path"))
+}
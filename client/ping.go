@@ -0,0 +1,195 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// PingProbeStats holds the packet statistics of a single probe aggregated across several ping measurements
+type PingProbeStats struct {
+	Label string
+	Sent  int
+	Rcv   int
+	Min   float64
+	Max   float64
+	Sum   float64
+	// Recent round trip times drawn as a sparkline
+	Recent []float64
+}
+
+// Loss returns the packet loss percentage
+func (s *PingProbeStats) Loss() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Sent-s.Rcv) / float64(s.Sent) * 100
+}
+
+// Avg returns the average round trip time of all received packets
+func (s *PingProbeStats) Avg() float64 {
+	if s.Rcv == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Rcv)
+}
+
+// Value of an optional duration in milliseconds, false if unset
+func msValue(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+// Optional duration printed with %v, nil if unset
+func msOrNil(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// Stats of a ping result, empty if the probe did not report them
+func pingStats(result model.MeasurementResponse) *model.PingStats {
+	if result.Result.Stats == nil {
+		return &model.PingStats{}
+	}
+	return result.Result.Stats
+}
+
+// Packet loss of a ping result in percent, false for other results
+func pingLoss(result model.MeasurementResponse) (float64, bool) {
+	if result.Result.Stats == nil {
+		return 0, false
+	}
+	return result.Result.Stats.Loss, true
+}
+
+// RttStats are the round trip time percentiles and jitter of a ping result computed from its per-packet timings
+type RttStats struct {
+	Count  int
+	P50    float64
+	P90    float64
+	P99    float64
+	Jitter float64
+}
+
+// PingRttStats computes the percentiles and jitter (standard deviation) of the round trip times of a ping result,
+// returns false if no packet was received
+func PingRttStats(result model.MeasurementResponse) (RttStats, bool) {
+	rtts := result.Result.Timings.RTTs()
+	sum := 0.0
+	for _, rtt := range rtts {
+		sum += rtt
+	}
+	if len(rtts) == 0 {
+		return RttStats{}, false
+	}
+	sort.Float64s(rtts)
+
+	mean := sum / float64(len(rtts))
+	variance := 0.0
+	for _, rtt := range rtts {
+		variance += (rtt - mean) * (rtt - mean)
+	}
+	variance /= float64(len(rtts))
+
+	return RttStats{
+		Count:  len(rtts),
+		P50:    Percentile(rtts, 50),
+		P90:    Percentile(rtts, 90),
+		P99:    Percentile(rtts, 99),
+		Jitter: math.Sqrt(variance),
+	}, true
+}
+
+// PingAggregator collects per-packet results of repeated ping measurements, used by the infinite ping mode
+type PingAggregator struct {
+	mu     sync.Mutex
+	order  []string
+	probes map[string]*PingProbeStats
+}
+
+func NewPingAggregator() *PingAggregator {
+	return &PingAggregator{probes: map[string]*PingProbeStats{}}
+}
+
+// Short probe label used as the prefix of per-packet lines
+func probeLabel(probe model.ProbeData) string {
+	return fmt.Sprintf("%s, %s, ASN:%d", probe.City, probe.Country, probe.ASN)
+}
+
+// Add records the results of a finished ping measurement and returns the per-packet lines to print
+func (a *PingAggregator) Add(data model.GetMeasurement) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var output strings.Builder
+
+	for _, result := range data.Results {
+		label := probeLabel(result.Probe)
+		stats, ok := a.probes[label]
+		if !ok {
+			stats = &PingProbeStats{Label: label}
+			a.probes[label] = stats
+			a.order = append(a.order, label)
+		}
+
+		var packets []model.PacketTiming
+		if result.Result.Timings != nil {
+			packets = result.Result.Timings.Packets
+		}
+		for _, t := range packets {
+			rtt := t.RTT
+			stats.Sent++
+			stats.Rcv++
+			stats.Sum += rtt
+			stats.Recent = appendRecent(stats.Recent, rtt)
+			if stats.Rcv == 1 || rtt < stats.Min {
+				stats.Min = rtt
+			}
+			if rtt > stats.Max {
+				stats.Max = rtt
+			}
+			output.WriteString(fmt.Sprintf("%s: %s: icmp_seq=%d ttl=%v time=%v ms\n", label, result.Result.ResolvedAddress, stats.Sent, t.TTL, rtt))
+		}
+
+		// Packets without timings were lost
+		total := 0
+		if result.Result.Stats != nil {
+			total = result.Result.Stats.Total
+		}
+		for i := len(packets); i < total; i++ {
+			stats.Sent++
+			output.WriteString(fmt.Sprintf("%s: Request timeout for icmp_seq=%d\n", label, stats.Sent))
+		}
+	}
+
+	return strings.TrimSpace(output.String()), nil
+}
+
+// Summary returns the final statistics of every probe, similar to the native ping summary
+func (a *PingAggregator) Summary() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var output strings.Builder
+
+	for _, label := range a.order {
+		stats := a.probes[label]
+		output.WriteString(fmt.Sprintf("--- %s ping statistics ---\n", label))
+		output.WriteString(fmt.Sprintf("%d packets transmitted, %d received, %.2f%% packet loss\n", stats.Sent, stats.Rcv, stats.Loss()))
+		if stats.Rcv > 0 {
+			output.WriteString(fmt.Sprintf("rtt min/avg/max = %.3f/%.3f/%.3f ms\n", stats.Min, stats.Avg(), stats.Max))
+			output.WriteString(fmt.Sprintf("rtt trend %s\n", Sparkline(stats.Recent)))
+		}
+		output.WriteString("\n")
+	}
+
+	return strings.TrimSpace(output.String())
+}
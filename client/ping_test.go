@@ -0,0 +1,62 @@
+package client_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pingMeasurement(timings string, total int) model.GetMeasurement {
+	var t model.Timings
+	if err := json.Unmarshal([]byte(timings), &t); err != nil {
+		panic(err)
+	}
+	return model.GetMeasurement{
+		Results: []model.MeasurementResponse{{
+			Probe: model.ProbeData{City: "Berlin", Country: "DE", ASN: 3320},
+			Result: model.ResultData{
+				ResolvedAddress: "1.1.1.1",
+				Stats:           &model.PingStats{Total: total},
+				Timings:         &t,
+			},
+		}},
+	}
+}
+
+func TestPingAggregator(t *testing.T) {
+	agg := client.NewPingAggregator()
+
+	lines, err := agg.Add(pingMeasurement(`[{"ttl":55,"rtt":10},{"ttl":55,"rtt":20}]`, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, `Berlin, DE, ASN:3320: 1.1.1.1: icmp_seq=1 ttl=55 time=10 ms
+Berlin, DE, ASN:3320: 1.1.1.1: icmp_seq=2 ttl=55 time=20 ms
+Berlin, DE, ASN:3320: Request timeout for icmp_seq=3`, lines)
+
+	lines, err = agg.Add(pingMeasurement(`[{"ttl":55,"rtt":30}]`, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "Berlin, DE, ASN:3320: 1.1.1.1: icmp_seq=4 ttl=55 time=30 ms", lines)
+
+	assert.Equal(t, `--- Berlin, DE, ASN:3320 ping statistics ---
+4 packets transmitted, 3 received, 25.00% packet loss
+rtt min/avg/max = 10.000/20.000/30.000 ms
+rtt trend ▁▅█`, agg.Summary())
+}
+
+func TestPingRttStats(t *testing.T) {
+	data := pingMeasurement(`[{"ttl":60,"rtt":10},{"ttl":60,"rtt":20},{"ttl":60,"rtt":30},{"ttl":60,"rtt":40}]`, 4)
+
+	rtt, ok := client.PingRttStats(data.Results[0])
+	assert.True(t, ok)
+	assert.Equal(t, 4, rtt.Count)
+	assert.Equal(t, 25.0, rtt.P50)
+	assert.InDelta(t, 37.0, rtt.P90, 0.0001)
+	assert.InDelta(t, 39.7, rtt.P99, 0.0001)
+	assert.InDelta(t, 11.1803, rtt.Jitter, 0.0001)
+
+	_, ok = client.PingRttStats(pingMeasurement(`[]`, 3).Results[0])
+	assert.False(t, ok)
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/pkg/globalping"
+)
+
+// poller fetches an in-progress measurement repeatedly. It sends the ETag of the previous response so the API can
+// answer 304 Not Modified instead of the full measurement, and spaces the requests out as the measurement goes on.
+type poller struct {
+	id    string
+	etag  string
+	data  model.GetMeasurement
+	start time.Time
+	now   func() time.Time
+	// fetched is set once the API answered, stored results are only read before
+	fetched bool
+}
+
+// Wait is the maximum time a measurement is polled before its results are returned as they are, 0 waits until it
+// is finished
+var Wait time.Duration
+
+var (
+	pollStartsMu sync.Mutex
+	pollStarts   = map[string]time.Time{}
+)
+
+// Time the CLI started polling a measurement, shared by every poller of the measurement so the wait is not reset
+// when the display switches from waiting for the first result to streaming them
+func pollStart(id string, now time.Time) time.Time {
+	pollStartsMu.Lock()
+	defer pollStartsMu.Unlock()
+	if start, ok := pollStarts[id]; ok {
+		return start
+	}
+	pollStarts[id] = now
+	return now
+}
+
+func newPoller(id string) *poller {
+	return &poller{id: id, start: pollStart(id, time.Now()), now: time.Now}
+}
+
+// expired returns true once the measurement has been polled for longer than Wait
+func (p *poller) expired() bool {
+	return Wait > 0 && p.now().Sub(p.start) >= Wait
+}
+
+// Fetch the measurement, the previous state is returned unchanged if the API answers 304 Not Modified
+func (p *poller) get(c context.Context) (model.GetMeasurement, error) {
+	// Finished measurements never change, they are read from a file or the cache before the first request
+	if !p.fetched {
+		if raw, ok := storedJson(p.id); ok {
+			var data model.GetMeasurement
+			if json.Unmarshal(raw, &data) == nil {
+				p.data = data
+				return data, nil
+			}
+		}
+	}
+
+	raw, etag, err := api(ApiUrl, "measurements").GetMeasurementJSON(c, p.id, p.etag)
+	if err != nil {
+		return model.GetMeasurement{}, getError(err)
+	}
+	if raw == nil {
+		return p.data, nil
+	}
+
+	var data model.GetMeasurement
+	err = json.Unmarshal(raw, &data)
+	if err != nil {
+		return model.GetMeasurement{}, errors.New("invalid get measurement format returned")
+	}
+	p.fetched = true
+	cacheFinished(p.id, raw, data.Status)
+
+	Logf(LevelVerbose, "GET %s/%s: %s, %d of %d results", ApiUrl, p.id, data.Status, len(data.Results), data.ProbesCount)
+	p.etag = etag
+	p.data = data
+	return data, nil
+}
+
+// Sleep until the next poll
+func (p *poller) wait(c context.Context) error {
+	return sleep(c, globalping.PollInterval(p.data.ProbesCount, p.now().Sub(p.start)))
+}
+
+// IncompleteNote explains that a measurement returned after Wait is partial, ok is false if it is finished
+func IncompleteNote(data model.GetMeasurement) (note string, ok bool) {
+	if data.Status != "in-progress" {
+		return "", false
+	}
+	total := data.ProbesCount
+	if total < len(data.Results) {
+		total = len(data.Results)
+	}
+	finished := 0
+	for _, result := range data.Results {
+		if result.Result.Status != "in-progress" {
+			finished++
+		}
+	}
+	return fmt.Sprintf("Incomplete results: %d of %d probes finished within %s\n%s", finished, total, Wait, FetchLaterHint(data.ID)), true
+}
+
+// FetchLaterHint tells how to fetch the results of a measurement that was not waited for until the end
+func FetchLaterHint(id string) string {
+	return fmt.Sprintf("Measurement %s is still running, fetch its results later with: globalping get %s", id, id)
+}
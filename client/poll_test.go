@@ -0,0 +1,71 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForResultsNotModified(t *testing.T) {
+	requests := 0
+	var ifNoneMatch []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		switch requests {
+		case 1:
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"id":"abcd","status":"in-progress","probesCount":1}`))
+		case 2:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v2"`)
+			w.Write([]byte(`{"id":"abcd","status":"finished","probesCount":1}`))
+		}
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	res, err := client.WaitForResults(context.Background(), "abcd")
+	assert.NoError(t, err)
+	assert.Equal(t, "finished", res.Status)
+	assert.Equal(t, []string{"", `"v1"`, `"v1"`}, ifNoneMatch)
+}
+
+func TestWaitForResultsPartial(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"id":"wait1","status":"in-progress","probesCount":3,"results":[
+			{"result":{"status":"finished"}},
+			{"result":{"status":"in-progress"}}
+		]}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+	wait := client.Wait
+	t.Cleanup(func() { client.Wait = wait })
+	client.Wait = 300 * time.Millisecond
+
+	start := time.Now()
+	res, err := client.WaitForResults(context.Background(), "wait1")
+	assert.NoError(t, err)
+	assert.Equal(t, "in-progress", res.Status)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Greater(t, requests, 1)
+
+	note, ok := client.IncompleteNote(res)
+	assert.True(t, ok)
+	assert.Equal(t, `Incomplete results: 1 of 3 probes finished within 300ms
+Measurement wait1 is still running, fetch its results later with: globalping get wait1`, note)
+
+	_, ok = client.IncompleteNote(model.GetMeasurement{Status: "finished"})
+	assert.False(t, ok)
+}
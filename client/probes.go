@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/pkg/globalping"
+)
+
+var ProbesApiUrl = "https://api.globalping.io/v1/probes"
+
+// ProbeFilter narrows down the probes returned by FilterProbes, empty fields match every probe
+type ProbeFilter struct {
+	Continent string
+	Country   string
+	Network   string
+	ASN       int
+	Tag       string
+}
+
+// Get the list of online probes from Globalping API
+func GetProbes(c context.Context) ([]model.Probe, error) {
+	data, err := api(ProbesApiUrl, "probes").Probes(c)
+	if err != nil {
+		var apiErr *APIError
+		switch {
+		case errors.As(err, &apiErr):
+			return nil, errors.New("err: failed to fetch probes - please try again later")
+		case errors.Is(err, globalping.ErrInvalidResponse):
+			return nil, errors.New("invalid probes format returned")
+		}
+		return nil, requestError(err, "err: request failed")
+	}
+	return data, nil
+}
+
+// FilterProbes returns the probes matching every field of the filter
+func FilterProbes(probes []model.Probe, f ProbeFilter) []model.Probe {
+	var filtered []model.Probe
+	for _, p := range probes {
+		if f.Continent != "" && !strings.EqualFold(p.Location.Continent, f.Continent) {
+			continue
+		}
+		if f.Country != "" && !strings.EqualFold(p.Location.Country, f.Country) {
+			continue
+		}
+		if f.Network != "" && !strings.Contains(strings.ToLower(p.Location.Network), strings.ToLower(f.Network)) {
+			continue
+		}
+		if f.ASN != 0 && p.Location.ASN != f.ASN {
+			continue
+		}
+		if f.Tag != "" && !hasTag(p.Tags, f.Tag) {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+	return filtered
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
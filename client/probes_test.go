@@ -0,0 +1,69 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const probesJson = `[
+	{
+		"version": "0.14.0",
+		"location": {"continent": "EU", "region": "Western Europe", "country": "DE", "state": null, "city": "Frankfurt", "asn": 16509, "latitude": 50.11, "longitude": 8.68, "network": "Amazon.com, Inc."},
+		"tags": ["aws-eu-central-1"],
+		"resolvers": ["private"]
+	},
+	{
+		"version": "0.14.0",
+		"location": {"continent": "NA", "region": "Northern America", "country": "US", "state": "NY", "city": "New York", "asn": 7922, "latitude": 40.71, "longitude": -74.01, "network": "Comcast Cable Communications, LLC"},
+		"tags": [],
+		"resolvers": ["8.8.8.8"]
+	}
+]`
+
+func TestGetProbes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(probesJson))
+	}))
+	defer server.Close()
+	client.ProbesApiUrl = server.URL
+
+	probes, err := client.GetProbes(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, probes, 2)
+	assert.Equal(t, "Frankfurt", probes[0].Location.City)
+	assert.Equal(t, 16509, probes[0].Location.ASN)
+	assert.Equal(t, 50.11, probes[0].Location.Latitude)
+	assert.Equal(t, []string{"aws-eu-central-1"}, probes[0].Tags)
+	assert.Equal(t, "NY", probes[1].Location.State)
+}
+
+func TestGetProbesError(t *testing.T) {
+	server := generateServerError(`{}`, 500)
+	defer server.Close()
+	client.ProbesApiUrl = server.URL
+
+	_, err := client.GetProbes(context.Background())
+	assert.EqualError(t, err, "err: failed to fetch probes - please try again later")
+}
+
+func TestFilterProbes(t *testing.T) {
+	probes := []model.Probe{
+		{Location: model.ProbeLocation{Continent: "EU", Country: "DE", ASN: 16509, Network: "Amazon.com, Inc."}, Tags: []string{"aws-eu-central-1"}},
+		{Location: model.ProbeLocation{Continent: "NA", Country: "US", ASN: 7922, Network: "Comcast Cable"}},
+	}
+
+	assert.Len(t, client.FilterProbes(probes, client.ProbeFilter{}), 2)
+	assert.Len(t, client.FilterProbes(probes, client.ProbeFilter{Continent: "eu"}), 1)
+	assert.Len(t, client.FilterProbes(probes, client.ProbeFilter{Country: "US"}), 1)
+	assert.Len(t, client.FilterProbes(probes, client.ProbeFilter{Network: "comcast"}), 1)
+	assert.Len(t, client.FilterProbes(probes, client.ProbeFilter{ASN: 16509}), 1)
+	assert.Len(t, client.FilterProbes(probes, client.ProbeFilter{Tag: "AWS-EU-CENTRAL-1"}), 1)
+	assert.Len(t, client.FilterProbes(probes, client.ProbeFilter{Country: "DE", ASN: 7922}), 0)
+}
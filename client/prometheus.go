@@ -0,0 +1,101 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// A single metric family of the OpenMetrics output
+type promMetric struct {
+	name    string
+	help    string
+	samples []string
+}
+
+// Escape a label value as required by the exposition format
+func promEscape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// Labels describing the probe of a result
+func promLabels(target string, probe model.ProbeData, extra ...string) string {
+	labels := []string{
+		"target", target,
+		"continent", probe.Continent,
+		"country", probe.Country,
+		"city", probe.City,
+		"asn", strconv.Itoa(probe.ASN),
+		"network", probe.Network,
+	}
+	labels = append(labels, extra...)
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], promEscape(labels[i+1])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (m *promMetric) add(labels string, value float64) {
+	m.samples = append(m.samples, m.name+labels+" "+strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// FormatPrometheus converts ping and http results into OpenMetrics text, other types only report probe success
+func FormatPrometheus(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+
+	success := &promMetric{name: "globalping_probe_success", help: "Whether the probe finished the measurement successfully"}
+	rttMin := &promMetric{name: "globalping_ping_rtt_min_seconds", help: "Minimum round trip time"}
+	rttAvg := &promMetric{name: "globalping_ping_rtt_avg_seconds", help: "Average round trip time"}
+	rttMax := &promMetric{name: "globalping_ping_rtt_max_seconds", help: "Maximum round trip time"}
+	loss := &promMetric{name: "globalping_ping_packet_loss_ratio", help: "Ratio of lost packets"}
+	httpDuration := &promMetric{name: "globalping_http_duration_seconds", help: "Duration of the HTTP request phases"}
+	httpStatus := &promMetric{name: "globalping_http_status_code", help: "HTTP response status code"}
+
+	for _, result := range data.Results {
+		labels := promLabels(ctx.Target, result.Probe)
+
+		ok := 0.0
+		if result.Result.Status == "finished" {
+			ok = 1
+		}
+		success.add(labels, ok)
+
+		switch cmd {
+		case "ping":
+			if s := result.Result.Stats; s != nil {
+				for m, v := range map[*promMetric]*float64{rttMin: s.Min, rttAvg: s.Avg, rttMax: s.Max} {
+					if v != nil {
+						m.add(labels, *v/1000)
+					}
+				}
+				loss.add(labels, s.Loss/100)
+			}
+		case "http":
+			phases := result.Result.Timings.Phases()
+			for _, phase := range []string{"total", "dns", "tcp", "tls", "firstByte", "download"} {
+				if v := phases[phase]; v != nil {
+					httpDuration.add(promLabels(ctx.Target, result.Probe, "phase", phase), *v/1000)
+				}
+			}
+			if result.Result.StatusCode != 0 {
+				httpStatus.add(labels, float64(result.Result.StatusCode))
+			}
+		}
+	}
+
+	var output strings.Builder
+	for _, m := range []*promMetric{success, rttMin, rttAvg, rttMax, loss, httpDuration, httpStatus} {
+		if len(m.samples) == 0 {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name))
+		output.WriteString(strings.Join(m.samples, "\n") + "\n")
+	}
+	output.WriteString("# EOF")
+
+	return output.String(), nil
+}
@@ -0,0 +1,74 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var promProbe = model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 3320, Network: `Deutsche "Telekom"`}
+
+func TestFormatPrometheusPing(t *testing.T) {
+	data := model.GetMeasurement{
+		Type: "ping",
+		Results: []model.MeasurementResponse{{
+			Probe: promProbe,
+			Result: model.ResultData{
+				Status: "finished",
+				Stats:  &model.PingStats{Min: ms(10.5), Avg: ms(12), Max: ms(15), Loss: 25},
+			},
+		}},
+	}
+
+	output, err := client.FormatPrometheus(data, model.Context{Target: "google.com"})
+	assert.NoError(t, err)
+
+	labels := `{target="google.com",continent="EU",country="DE",city="Berlin",asn="3320",network="Deutsche \"Telekom\""}`
+	assert.Equal(t, `# HELP globalping_probe_success Whether the probe finished the measurement successfully
+# TYPE globalping_probe_success gauge
+globalping_probe_success`+labels+` 1
+# HELP globalping_ping_rtt_min_seconds Minimum round trip time
+# TYPE globalping_ping_rtt_min_seconds gauge
+globalping_ping_rtt_min_seconds`+labels+` 0.0105
+# HELP globalping_ping_rtt_avg_seconds Average round trip time
+# TYPE globalping_ping_rtt_avg_seconds gauge
+globalping_ping_rtt_avg_seconds`+labels+` 0.012
+# HELP globalping_ping_rtt_max_seconds Maximum round trip time
+# TYPE globalping_ping_rtt_max_seconds gauge
+globalping_ping_rtt_max_seconds`+labels+` 0.015
+# HELP globalping_ping_packet_loss_ratio Ratio of lost packets
+# TYPE globalping_ping_packet_loss_ratio gauge
+globalping_ping_packet_loss_ratio`+labels+` 0.25
+# EOF`, output)
+}
+
+func TestFormatPrometheusHttp(t *testing.T) {
+	data := model.GetMeasurement{
+		Type: "http",
+		Results: []model.MeasurementResponse{{
+			Probe: model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 3320},
+			Result: model.ResultData{
+				Status:     "failed",
+				StatusCode: 503,
+				Timings:    &model.Timings{Total: ms(250), DNS: ms(20)},
+			},
+		}},
+	}
+
+	output, err := client.FormatPrometheus(data, model.Context{Target: "jsdelivr.com"})
+	assert.NoError(t, err)
+	assert.Contains(t, output, `globalping_probe_success{target="jsdelivr.com",continent="EU",country="DE",city="Berlin",asn="3320",network=""} 0`)
+	assert.Contains(t, output, `globalping_http_duration_seconds{target="jsdelivr.com",continent="EU",country="DE",city="Berlin",asn="3320",network="",phase="total"} 0.25`)
+	assert.Contains(t, output, `globalping_http_duration_seconds{target="jsdelivr.com",continent="EU",country="DE",city="Berlin",asn="3320",network="",phase="dns"} 0.02`)
+	assert.NotContains(t, output, `phase="tls"`)
+	assert.Contains(t, output, `globalping_http_status_code{target="jsdelivr.com",continent="EU",country="DE",city="Berlin",asn="3320",network=""} 503`)
+}
+
+func TestValidFormat(t *testing.T) {
+	assert.True(t, client.ValidFormat(""))
+	assert.True(t, client.ValidFormat("prometheus"))
+	assert.False(t, client.ValidFormat("xml"))
+}
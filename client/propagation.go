@@ -0,0 +1,93 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// PropagationContinents are queried by the dns propagation mode when no location is given
+var PropagationContinents = []string{"AF", "AS", "EU", "NA", "OC", "SA"}
+
+// Normalize a record value for comparison, e.g. "Example.com." and "example.com" are equal
+func normalizeRecord(v string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(v)), ".")
+}
+
+// Propagated checks that the answers of a result contain every expected value
+func Propagated(result model.MeasurementResponse, expected []string) bool {
+	if result.Result.Status != "" && result.Result.Status != "finished" {
+		return false
+	}
+
+	values := map[string]bool{}
+	for _, a := range result.Result.Answers {
+		values[normalizeRecord(a.Value)] = true
+	}
+	for _, e := range expected {
+		if !values[normalizeRecord(e)] {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatPropagation renders the answers of every probe, the propagation status per region and returns the percentage
+// of probes that see the expected values
+func FormatPropagation(data model.GetMeasurement, expected []string) (string, float64) {
+	var output strings.Builder
+	w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROBE\tANSWER\tPROPAGATED")
+
+	var regions []string
+	total := map[string]int{}
+	ok := map[string]int{}
+	propagated := 0
+
+	for _, result := range data.Results {
+		region := result.Probe.Region
+		if region == "" {
+			region = result.Probe.Continent
+		}
+		if _, seen := total[region]; !seen {
+			regions = append(regions, region)
+		}
+		total[region]++
+
+		values := make([]string, len(result.Result.Answers))
+		for i, a := range result.Result.Answers {
+			values[i] = a.Value
+		}
+		answer := strings.Join(values, ", ")
+		if answer == "" {
+			answer = "-"
+		}
+
+		status := "no"
+		if Propagated(result, expected) {
+			status = "yes"
+			ok[region]++
+			propagated++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", probeLabel(result.Probe), answer, status)
+	}
+	w.Flush()
+
+	output.WriteString("\n")
+	w = tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REGION\tPROPAGATED")
+	for _, region := range regions {
+		fmt.Fprintf(w, "%s\t%d/%d\n", region, ok[region], total[region])
+	}
+	w.Flush()
+
+	percent := 0.0
+	if len(data.Results) > 0 {
+		percent = float64(propagated) / float64(len(data.Results)) * 100
+	}
+	output.WriteString(fmt.Sprintf("\nPropagation: %.0f%% (%d/%d probes see %s)", percent, propagated, len(data.Results), strings.Join(expected, ", ")))
+
+	return output.String(), percent
+}
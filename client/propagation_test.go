@@ -0,0 +1,50 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dnsResult(city, region string, values ...string) model.MeasurementResponse {
+	answers := make([]model.DnsAnswer, len(values))
+	for i, v := range values {
+		answers[i] = model.DnsAnswer{Name: "example.com.", Type: "A", Value: v}
+	}
+	return model.MeasurementResponse{
+		Probe:  model.ProbeData{City: city, Country: "XX", ASN: 1, Region: region},
+		Result: model.ResultData{Status: "finished", Answers: answers},
+	}
+}
+
+func TestFormatPropagation(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		dnsResult("Berlin", "Western Europe", "1.2.3.4"),
+		dnsResult("Paris", "Western Europe", "5.6.7.8"),
+		dnsResult("Tokyo", "Eastern Asia", "1.2.3.4", "1.2.3.5"),
+		dnsResult("Lima", "South America"),
+	}}
+
+	output, percent := client.FormatPropagation(data, []string{"1.2.3.4"})
+	assert.Equal(t, 50.0, percent)
+	assert.Equal(t, `PROBE              ANSWER            PROPAGATED
+Berlin, XX, ASN:1  1.2.3.4           yes
+Paris, XX, ASN:1   5.6.7.8           no
+Tokyo, XX, ASN:1   1.2.3.4, 1.2.3.5  yes
+Lima, XX, ASN:1    -                 no
+
+REGION          PROPAGATED
+Western Europe  1/2
+Eastern Asia    1/1
+South America   0/1
+
+Propagation: 50% (2/4 probes see 1.2.3.4)`, output)
+}
+
+func TestPropagated(t *testing.T) {
+	assert.True(t, client.Propagated(dnsResult("Berlin", "", "Target.Example.com."), []string{"target.example.com"}))
+	assert.False(t, client.Propagated(dnsResult("Berlin", "", "1.2.3.4"), []string{"1.2.3.4", "1.2.3.5"}))
+}
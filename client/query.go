@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// ParseQuery compiles a jq query, e.g. .results[].result.stats.avg
+func ParseQuery(query string) (*gojq.Code, error) {
+	q, err := gojq.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("err: invalid query %q: %s", query, err)
+	}
+	code, err := gojq.Compile(q)
+	if err != nil {
+		return nil, fmt.Errorf("err: invalid query %q: %s", query, err)
+	}
+	return code, nil
+}
+
+// QueryResults runs a jq query on a measurement in the normalized result model, the same for every measurement type,
+// and returns one line per value. Strings are printed without quotes like jq -r, other values as compact JSON
+func QueryResults(c context.Context, data model.GetMeasurement, query string) (string, error) {
+	code, err := ParseQuery(query)
+	if err != nil {
+		return "", err
+	}
+
+	// The query runs on plain JSON values
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("err: failed to encode the measurement: %s", err)
+	}
+	var input interface{}
+	if err := json.Unmarshal(raw, &input); err != nil {
+		return "", fmt.Errorf("err: failed to encode the measurement: %s", err)
+	}
+
+	var lines []string
+	iter := code.RunWithContext(c, input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return "", fmt.Errorf("err: query failed: %s", err)
+		}
+		if s, ok := v.(string); ok {
+			lines = append(lines, s)
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("err: query failed: %s", err)
+		}
+		lines = append(lines, string(b))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,33 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryResults(t *testing.T) {
+	data := model.GetMeasurement{ID: "abcd", Type: "ping", Results: []model.MeasurementResponse{pingResult("Berlin", 10.5), pingResult("Munich", 20)}}
+
+	output, err := client.QueryResults(context.Background(), data, ".results[].result.stats.avg")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.5\n20", output)
+
+	output, err = client.QueryResults(context.Background(), data, `.results[] | {city: .probe.city, avg: .result.stats.avg}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"avg":10.5,"city":"Berlin"}
+{"avg":20,"city":"Munich"}`, output)
+
+	output, err = client.QueryResults(context.Background(), data, ".id, (.results | length)")
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd\n2", output)
+
+	_, err = client.QueryResults(context.Background(), data, ".results[")
+	assert.Error(t, err)
+	_, err = client.QueryResults(context.Background(), data, ".id | keys")
+	assert.EqualError(t, err, "err: query failed: keys cannot be applied to: string (\"abcd\")")
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// RedirectLocation returns the Location header of a result with a 3xx status code
+func RedirectLocation(result model.MeasurementResponse) (string, bool) {
+	if result.Result.StatusCode < 300 || result.Result.StatusCode >= 400 {
+		return "", false
+	}
+	return headerValue(result.Result.Headers, "Location")
+}
+
+// NextRedirect returns the absolute URL the probes were redirected to, resolved against the requested URL. Failed
+// probes are ignored, an error is returned if the other probes were not all sent to the same URL, e.g. by a geo
+// redirect, since the next hop would measure some of them against a URL they were never sent to
+func NextRedirect(target string, data model.GetMeasurement) (string, bool, error) {
+	base, err := url.Parse(target)
+	if err != nil {
+		return "", false, nil
+	}
+
+	var hops []string
+	probes := map[string][]string{}
+	for _, result := range data.Results {
+		if ProbeFailed(result) {
+			continue
+		}
+		hop := ""
+		if location, ok := RedirectLocation(result); ok {
+			next, err := base.Parse(location)
+			if err != nil {
+				return "", false, fmt.Errorf("err: invalid redirect location %q", location)
+			}
+			hop = next.String()
+		}
+		if _, ok := probes[hop]; !ok {
+			hops = append(hops, hop)
+		}
+		probes[hop] = append(probes[hop], probeLabel(result.Probe))
+	}
+
+	switch {
+	case len(hops) == 0:
+		return "", false, nil
+	case len(hops) == 1:
+		return hops[0], hops[0] != "", nil
+	}
+
+	lines := make([]string, len(hops))
+	for i, hop := range hops {
+		if hop == "" {
+			hop = "not redirected"
+		}
+		lines[i] = fmt.Sprintf("  %s: %s", hop, strings.Join(probes[hops[i]], "; "))
+	}
+	return "", false, errors.New("err: the probes were redirected to different URLs, stopping the chain\n" + strings.Join(lines, "\n"))
+}
+
+// FormatRedirectHop renders one hop of a redirect chain with the status code, location and total time seen by every probe
+func FormatRedirectHop(hop int, target string, data model.GetMeasurement) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Hop %d: %s\n", hop, target))
+
+	for _, result := range data.Results {
+		line := fmt.Sprintf("  %s: ", probeLabel(result.Probe))
+		if result.Result.StatusCode == 0 {
+			line += result.Result.Status
+		} else {
+			line += fmt.Sprint(result.Result.StatusCode)
+		}
+		if location, ok := RedirectLocation(result); ok {
+			line += " -> " + location
+		}
+		if v, ok := KeyMetric("http", result); ok {
+			line += fmt.Sprintf(", %.2f ms", v)
+		}
+		output.WriteString(line + "\n")
+	}
+
+	return strings.TrimSuffix(output.String(), "\n")
+}
@@ -0,0 +1,48 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectChain(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		{
+			Probe:  model.ProbeData{City: "Berlin", Country: "DE", ASN: 1},
+			Result: model.ResultData{Status: "finished", StatusCode: 200, Timings: &model.Timings{Total: ms(12)}},
+		},
+		{
+			Probe:  model.ProbeData{City: "Paris", Country: "FR", ASN: 2},
+			Result: model.ResultData{Status: "finished", StatusCode: 301, Headers: map[string]interface{}{"location": "/fr/"}, Timings: &model.Timings{Total: ms(20.5)}},
+		},
+		{
+			Probe:  model.ProbeData{City: "Rome", Country: "IT", ASN: 3},
+			Result: model.ResultData{Status: "failed"},
+		},
+	}}
+
+	// Berlin was not redirected, it cannot be measured against the next URL
+	_, ok, err := client.NextRedirect("http://example.com/path?a=1", data)
+	assert.False(t, ok)
+	assert.EqualError(t, err, `err: the probes were redirected to different URLs, stopping the chain
+  not redirected: Berlin, DE, ASN:1
+  http://example.com/fr/: Paris, FR, ASN:2`)
+
+	next, ok, err := client.NextRedirect("http://example.com/path?a=1", model.GetMeasurement{Results: data.Results[1:]})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "http://example.com/fr/", next)
+
+	assert.Equal(t, `Hop 1: http://example.com/path?a=1
+  Berlin, DE, ASN:1: 200, 12.00 ms
+  Paris, FR, ASN:2: 301 -> /fr/, 20.50 ms
+  Rome, IT, ASN:3: failed`, client.FormatRedirectHop(1, "http://example.com/path?a=1", data))
+
+	_, ok, err = client.NextRedirect("http://example.com", model.GetMeasurement{Results: data.Results[:1]})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Probe of the HTML report, also passed to the scripts drawing the map and the chart
+type reportProbe struct {
+	Location string   `json:"location"`
+	Network  string   `json:"network"`
+	Status   string   `json:"status"`
+	Latency  *float64 `json:"latency"`
+	Loss     *float64 `json:"loss"`
+	Lat      float64  `json:"lat"`
+	Lon      float64  `json:"lon"`
+	Located  bool     `json:"located"`
+	Output   string   `json:"-"`
+}
+
+type reportData struct {
+	Title     string
+	ID        string
+	Type      string
+	Target    string
+	Status    string
+	CreatedAt string
+	ShareUrl  string
+	Summary   string
+	Probes    []reportProbe
+	Good      float64
+	Warn      float64
+}
+
+// FormatHTML renders a standalone HTML page with the results of every probe in a table, a map of the probes and
+// a latency chart, the styles and scripts are inlined so the page can be attached to a ticket as is
+func FormatHTML(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+	target := data.Target
+	if target == "" {
+		target = ctx.Target
+	}
+
+	report := reportData{
+		Title:     strings.TrimSpace(fmt.Sprintf("globalping %s %s", cmd, target)),
+		ID:        data.ID,
+		Type:      cmd,
+		Target:    target,
+		Status:    data.Status,
+		CreatedAt: data.CreatedAt,
+		Summary:   AggregateSummary(cmd, data),
+		Probes:    []reportProbe{},
+		Good:      LatencyGood,
+		Warn:      LatencyWarn,
+	}
+	if data.ID != "" {
+		report.ShareUrl = ShareUrl(data.ID)
+	}
+
+	for _, result := range data.Results {
+		p := result.Probe
+		location := p.City + ", " + p.Country
+		if p.State != "" {
+			location = p.City + ", " + p.State + ", " + p.Country
+		}
+		probe := reportProbe{
+			Location: location,
+			Network:  fmt.Sprintf("%s (AS%d)", p.Network, p.ASN),
+			Status:   result.Result.Status,
+			Lat:      p.Latitude,
+			Lon:      p.Longitude,
+			Located:  p.Latitude != 0 || p.Longitude != 0,
+			Output:   strings.TrimSpace(result.Result.RawOutput),
+		}
+		if v, ok := KeyMetric(cmd, result); ok {
+			probe.Latency = &v
+		}
+		if v, ok := pingLoss(result); ok {
+			probe.Loss = &v
+		}
+		report.Probes = append(report.Probes, probe)
+	}
+
+	var output strings.Builder
+	err := reportTemplate.Execute(&output, report)
+	if err != nil {
+		return "", errors.New("err: failed to render the HTML report")
+	}
+	return output.String(), nil
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ms": func(v *float64) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.2f ms", *v)
+	},
+	"percent": func(v *float64) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%v%%", *v)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 2rem auto; max-width: 1100px; padding: 0 1rem; color: #1f2328; }
+h1 { font-size: 1.5rem; }
+h2 { font-size: 1.15rem; margin-top: 2rem; }
+dl { display: grid; grid-template-columns: max-content auto; gap: .25rem 1rem; }
+dt { color: #656d76; }
+dd { margin: 0; }
+table { border-collapse: collapse; width: 100%; font-size: .9rem; }
+th, td { border-bottom: 1px solid #d0d7de; padding: .4rem .5rem; text-align: left; vertical-align: top; }
+td.num { text-align: right; white-space: nowrap; }
+pre { background: #f6f8fa; padding: .5rem; overflow-x: auto; font-size: .8rem; }
+svg { width: 100%; height: auto; display: block; }
+.good { color: #1a7f37; } .warn { color: #9a6700; } .bad, .failed { color: #cf222e; }
+.legend span { margin-right: 1rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<dl>
+{{- if .ID}}<dt>Measurement</dt><dd>{{if .ShareUrl}}<a href="{{.ShareUrl}}">{{.ID}}</a>{{else}}{{.ID}}{{end}}</dd>{{end}}
+{{- if .Status}}<dt>Status</dt><dd>{{.Status}}</dd>{{end}}
+{{- if .CreatedAt}}<dt>Created</dt><dd>{{.CreatedAt}}</dd>{{end}}
+<dt>Summary</dt><dd>{{.Summary}}</dd>
+</dl>
+
+<h2>Map</h2>
+<svg id="map" viewBox="0 0 360 150" role="img" aria-label="Map of the probes"></svg>
+<p class="legend"><span class="good">&#9679; &lt; {{.Good}} ms</span><span class="warn">&#9679; &lt; {{.Warn}} ms</span><span class="bad">&#9679; &ge; {{.Warn}} ms or failed</span></p>
+
+<h2>Latency</h2>
+<svg id="chart" role="img" aria-label="Latency of every probe"></svg>
+
+<h2>Probes</h2>
+<table>
+<thead><tr><th>Location</th><th>Network</th><th>Status</th><th>Latency</th><th>Loss</th></tr></thead>
+<tbody>
+{{- range .Probes}}
+<tr><td>{{.Location}}</td><td>{{.Network}}</td><td{{if ne .Status "finished"}} class="failed"{{end}}>{{.Status}}</td><td class="num">{{ms .Latency}}</td><td class="num">{{percent .Loss}}</td></tr>
+{{- if .Output}}
+<tr><td colspan="5"><details><summary>Output</summary><pre>{{.Output}}</pre></details></td></tr>
+{{- end}}
+{{- end}}
+</tbody>
+</table>
+
+<script>
+(function () {
+  var probes = {{.Probes}};
+  var good = {{.Good}}, warn = {{.Warn}};
+  var ns = "http://www.w3.org/2000/svg";
+
+  function color(p) {
+    if (p.status !== "finished") return "#cf222e";
+    if (p.latency === null) return "#656d76";
+    if (p.latency < good) return "#1a7f37";
+    if (p.latency < warn) return "#bf8700";
+    return "#cf222e";
+  }
+
+  function el(name, attrs, parent) {
+    var e = document.createElementNS(ns, name);
+    for (var k in attrs) e.setAttribute(k, attrs[k]);
+    parent.appendChild(e);
+    return e;
+  }
+
+  function label(p) {
+    return p.location + " - " + p.network + " - " + (p.latency === null ? p.status : p.latency.toFixed(2) + " ms");
+  }
+
+  // Coarse outlines of the land masses as longitude, latitude pairs
+  var land = [
+    [[-168,66],[-162,70],[-140,70],[-125,72],[-95,74],[-80,73],[-62,60],[-56,52],[-66,44],[-75,35],[-81,25],[-82,30],[-90,29],[-97,26],[-97,20],[-87,21],[-83,10],[-78,8],[-92,15],[-105,20],[-112,30],[-117,33],[-124,40],[-124,48],[-135,58],[-152,60],[-165,55]],
+    [[-55,60],[-43,60],[-20,70],[-20,82],[-60,82],[-72,78]],
+    [[-78,8],[-60,10],[-50,0],[-35,-6],[-40,-22],[-48,-28],[-58,-38],[-65,-42],[-68,-55],[-75,-50],[-72,-30],[-70,-18],[-81,-5]],
+    [[-10,36],[-9,43],[-2,44],[-5,48],[2,51],[8,54],[10,58],[5,62],[15,69],[28,71],[40,68],[60,68],[60,45],[48,42],[40,41],[28,41],[26,38],[22,36],[15,40],[12,44],[8,44],[3,43],[-5,36]],
+    [[-6,50],[2,51],[0,53],[-3,59],[-6,58],[-5,54]],
+    [[-17,21],[-10,30],[-6,36],[10,37],[20,32],[32,31],[35,28],[43,12],[51,12],[40,-2],[40,-15],[35,-25],[20,-35],[17,-30],[12,-18],[9,-2],[9,4],[-8,4],[-17,14]],
+    [[40,41],[48,42],[60,45],[60,68],[80,73],[105,78],[140,72],[180,68],[180,65],[160,60],[155,50],[140,45],[130,35],[122,30],[120,22],[108,18],[105,10],[103,1],[98,8],[98,16],[92,22],[80,15],[77,8],[72,20],[66,25],[57,25],[59,22],[52,16],[43,13],[38,22],[35,28],[34,32],[36,36],[28,37],[26,40]],
+    [[130,31],[141,36],[142,45],[140,41],[135,34]],
+    [[95,5],[106,-6],[115,-8],[125,-8],[140,-8],[150,-10],[141,-3],[131,-1],[119,5],[109,2],[100,-1]],
+    [[114,-22],[122,-18],[130,-12],[137,-12],[142,-11],[146,-19],[153,-25],[150,-37],[141,-38],[135,-35],[129,-32],[115,-34]],
+    [[172,-34],[178,-38],[174,-41],[167,-46],[172,-41]]
+  ];
+
+  var map = document.getElementById("map");
+  el("rect", {x: 0, y: 0, width: 360, height: 150, fill: "#eaf2fb"}, map);
+  land.forEach(function (shape) {
+    var points = shape.map(function (c) { return (c[0] + 180) + "," + (90 - c[1]); }).join(" ");
+    el("polygon", {points: points, fill: "#d0d7de"}, map);
+  });
+  probes.forEach(function (p) {
+    if (!p.located) return;
+    var dot = el("circle", {cx: p.lon + 180, cy: 90 - p.lat, r: 2, fill: color(p), stroke: "#fff", "stroke-width": 0.4}, map);
+    el("title", {}, dot).textContent = label(p);
+  });
+
+  var measured = probes.filter(function (p) { return p.latency !== null; });
+  var chart = document.getElementById("chart");
+  if (measured.length === 0) {
+    chart.style.display = "none";
+    return;
+  }
+  measured.sort(function (a, b) { return a.latency - b.latency; });
+  var max = measured[measured.length - 1].latency || 1;
+  var row = 18, labelWidth = 260, width = 1000;
+  chart.setAttribute("viewBox", "0 0 " + width + " " + (measured.length * row));
+  measured.forEach(function (p, i) {
+    var y = i * row;
+    var text = el("text", {x: labelWidth - 6, y: y + 13, "text-anchor": "end", "font-size": 11}, chart);
+    text.textContent = p.location;
+    var w = Math.max(1, (width - labelWidth - 90) * p.latency / max);
+    var bar = el("rect", {x: labelWidth, y: y + 3, width: w, height: row - 6, fill: color(p)}, chart);
+    el("title", {}, bar).textContent = label(p);
+    el("text", {x: labelWidth + w + 6, y: y + 13, "font-size": 11}, chart).textContent = p.latency.toFixed(2) + " ms";
+  });
+})();
+</script>
+</body>
+</html>`))
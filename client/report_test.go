@@ -0,0 +1,51 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatHTML(t *testing.T) {
+	berlin := pingResult("Berlin", 10)
+	berlin.Probe.Network = "Deutsche Telekom AG"
+	berlin.Probe.Latitude = 52.52
+	berlin.Probe.Longitude = 13.41
+	berlin.Result.RawOutput = "PING <jsdelivr.com> 56 bytes\n"
+	failed := pingResult("Munich", 0)
+	failed.Result.Stats.Avg = nil
+	failed.Result.Status = "failed"
+	data := model.GetMeasurement{
+		ID:        "abcd",
+		Type:      "ping",
+		Status:    "finished",
+		CreatedAt: "2023-10-01T10:00:00.000Z",
+		Results:   []model.MeasurementResponse{berlin, failed},
+	}
+
+	output, err := client.FormatHTML(data, model.Context{Target: "jsdelivr.com"})
+	assert.NoError(t, err)
+	assert.Contains(t, output, "<title>globalping ping jsdelivr.com</title>")
+	assert.Contains(t, output, `<a href="https://globalping.io?measurement=abcd">abcd</a>`)
+	assert.Contains(t, output, "<dd>2023-10-01T10:00:00.000Z</dd>")
+	assert.Contains(t, output, "<tr><td>Berlin, DE</td><td>Deutsche Telekom AG (AS1)</td><td>finished</td><td class=\"num\">10.00 ms</td><td class=\"num\">0%</td></tr>")
+	assert.Contains(t, output, "<tr><td>Munich, DE</td><td> (AS1)</td><td class=\"failed\">failed</td><td class=\"num\">-</td><td class=\"num\">0%</td></tr>")
+	// The raw output is escaped
+	assert.Contains(t, output, "<pre>PING &lt;jsdelivr.com&gt; 56 bytes</pre>")
+	// The probes are passed to the scripts as JSON
+	assert.Contains(t, output, `"location":"Berlin, DE"`)
+	assert.Contains(t, output, `"lat":52.52,"lon":13.41,"located":true`)
+	assert.Contains(t, output, `"latency":null`)
+	assert.NotContains(t, output, "<script src")
+}
+
+func TestFormatHTMLEmpty(t *testing.T) {
+	output, err := client.FormatHTML(model.GetMeasurement{}, model.Context{Cmd: "traceroute", Target: "jsdelivr.com"})
+	assert.NoError(t, err)
+	assert.Contains(t, output, "<title>globalping traceroute jsdelivr.com</title>")
+	assert.Contains(t, output, "var probes = []")
+	assert.NotContains(t, output, "<dt>Measurement</dt>")
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/pkg/globalping"
+)
+
+// RetryPolicy configures the retries of requests failing with a transient error: a network error, a 5xx status or
+// a 429 status with a Retry-After header
+type RetryPolicy = globalping.RetryPolicy
+
+// Retry is the policy used for every request made to the API
+var Retry = RetryPolicy{}
+
+// ErrInterrupted is returned when a request or a poll is cancelled, e.g. with Ctrl-C
+var ErrInterrupted = errors.New("err: interrupted")
+
+// ErrTimeout is returned when the deadline of a request or a poll is exceeded
+var ErrTimeout = errors.New("err: request timed out - please try again later")
+
+// requestError converts the error of a failed request, keeping cancellation distinct from a network failure
+func requestError(err error, msg string) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrInterrupted
+	case errors.Is(err, context.DeadlineExceeded), os.IsTimeout(err):
+		return ErrTimeout
+	}
+	return errors.New(msg)
+}
+
+// sleep waits for the given duration unless the context is done first
+func sleep(c context.Context, d time.Duration) error {
+	select {
+	case <-c.Done():
+		return requestError(c.Err(), "")
+	case <-time.After(d):
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostRetry(t *testing.T) {
+	retry := client.Retry
+	client.Retry = client.RetryPolicy{Count: 3}
+	t.Cleanup(func() { client.Retry = retry })
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+		}
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	res, err := client.PostAPI(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", res.ID)
+	assert.Equal(t, 3, requests)
+}
+
+func TestPostRetryExhausted(t *testing.T) {
+	retry := client.Retry
+	client.Retry = client.RetryPolicy{Count: 1}
+	t.Cleanup(func() { client.Retry = retry })
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":{"type":"api_error","message":"Internal Server Error"}}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	_, err := client.PostAPI(context.Background(), opts)
+	assert.EqualError(t, err, "err: internal server error - please try again later")
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jsdelivr/globalping-cli/cache"
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Raw JSON of the measurements loaded with LoadMeasurement by ID, returned by GetApiJson instead of fetching the API
+var (
+	savedJson   = map[string]string{}
+	savedJsonMu sync.Mutex
+)
+
+// LoadMeasurement reads a measurement saved as JSON, e.g. with --output results.json or --json, so it can be rendered
+// again without calling the API
+func LoadMeasurement(path string) (model.GetMeasurement, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return model.GetMeasurement{}, fmt.Errorf("err: failed to read %s", path)
+	}
+
+	var data model.GetMeasurement
+	err = json.Unmarshal(b, &data)
+	if err != nil || data.ID == "" || data.Type == "" {
+		return model.GetMeasurement{}, fmt.Errorf("err: %s is not a JSON measurement", path)
+	}
+
+	savedJsonMu.Lock()
+	savedJson[data.ID] = strings.TrimSpace(string(b))
+	savedJsonMu.Unlock()
+	return data, nil
+}
+
+// Return the raw JSON of a measurement loaded with LoadMeasurement
+func loadedJson(id string) (string, bool) {
+	savedJsonMu.Lock()
+	defer savedJsonMu.Unlock()
+	raw, ok := savedJson[id]
+	return raw, ok
+}
+
+// UseCache reads finished measurements from the on-disk cache and stores the ones fetched from the API
+var UseCache bool
+
+// Return the raw JSON of a measurement loaded from a file or from the cache
+func storedJson(id string) ([]byte, bool) {
+	if raw, ok := loadedJson(id); ok {
+		return []byte(raw), true
+	}
+	if !UseCache {
+		return nil, false
+	}
+	return cache.Get(id)
+}
+
+// Store a measurement fetched from the API in the cache once it is finished, results still change while in progress
+func cacheFinished(id string, raw []byte, status string) {
+	if !UseCache || status == "in-progress" {
+		return
+	}
+	err := cache.Put(id, raw)
+	if err != nil {
+		Logf(LevelVerbose, "%s", err)
+	}
+}
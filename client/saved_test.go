@@ -0,0 +1,86 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/cache"
+	"github.com/jsdelivr/globalping-cli/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMeasurement(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	raw := `{"id":"saved1","type":"ping","status":"finished","target":"jsdelivr.com","results":[]}`
+	assert.NoError(t, os.WriteFile(path, []byte(raw+"\n"), 0o644))
+
+	data, err := client.LoadMeasurement(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "saved1", data.ID)
+	assert.Equal(t, "ping", data.Type)
+	assert.Equal(t, "jsdelivr.com", data.Target)
+
+	// The raw JSON is rendered without calling the API
+	client.ApiUrl = "http://127.0.0.1:0"
+	output, err := client.FormatJson(context.Background(), "saved1")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"saved1","type":"ping","status":"finished","target":"jsdelivr.com","results":[],"shareUrl":"https://globalping.io?measurement=saved1"}`, output)
+}
+
+func TestLoadMeasurementErrors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := client.LoadMeasurement(filepath.Join(dir, "missing.json"))
+	assert.EqualError(t, err, "err: failed to read "+filepath.Join(dir, "missing.json"))
+
+	path := filepath.Join(dir, "results.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("probe,latency\n"), 0o644))
+	_, err = client.LoadMeasurement(path)
+	assert.EqualError(t, err, "err: "+path+" is not a JSON measurement")
+}
+
+func TestCache(t *testing.T) {
+	dir := cache.Dir
+	cache.Dir = t.TempDir()
+	client.UseCache = true
+	t.Cleanup(func() {
+		cache.Dir = dir
+		client.UseCache = false
+	})
+
+	requests := 0
+	status := "in-progress"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"id":"cached1","type":"ping","status":"` + status + `","results":[]}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	// In-progress measurements are not cached
+	_, err := client.GetAPI(context.Background(), "cached1")
+	assert.NoError(t, err)
+	_, err = client.GetAPI(context.Background(), "cached1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+
+	status = "finished"
+	_, err = client.GetAPI(context.Background(), "cached1")
+	assert.NoError(t, err)
+	data, err := client.GetAPI(context.Background(), "cached1")
+	assert.NoError(t, err)
+	assert.Equal(t, "finished", data.Status)
+	raw, err := client.GetApiJson(context.Background(), "cached1")
+	assert.NoError(t, err)
+	assert.Contains(t, raw, `"status":"finished"`)
+	assert.Equal(t, 3, requests)
+
+	client.UseCache = false
+	_, err = client.GetAPI(context.Background(), "cached1")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, requests)
+}
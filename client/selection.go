@@ -0,0 +1,165 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// SortKeys are the values accepted by --sort
+var SortKeys = []string{"latency", "country", "network"}
+
+// FilterFields are the probe fields accepted by --filter
+var FilterFields = []string{"continent", "region", "country", "state", "city", "network", "asn", "tag", "status"}
+
+// ParseFilters parses "field=value" filters, several values of a field are separated by commas and match any of them
+func ParseFilters(input []string) (map[string][]string, error) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+
+	filters := map[string][]string{}
+	for _, f := range input {
+		field, value, ok := strings.Cut(f, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || field == "" || strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("invalid filter %q, expected \"field=value\"", f)
+		}
+		known := false
+		for _, name := range FilterFields {
+			known = known || name == field
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown filter field %q, supported fields are %s", field, strings.Join(FilterFields, ", "))
+		}
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				filters[field] = append(filters[field], v)
+			}
+		}
+	}
+	return filters, nil
+}
+
+// Whether a field of a result matches one of the filter values. Codes also match their name, e.g. DE and Germany,
+// networks match a part of their name and ASNs are accepted with or without the AS prefix
+func matchField(field string, values []string, result model.MeasurementResponse) bool {
+	p := result.Probe
+	for _, v := range values {
+		switch field {
+		case "continent":
+			if strings.EqualFold(p.Continent, v) || strings.EqualFold(Continents[p.Continent], v) {
+				return true
+			}
+		case "country":
+			if strings.EqualFold(p.Country, v) || strings.EqualFold(Countries[p.Country], v) {
+				return true
+			}
+		case "region":
+			if strings.EqualFold(p.Region, v) {
+				return true
+			}
+		case "state":
+			if strings.EqualFold(p.State, v) {
+				return true
+			}
+		case "city":
+			if strings.EqualFold(p.City, v) {
+				return true
+			}
+		case "network":
+			if strings.Contains(strings.ToLower(p.Network), strings.ToLower(v)) {
+				return true
+			}
+		case "asn":
+			if strings.TrimPrefix(strings.ToLower(v), "as") == strconv.Itoa(p.ASN) {
+				return true
+			}
+		case "tag":
+			if hasTag(p.Tags, v) {
+				return true
+			}
+		case "status":
+			if strings.EqualFold(result.Result.Status, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Latency of a result used to sort probes, the key metric or the latency of the last hop of traceroute and mtr
+func probeLatency(cmd string, result model.MeasurementResponse) (float64, bool) {
+	if v, ok := KeyMetric(cmd, result); ok {
+		return v, true
+	}
+	return lastHopLatency(cmd, result)
+}
+
+// Sort results by latency, results without latency, e.g. failed probes, are the slowest
+func sortByLatency(cmd string, results []model.MeasurementResponse, descending bool) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, aok := probeLatency(cmd, results[i])
+		b, bok := probeLatency(cmd, results[j])
+		if aok != bok {
+			return aok != descending
+		}
+		if descending {
+			return a > b
+		}
+		return a < b
+	})
+}
+
+// SelectResults returns the measurement with only the results matching the filters of the selection, limited to the
+// best or worst probes and in the selected order. The measurement is not modified
+func SelectResults(cmd string, data model.GetMeasurement, s model.Selection) model.GetMeasurement {
+	if !s.Enabled() {
+		return data
+	}
+
+	results := make([]model.MeasurementResponse, 0, len(data.Results))
+	for _, result := range data.Results {
+		keep := true
+		for field, values := range s.Filters {
+			keep = keep && matchField(field, values, result)
+		}
+		if keep {
+			results = append(results, result)
+		}
+	}
+
+	n := s.Top
+	if s.Worst > 0 {
+		n = s.Worst
+	}
+	if n > 0 {
+		sortByLatency(cmd, results, s.Worst > 0)
+		if len(results) > n {
+			results = results[:n]
+		}
+	}
+
+	switch s.Sort {
+	case "latency":
+		sortByLatency(cmd, results, false)
+	case "country":
+		sort.SliceStable(results, func(i, j int) bool {
+			a, b := results[i].Probe, results[j].Probe
+			if a.Country != b.Country {
+				return a.Country < b.Country
+			}
+			return a.City < b.City
+		})
+	case "network":
+		sort.SliceStable(results, func(i, j int) bool {
+			return strings.ToLower(results[i].Probe.Network) < strings.ToLower(results[j].Probe.Network)
+		})
+	}
+
+	data.Results = results
+	return data
+}
@@ -0,0 +1,65 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilters(t *testing.T) {
+	filters, err := client.ParseFilters([]string{"country=DE,FR", "Network = Telekom"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"country": {"DE", "FR"}, "network": {"Telekom"}}, filters)
+
+	_, err = client.ParseFilters([]string{"DE"})
+	assert.EqualError(t, err, `invalid filter "DE", expected "field=value"`)
+	_, err = client.ParseFilters([]string{"color=red"})
+	assert.EqualError(t, err, `unknown filter field "color", supported fields are continent, region, country, state, city, network, asn, tag, status`)
+}
+
+func TestSelectResults(t *testing.T) {
+	probe := func(city, country, network string, asn int, avg float64) model.MeasurementResponse {
+		r := pingResult(city, avg)
+		r.Probe.Country = country
+		r.Probe.Network = network
+		r.Probe.ASN = asn
+		return r
+	}
+	failed := probe("Lyon", "FR", "Orange", 3215, 0)
+	failed.Result.Status = "failed"
+	failed.Result.Stats = nil
+	data := model.GetMeasurement{Type: "ping", Results: []model.MeasurementResponse{
+		probe("Berlin", "DE", "Deutsche Telekom AG", 3320, 30),
+		probe("Paris", "FR", "Free SAS", 12322, 10),
+		failed,
+		probe("Munich", "DE", "Vodafone GmbH", 3209, 20),
+	}}
+	cities := func(data model.GetMeasurement) []string {
+		var res []string
+		for _, r := range data.Results {
+			res = append(res, r.Probe.City)
+		}
+		return res
+	}
+
+	assert.Equal(t, []string{"Berlin", "Paris", "Lyon", "Munich"}, cities(client.SelectResults("ping", data, model.Selection{})))
+	assert.Equal(t, []string{"Paris", "Munich", "Berlin", "Lyon"}, cities(client.SelectResults("ping", data, model.Selection{Sort: "latency"})))
+	assert.Equal(t, []string{"Berlin", "Munich", "Lyon", "Paris"}, cities(client.SelectResults("ping", data, model.Selection{Sort: "country"})))
+	assert.Equal(t, []string{"Berlin", "Paris", "Lyon", "Munich"}, cities(client.SelectResults("ping", data, model.Selection{Sort: "network"})))
+	assert.Equal(t, []string{"Paris", "Munich"}, cities(client.SelectResults("ping", data, model.Selection{Top: 2})))
+	assert.Equal(t, []string{"Lyon", "Berlin"}, cities(client.SelectResults("ping", data, model.Selection{Worst: 2})))
+
+	filter := func(filters map[string][]string) []string {
+		return cities(client.SelectResults("ping", data, model.Selection{Filters: filters}))
+	}
+	assert.Equal(t, []string{"Berlin", "Munich"}, filter(map[string][]string{"country": {"germany"}}))
+	assert.Equal(t, []string{"Berlin"}, filter(map[string][]string{"country": {"DE"}, "network": {"telekom"}}))
+	assert.Equal(t, []string{"Paris", "Munich"}, filter(map[string][]string{"asn": {"AS12322", "3209"}}))
+	assert.Equal(t, []string{"Lyon"}, filter(map[string][]string{"status": {"failed"}}))
+
+	// The measurement itself is unchanged
+	assert.Equal(t, "Berlin", data.Results[0].Probe.City)
+}
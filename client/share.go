@@ -0,0 +1,83 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ShareUrl is the URL of the measurement on the Globalping website
+func ShareUrl(id string) string {
+	return "https://globalping.io?measurement=" + id
+}
+
+// A field of a JSON object, kept in the order of the document
+type jsonField struct {
+	Name  string
+	Value json.RawMessage
+}
+
+// Decode the fields of a JSON object in order
+func decodeObject(raw string) ([]jsonField, error) {
+	dec := json.NewDecoder(strings.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("not a JSON object")
+	}
+	var fields []jsonField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		f := jsonField{Name: tok.(string)}
+		if err := dec.Decode(&f.Value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// WithShareUrl adds the shareUrl field to the JSON object of a measurement, the other fields are kept as is
+func WithShareUrl(raw string, id string) string {
+	raw = strings.TrimSpace(raw)
+	fields, err := decodeObject(raw)
+	if err != nil {
+		return raw
+	}
+	for _, f := range fields {
+		// Saved results may already have it
+		if f.Name == "shareUrl" {
+			return raw
+		}
+	}
+
+	url, _ := json.Marshal(ShareUrl(id))
+	fields = append(fields, jsonField{Name: "shareUrl", Value: url})
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.Name)
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(f.Value)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// CopyToClipboard copies text to the clipboard using the OSC 52 terminal escape sequence
+func CopyToClipboard(text string) {
+	fmt.Fprintf(os.Stdout, "\033]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+}
@@ -0,0 +1,24 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareUrl(t *testing.T) {
+	assert.Equal(t, "https://globalping.io?measurement=abcd", client.ShareUrl("abcd"))
+}
+
+func TestWithShareUrl(t *testing.T) {
+	assert.Equal(t, `{"id":"abcd","shareUrl":"https://globalping.io?measurement=abcd"}`, client.WithShareUrl(`{"id":"abcd"}`+"\n", "abcd"))
+	assert.Equal(t, `{"shareUrl":"https://globalping.io?measurement=abcd"}`, client.WithShareUrl(`{}`, "abcd"))
+	assert.Equal(t, `not json`, client.WithShareUrl(`not json`, "abcd"))
+	assert.Equal(t, `[{"id":"abcd"}]`, client.WithShareUrl(`[{"id":"abcd"}]`, "abcd"))
+	assert.Equal(t, `{"id":"abcd","shareUrl":"saved"}`, client.WithShareUrl(`{"id":"abcd","shareUrl":"saved"}`, "abcd"))
+	// Only a top level shareUrl is kept
+	assert.Equal(t, `{"id":"abcd","results":[{"shareUrl":"x}"}],"shareUrl":"https://globalping.io?measurement=abcd"}`,
+		client.WithShareUrl(`{"id":"abcd","results":[{"shareUrl":"x}"}]}`, "abcd"))
+}
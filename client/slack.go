@@ -0,0 +1,94 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// SlackText is a text object of a Block Kit message
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SlackBlock is a block of a Block Kit message
+type SlackBlock struct {
+	Type   string      `json:"type"`
+	Text   *SlackText  `json:"text,omitempty"`
+	Fields []SlackText `json:"fields,omitempty"`
+}
+
+// SlackMessage is the payload of a Slack incoming webhook, text is the fallback of the notification
+type SlackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+// worstProbe returns the label and the cell of the probe with the highest latency, failed probes first
+func worstProbe(cmd string, data model.GetMeasurement) (string, string) {
+	label, cell := "-", "-"
+	worst := -1.0
+	for _, result := range data.Results {
+		if result.Result.Status != "finished" {
+			return probeLabel(result.Probe), result.Result.Status
+		}
+		if v, ok := KeyMetric(cmd, result); ok && v > worst {
+			worst = v
+			label, cell = probeLabel(result.Probe), metricCell(cmd, result)
+		}
+	}
+	return label, cell
+}
+
+// NewSlackMessage summarizes a finished measurement as a Block Kit message with the worst probe, the average latency
+// and a link to the results
+func NewSlackMessage(data model.GetMeasurement, ctx model.Context, violations []Violation) SlackMessage {
+	cmd := measurementType(data, ctx)
+
+	status, emoji := "passed", ":white_check_mark:"
+	if MeasurementFailed(data, violations, ctx.Thresholds.FailOnProbeErrors) {
+		status, emoji = "failed", ":x:"
+	}
+	title := fmt.Sprintf("globalping %s %s from %s %s", cmd, ctx.Target, ctx.From, status)
+
+	total, count := 0.0, 0
+	for _, result := range data.Results {
+		if v, ok := KeyMetric(cmd, result); ok {
+			total += v
+			count++
+		}
+	}
+	avg := "-"
+	if count > 0 {
+		avg = fmt.Sprintf("%.2f ms", total/float64(count))
+	}
+	worstLabel, worstCell := worstProbe(cmd, data)
+
+	msg := SlackMessage{
+		Text: title,
+		Blocks: []SlackBlock{
+			{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: fmt.Sprintf("%s *%s*", emoji, title)}},
+			{Type: "section", Fields: []SlackText{
+				{Type: "mrkdwn", Text: "*Target*\n" + ctx.Target},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Probes*\n%d", len(data.Results))},
+				{Type: "mrkdwn", Text: "*Average latency*\n" + avg},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Worst probe*\n%s: %s", worstLabel, worstCell)},
+			}},
+		},
+	}
+
+	if len(violations) > 0 {
+		lines := make([]string, 0, len(violations))
+		for _, v := range violations {
+			lines = append(lines, "• "+v.String())
+		}
+		msg.Blocks = append(msg.Blocks, SlackBlock{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: "*Violations*\n" + strings.Join(lines, "\n")}})
+	}
+
+	if data.ID != "" {
+		msg.Blocks = append(msg.Blocks, SlackBlock{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: fmt.Sprintf("<%s|View the results on globalping.io>", ShareUrl(data.ID))}})
+	}
+	return msg
+}
@@ -0,0 +1,28 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSlackMessage(t *testing.T) {
+	data := model.GetMeasurement{ID: "abcd", Type: "ping", Results: []model.MeasurementResponse{pingResult("Berlin", 10), pingResult("Munich", 30)}}
+	violations := []client.Violation{{Probe: "Munich, DE, ASN:1", Reason: "avg latency 30.00 ms exceeds 20 ms"}}
+
+	msg := client.NewSlackMessage(data, model.Context{Target: "google.com", From: "Germany"}, violations)
+	assert.Equal(t, "globalping ping google.com from Germany failed", msg.Text)
+	assert.Len(t, msg.Blocks, 4)
+	assert.Equal(t, ":x: *globalping ping google.com from Germany failed*", msg.Blocks[0].Text.Text)
+	assert.Equal(t, []client.SlackText{
+		{Type: "mrkdwn", Text: "*Target*\ngoogle.com"},
+		{Type: "mrkdwn", Text: "*Probes*\n2"},
+		{Type: "mrkdwn", Text: "*Average latency*\n20.00 ms"},
+		{Type: "mrkdwn", Text: "*Worst probe*\nMunich, DE, ASN:1: 30.00 ms, 0% loss"},
+	}, msg.Blocks[1].Fields)
+	assert.Equal(t, "*Violations*\n• Munich, DE, ASN:1: avg latency 30.00 ms exceeds 20 ms", msg.Blocks[2].Text.Text)
+	assert.Equal(t, "<https://globalping.io?measurement=abcd|View the results on globalping.io>", msg.Blocks[3].Text.Text)
+}
@@ -0,0 +1,40 @@
+package client
+
+import "math"
+
+// Number of recent values kept to draw a sparkline
+const sparklineSize = 20
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the values with unicode block characters scaled between their min and max
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+
+	res := make([]rune, len(values))
+	for i, v := range values {
+		idx := 0
+		if max > min {
+			idx = int(math.Round((v - min) / (max - min) * float64(len(sparkBlocks)-1)))
+		}
+		res[i] = sparkBlocks[idx]
+	}
+	return string(res)
+}
+
+// Append a value keeping only the most recent ones used by sparklines
+func appendRecent(values []float64, v float64) []float64 {
+	values = append(values, v)
+	if len(values) > sparklineSize {
+		values = values[len(values)-sparklineSize:]
+	}
+	return values
+}
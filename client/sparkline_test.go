@@ -0,0 +1,23 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", Sparkline(nil))
+	assert.Equal(t, "▁▁▁", Sparkline([]float64{5, 5, 5}))
+	assert.Equal(t, "▁▂▃▄▅▆▇█", Sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7}))
+	assert.Equal(t, "█▁▅", Sparkline([]float64{20, 10, 15}))
+}
+
+func TestAppendRecent(t *testing.T) {
+	var values []float64
+	for i := 0; i < sparklineSize+5; i++ {
+		values = appendRecent(values, float64(i))
+	}
+	assert.Len(t, values, sparklineSize)
+	assert.Equal(t, 5.0, values[0])
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// StreamUpdate is a snapshot of an in-progress measurement sent by StreamResults
+type StreamUpdate struct {
+	Data model.GetMeasurement
+	// Changed holds the indexes of the results whose raw output changed since the previous update
+	Changed []int
+	Err     error
+}
+
+// StreamResults polls the API and sends an update whenever a probe reports new partial output.
+// The channel is closed once the measurement is no longer in progress, Wait is over or an error occurs.
+func StreamResults(c context.Context, id string) <-chan StreamUpdate {
+	ch := make(chan StreamUpdate)
+
+	go func() {
+		defer close(ch)
+
+		p := newPoller(id)
+		var prev []string
+		for {
+			data, err := p.get(c)
+			if err != nil {
+				ch <- StreamUpdate{Err: err}
+				return
+			}
+
+			changed := changedResults(prev, data.Results)
+			prev = make([]string, len(data.Results))
+			for i, result := range data.Results {
+				prev[i] = result.Result.RawOutput
+			}
+
+			done := data.Status != "in-progress" || p.expired()
+			if len(changed) > 0 || done {
+				ch <- StreamUpdate{Data: data, Changed: changed}
+			}
+
+			if done {
+				return
+			}
+
+			if err := p.wait(c); err != nil {
+				ch <- StreamUpdate{Err: err}
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Compare raw outputs with the previous snapshot and return the indexes of new or updated results
+func changedResults(prev []string, results []model.MeasurementResponse) []int {
+	var changed []int
+	for i, result := range results {
+		if i >= len(prev) || prev[i] != result.Result.RawOutput {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,38 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamResults(t *testing.T) {
+	responses := []string{
+		`{"id":"abcd","status":"in-progress","results":[{"result":{"rawOutput":"PING"}}]}`,
+		`{"id":"abcd","status":"in-progress","results":[{"result":{"rawOutput":"PING"}}]}`,
+		`{"id":"abcd","status":"in-progress","results":[{"result":{"rawOutput":"PING"}},{"result":{"rawOutput":"PING"}}]}`,
+		`{"id":"abcd","status":"finished","results":[{"result":{"rawOutput":"PING 1"}},{"result":{"rawOutput":"PING"}}]}`,
+	}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(responses[calls]))
+		calls++
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	var changed [][]int
+	for update := range client.StreamResults(context.Background(), "abcd") {
+		assert.NoError(t, update.Err)
+		changed = append(changed, update.Changed)
+	}
+
+	// The second poll has no new output and is skipped
+	assert.Equal(t, [][]int{{0}, {1}, {0}}, changed)
+	assert.Equal(t, 4, calls)
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Percentile returns the p-th percentile (0-100) of sorted values, interpolating linearly between the closest ranks
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// AggregateSummary computes the latency distribution and the overall packet loss across every probe of a measurement
+// and renders it on one line
+func AggregateSummary(cmd string, data model.GetMeasurement) string {
+	var values []float64
+	sent, lost := 0.0, 0.0
+	for _, result := range data.Results {
+		if v, ok := KeyMetric(cmd, result); ok {
+			values = append(values, v)
+		}
+		if cmd == "ping" && result.Result.Stats != nil {
+			sent += float64(result.Result.Stats.Total)
+			lost += float64(result.Result.Stats.Drop)
+		}
+	}
+
+	line := fmt.Sprintf("Summary of %d probes", len(data.Results))
+	if failed := FailedProbes(data); failed > 0 {
+		line += fmt.Sprintf(" (%d failed)", failed)
+	}
+	line += ":"
+	if len(values) == 0 {
+		line += " no latency data"
+	} else {
+		sort.Float64s(values)
+		line += fmt.Sprintf(" min %.2f ms, median %.2f ms, p95 %.2f ms, max %.2f ms",
+			values[0], Percentile(values, 50), Percentile(values, 95), values[len(values)-1])
+	}
+	if sent > 0 {
+		line += fmt.Sprintf(", packet loss %.2f%% (%d/%d)", lost/sent*100, int(lost), int(sent))
+	}
+	return line
+}
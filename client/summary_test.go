@@ -0,0 +1,38 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	values := []float64{10, 20, 30, 40, 50}
+	assert.Equal(t, 10.0, client.Percentile(values, 0))
+	assert.Equal(t, 30.0, client.Percentile(values, 50))
+	assert.Equal(t, 48.0, client.Percentile(values, 95))
+	assert.Equal(t, 50.0, client.Percentile(values, 100))
+	assert.Equal(t, 0.0, client.Percentile(nil, 50))
+}
+
+func TestAggregateSummary(t *testing.T) {
+	berlin := pingResult("Berlin", 10)
+	berlin.Result.Stats.Total = 3
+	berlin.Result.Stats.Drop = 0
+	munich := pingResult("Munich", 30)
+	munich.Result.Stats.Total = 3
+	munich.Result.Stats.Drop = 1
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{berlin, munich, pingResult("Hamburg", 20)}}
+
+	assert.Equal(t, "Summary of 3 probes: min 10.00 ms, median 20.00 ms, p95 29.00 ms, max 30.00 ms, packet loss 16.67% (1/6)", client.AggregateSummary("ping", data))
+	assert.Equal(t, "Summary of 1 probes: no latency data", client.AggregateSummary("traceroute", model.GetMeasurement{Results: []model.MeasurementResponse{{}}}))
+
+	failed := pingResult("Cologne", 0)
+	failed.Result.Status = "failed"
+	failed.Result.Stats = nil
+	data.Results = append(data.Results, failed)
+	assert.Equal(t, "Summary of 4 probes (1 failed): min 10.00 ms, median 20.00 ms, p95 29.00 ms, max 30.00 ms, packet loss 16.67% (1/6)", client.AggregateSummary("ping", data))
+}
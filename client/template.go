@@ -0,0 +1,76 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// TemplateResult is the data of a --template, executed once per probe: the probe and the fields of its result,
+// e.g. {{.Probe.Country}} {{.Stats.Avg}}, with the measurement it is part of, e.g. {{.Measurement.Target}}.
+// Stats and Timings are never nil so failed probes print <nil> values instead of failing the template
+type TemplateResult struct {
+	Probe model.ProbeData
+	model.ResultData
+	Measurement model.GetMeasurement
+}
+
+// Functions available in templates
+func templateFuncs(cmd string) template.FuncMap {
+	return template.FuncMap{
+		// latency is the key latency of the result in milliseconds, "-" if it has none
+		"latency": func(r TemplateResult) string {
+			v, ok := probeLatency(cmd, model.MeasurementResponse{Probe: r.Probe, Result: r.ResultData})
+			if !ok {
+				return "-"
+			}
+			return strconv.FormatFloat(v, 'f', 2, 64)
+		},
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+}
+
+// ParseTemplate parses a --template, the functions are the same for every measurement type
+func ParseTemplate(text string) (*template.Template, error) {
+	t, err := template.New("template").Funcs(templateFuncs("")).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("err: invalid template: %s", err)
+	}
+	return t, nil
+}
+
+// FormatTemplate renders every probe of a measurement with the template of the context, each on its own line unless
+// the template ends with a line break
+func FormatTemplate(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+
+	t, err := ParseTemplate(ctx.Template)
+	if err != nil {
+		return "", err
+	}
+	t.Funcs(templateFuncs(cmd))
+
+	var output strings.Builder
+	for _, result := range data.Results {
+		r := result.Result
+		if r.Stats == nil {
+			r.Stats = &model.PingStats{}
+		}
+		if r.Timings == nil {
+			r.Timings = &model.Timings{}
+		}
+		err := t.Execute(&output, TemplateResult{Probe: result.Probe, ResultData: r, Measurement: data})
+		if err != nil {
+			return "", fmt.Errorf("err: failed to execute the template: %s", err)
+		}
+		if !strings.HasSuffix(ctx.Template, "\n") {
+			output.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(output.String(), "\n"), nil
+}
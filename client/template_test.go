@@ -0,0 +1,30 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTemplate(t *testing.T) {
+	failed := pingResult("Munich", 0)
+	failed.Result.Status = "failed"
+	failed.Result.Stats = nil
+	data := model.GetMeasurement{Type: "ping", Target: "google.com", Results: []model.MeasurementResponse{pingResult("Berlin", 10.5), failed}}
+
+	output, err := client.FormatTemplate(data, model.Context{Template: "{{.Probe.Country}} {{.Probe.City}} {{.Stats.Avg}}ms"})
+	assert.NoError(t, err)
+	assert.Equal(t, "DE Berlin 10.5ms\nDE Munich <nil>ms", output)
+
+	output, err = client.FormatTemplate(data, model.Context{Template: "{{.Measurement.Target}},{{lower .Probe.City}},{{latency .}},{{.Status}}\n"})
+	assert.NoError(t, err)
+	assert.Equal(t, "google.com,berlin,10.50,finished\ngoogle.com,munich,-,failed", output)
+
+	_, err = client.FormatTemplate(data, model.Context{Template: "{{.Probe.Country"})
+	assert.Error(t, err)
+	_, err = client.FormatTemplate(data, model.Context{Template: "{{.Missing}}"})
+	assert.Error(t, err)
+}
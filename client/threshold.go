@@ -0,0 +1,151 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Violation is a threshold not respected by the result of a probe
+type Violation struct {
+	Probe  string
+	Reason string
+}
+
+func (v Violation) String() string {
+	return v.Probe + ": " + v.Reason
+}
+
+// CheckThresholds evaluates the thresholds against every result of a finished measurement
+func CheckThresholds(cmd string, data model.GetMeasurement, th model.Thresholds) []Violation {
+	if !th.Enabled() {
+		return nil
+	}
+
+	tolerate := probeErrorsTolerated(data, th.FailOnProbeErrors)
+	var violations []Violation
+	for _, result := range data.Results {
+		label := probeLabel(result.Probe)
+		for _, reason := range resultViolations(cmd, result, th, tolerate) {
+			violations = append(violations, Violation{Probe: label, Reason: reason})
+		}
+	}
+
+	return violations
+}
+
+// Reasons why a single result fails, a probe that did not finish fails unless the probe errors are tolerated, see
+// probeErrorsTolerated
+func resultViolations(cmd string, result model.MeasurementResponse, th model.Thresholds, tolerate bool) []string {
+	if result.Result.Status != "" && result.Result.Status != "finished" {
+		if tolerate {
+			return nil
+		}
+		reason := "probe " + result.Result.Status
+		if r := FailureReason(result); r != "" {
+			// Only the first line of a multi-line error
+			first, _, _ := strings.Cut(r, "\n")
+			reason += ": " + first
+		}
+		return []string{reason}
+	}
+
+	var reasons []string
+	if th.MaxLatency > 0 {
+		if v, ok := KeyMetric(cmd, result); ok && time.Duration(v*float64(time.Millisecond)) > th.MaxLatency {
+			reasons = append(reasons, fmt.Sprintf("latency %.2f ms exceeds %s", v, th.MaxLatency))
+		}
+	}
+
+	if th.MaxLoss != nil {
+		if v, ok := pingLoss(result); ok && v > *th.MaxLoss {
+			reasons = append(reasons, fmt.Sprintf("packet loss %v%% exceeds %v%%", v, *th.MaxLoss))
+		}
+	}
+
+	if th.ExpectStatus > 0 && cmd == "http" && result.Result.StatusCode != th.ExpectStatus {
+		reasons = append(reasons, fmt.Sprintf("status code %d, expected %d", result.Result.StatusCode, th.ExpectStatus))
+	}
+
+	if cmd == "http" {
+		if th.ExpectBodyContains != "" && !strings.Contains(result.Result.RawBody, th.ExpectBodyContains) {
+			reasons = append(reasons, fmt.Sprintf("body does not contain %q", th.ExpectBodyContains))
+		}
+
+		names := make([]string, 0, len(th.ExpectHeaders))
+		for name := range th.ExpectHeaders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			expected := th.ExpectHeaders[name]
+			value, ok := headerValue(result.Result.Headers, name)
+			if !ok {
+				reasons = append(reasons, fmt.Sprintf("header %s missing", name))
+			} else if !strings.Contains(value, expected) {
+				reasons = append(reasons, fmt.Sprintf("header %s is %q, expected %q", name, value, expected))
+			}
+		}
+
+		if th.MinDaysValid > 0 {
+			if result.Result.TLS == nil {
+				reasons = append(reasons, "no TLS certificate")
+			} else if days, err := DaysUntilExpiry(result.Result.TLS, time.Now()); err != nil {
+				reasons = append(reasons, err.Error())
+			} else if days < th.MinDaysValid {
+				reasons = append(reasons, fmt.Sprintf("certificate expires in %d days, expected at least %d", days, th.MinDaysValid))
+			}
+		}
+	}
+
+	return reasons
+}
+
+// Value of a response header matched case-insensitively, repeated headers are joined with a comma
+func headerValue(headers map[string]interface{}, name string) (string, bool) {
+	for k, v := range headers {
+		if !strings.EqualFold(k, name) {
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			return v, true
+		case []interface{}:
+			values := make([]string, len(v))
+			for i := range v {
+				values[i] = fmt.Sprint(v[i])
+			}
+			return strings.Join(values, ", "), true
+		default:
+			return fmt.Sprint(v), true
+		}
+	}
+	return "", false
+}
+
+// FormatChecks renders the outcome of the thresholds for every probe, passed or failed
+func FormatChecks(cmd string, data model.GetMeasurement, th model.Thresholds) string {
+	tolerate := probeErrorsTolerated(data, th.FailOnProbeErrors)
+	lines := make([]string, len(data.Results))
+	for i, result := range data.Results {
+		label := probeLabel(result.Probe)
+		if reasons := resultViolations(cmd, result, th, tolerate); len(reasons) > 0 {
+			lines[i] = "FAIL " + label + ": " + strings.Join(reasons, ", ")
+		} else {
+			lines[i] = "PASS " + label
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatViolations renders the violations one per line
+func FormatViolations(violations []Violation) string {
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = "threshold failed: " + v.String()
+	}
+	return strings.Join(lines, "\n")
+}
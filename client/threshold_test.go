@@ -0,0 +1,85 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckThresholdsPing(t *testing.T) {
+	slow := pingResult("Berlin", 150)
+	slow.Result.Stats.Loss = 10.0
+	failed := pingResult("Munich", 0)
+	failed.Result.Status = "failed"
+
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Hamburg", 20), slow, failed}}
+
+	assert.Nil(t, client.CheckThresholds("ping", data, model.Thresholds{}))
+
+	maxLoss := 5.0
+	violations := client.CheckThresholds("ping", data, model.Thresholds{MaxLatency: 100 * time.Millisecond, MaxLoss: &maxLoss})
+	assert.Equal(t, []client.Violation{
+		{Probe: "Berlin, DE, ASN:1", Reason: "latency 150.00 ms exceeds 100ms"},
+		{Probe: "Berlin, DE, ASN:1", Reason: "packet loss 10% exceeds 5%"},
+		{Probe: "Munich, DE, ASN:1", Reason: "probe failed"},
+	}, violations)
+
+	assert.Equal(t, `threshold failed: Berlin, DE, ASN:1: latency 150.00 ms exceeds 100ms
+threshold failed: Berlin, DE, ASN:1: packet loss 10% exceeds 5%
+threshold failed: Munich, DE, ASN:1: probe failed`, client.FormatViolations(violations))
+}
+
+func TestCheckThresholdsHttp(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		{Result: model.ResultData{Status: "finished", StatusCode: 200, Timings: &model.Timings{Total: ms(50)}}},
+		{Result: model.ResultData{Status: "finished", StatusCode: 503, Timings: &model.Timings{Total: ms(50)}}},
+	}}
+
+	violations := client.CheckThresholds("http", data, model.Thresholds{ExpectStatus: 200, MaxLatency: time.Second})
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "status code 503, expected 200", violations[0].Reason)
+}
+
+func TestCheckThresholdsHttpAssertions(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		{
+			Probe:  model.ProbeData{City: "Berlin", Country: "DE", ASN: 1},
+			Result: model.ResultData{Status: "finished", RawBody: "<h1>Example Domain</h1>", Headers: map[string]interface{}{"server": "nginx/1.25"}},
+		},
+		{
+			Probe:  model.ProbeData{City: "Paris", Country: "FR", ASN: 2},
+			Result: model.ResultData{Status: "finished", RawBody: "Error", Headers: map[string]interface{}{"server": []interface{}{"cloudflare"}}},
+		},
+		{
+			Probe:  model.ProbeData{City: "Rome", Country: "IT", ASN: 3},
+			Result: model.ResultData{Status: "finished", RawBody: "Example Domain"},
+		},
+	}}
+	th := model.Thresholds{ExpectBodyContains: "Example Domain", ExpectHeaders: map[string]string{"Server": "nginx"}}
+
+	violations := client.CheckThresholds("http", data, th)
+	assert.Equal(t, []client.Violation{
+		{Probe: "Paris, FR, ASN:2", Reason: `body does not contain "Example Domain"`},
+		{Probe: "Paris, FR, ASN:2", Reason: `header Server is "cloudflare", expected "nginx"`},
+		{Probe: "Rome, IT, ASN:3", Reason: "header Server missing"},
+	}, violations)
+
+	assert.Equal(t, `PASS Berlin, DE, ASN:1
+FAIL Paris, FR, ASN:2: body does not contain "Example Domain", header Server is "cloudflare", expected "nginx"
+FAIL Rome, IT, ASN:3: header Server missing`, client.FormatChecks("http", data, th))
+}
+
+func TestCheckThresholdsAnyLoss(t *testing.T) {
+	lossy := pingResult("Berlin", 10)
+	lossy.Result.Stats.Loss = 1
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Hamburg", 20), lossy}}
+
+	noLoss := 0.0
+	assert.Equal(t, []client.Violation{
+		{Probe: "Berlin, DE, ASN:1", Reason: "packet loss 1% exceeds 0%"},
+	}, client.CheckThresholds("ping", data, model.Thresholds{MaxLoss: &noLoss}))
+}
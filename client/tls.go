@@ -0,0 +1,74 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// DaysUntilExpiry returns the number of whole days left before the certificate expires, negative once expired
+func DaysUntilExpiry(cert *model.TLSCertificate, now time.Time) (int, error) {
+	expires, err := time.Parse(time.RFC3339, cert.ExpiresAt)
+	if err != nil {
+		return 0, errors.New("err: invalid certificate expiry date: " + cert.ExpiresAt)
+	}
+	return int(expires.Sub(now).Hours() / 24), nil
+}
+
+// Subject alternative names of a certificate without their type prefix, e.g. "DNS:a.com, DNS:b.com"
+func altNames(alt string) []string {
+	var names []string
+	for _, name := range strings.Split(alt, ",") {
+		name = strings.TrimSpace(name)
+		if i := strings.Index(name, ":"); i >= 0 {
+			name = name[i+1:]
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// FormatTLS renders the TLS certificate seen by every probe of an https measurement
+func FormatTLS(data model.GetMeasurement, ctx model.Context) (string, error) {
+	now := time.Now()
+
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+
+		cert := result.Result.TLS
+		if cert == nil {
+			if result.Result.Status != "" && result.Result.Status != "finished" {
+				output.WriteString("Probe " + result.Result.Status + "\n\n")
+			} else {
+				output.WriteString("No TLS certificate, the target must use HTTPS\n\n")
+			}
+			continue
+		}
+
+		issuer := cert.Issuer.CommonName
+		if cert.Issuer.Organization != "" {
+			issuer += " (" + cert.Issuer.Organization + ")"
+		}
+		output.WriteString(fmt.Sprintf("Subject: %s\n", cert.Subject.CommonName))
+		output.WriteString(fmt.Sprintf("Issuer: %s\n", issuer))
+		output.WriteString(fmt.Sprintf("SANs: %s\n", strings.Join(altNames(cert.Subject.AltNames), ", ")))
+		output.WriteString(fmt.Sprintf("Valid from: %s\n", cert.CreatedAt))
+		output.WriteString(fmt.Sprintf("Valid until: %s\n", cert.ExpiresAt))
+		if days, err := DaysUntilExpiry(cert, now); err == nil {
+			output.WriteString(fmt.Sprintf("Expires in: %d days\n", days))
+		}
+		if cert.Authorized {
+			output.WriteString("Trusted: yes\n\n")
+		} else {
+			output.WriteString(fmt.Sprintf("Trusted: no (%s)\n\n", cert.Error))
+		}
+	}
+
+	return strings.TrimSpace(output.String()), nil
+}
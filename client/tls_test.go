@@ -0,0 +1,62 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tlsResult(city string, expires time.Time) model.MeasurementResponse {
+	cert := &model.TLSCertificate{
+		Authorized: true,
+		CreatedAt:  "2023-01-01T00:00:00.000Z",
+		ExpiresAt:  expires.UTC().Format(time.RFC3339),
+	}
+	cert.Subject.CommonName = "jsdelivr.com"
+	cert.Subject.AltNames = "DNS:jsdelivr.com, DNS:www.jsdelivr.com"
+	cert.Issuer.CommonName = "R3"
+	cert.Issuer.Organization = "Let's Encrypt"
+
+	return model.MeasurementResponse{
+		Probe:  model.ProbeData{Continent: "EU", City: city, Country: "DE", ASN: 1, Network: "Network"},
+		Result: model.ResultData{Status: "finished", TLS: cert},
+	}
+}
+
+func TestFormatTLS(t *testing.T) {
+	expires := time.Now().Add(90*24*time.Hour + time.Hour)
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		tlsResult("Berlin", expires),
+		{Probe: model.ProbeData{Continent: "EU", City: "Munich", Country: "DE", ASN: 1, Network: "Network"}, Result: model.ResultData{Status: "finished"}},
+	}}
+
+	output, err := client.FormatTLS(data, model.Context{CI: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `> EU, DE, Berlin, ASN:1, Network
+Subject: jsdelivr.com
+Issuer: R3 (Let's Encrypt)
+SANs: jsdelivr.com, www.jsdelivr.com
+Valid from: 2023-01-01T00:00:00.000Z
+Valid until: `+expires.UTC().Format(time.RFC3339)+`
+Expires in: 90 days
+Trusted: yes
+
+> EU, DE, Munich, ASN:1, Network
+No TLS certificate, the target must use HTTPS`, output)
+}
+
+func TestCheckThresholdsMinDaysValid(t *testing.T) {
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		tlsResult("Berlin", time.Now().Add(90*24*time.Hour+time.Hour)),
+		tlsResult("Munich", time.Now().Add(10*24*time.Hour+time.Hour)),
+	}}
+
+	violations := client.CheckThresholds("http", data, model.Thresholds{MinDaysValid: 30})
+	assert.Equal(t, []client.Violation{
+		{Probe: "Munich, DE, ASN:1", Reason: "certificate expires in 10 days, expected at least 30"},
+	}, violations)
+}
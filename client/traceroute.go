@@ -0,0 +1,61 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// FormatTracerouteTable renders the hops of every probe of a traceroute measurement as a table, enriched with the
+// ASN and organization of every hop resolved by ResolveASNs unless disabled in the context
+func FormatTracerouteTable(data model.GetMeasurement, ctx model.Context) (string, error) {
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+
+		hops := result.Result.Hops
+		if len(hops) == 0 {
+			output.WriteString("No hops\n\n")
+			continue
+		}
+
+		w := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+		if ctx.NoEnrich {
+			fmt.Fprintln(w, "Hop\tHost\tRTT")
+		} else {
+			fmt.Fprintln(w, "Hop\tHost\tASN\tOrganization\tRTT")
+		}
+
+		for i, hop := range hops {
+			rtts := make([]string, 0)
+			for _, rtt := range hop.Timings.RTTs() {
+				rtts = append(rtts, fmt.Sprintf("%.1f ms", rtt))
+			}
+			rtt := strings.Join(rtts, "  ")
+			if rtt == "" {
+				rtt = "*"
+			}
+			host := hopHost(hop.ResolvedHostname, hop.ResolvedAddress)
+
+			if ctx.NoEnrich {
+				fmt.Fprintf(w, "%d.\t%s\t%s\n", i+1, host, rtt)
+				continue
+			}
+
+			asn, org := "-", "-"
+			if info, ok := knownASN(hop.ResolvedAddress); ok {
+				asn = fmt.Sprintf("AS%d", info.ASN)
+				if info.Name != "" {
+					org = info.Name
+				}
+			}
+			fmt.Fprintf(w, "%d.\t%s\t%s\t%s\t%s\n", i+1, host, asn, org, rtt)
+		}
+		w.Flush()
+		output.WriteString("\n")
+	}
+
+	return strings.TrimRight(output.String(), "\n"), nil
+}
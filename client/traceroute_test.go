@@ -0,0 +1,74 @@
+package client_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTracerouteTable(t *testing.T) {
+	lookup := client.ASNLookup
+	t.Cleanup(func() { client.ASNLookup = lookup })
+	client.ASNLookup = func(c context.Context, ip net.IP) (client.ASNInfo, error) {
+		return client.ASNInfo{ASN: 15169, Name: "GOOGLE, US"}, nil
+	}
+
+	data := model.GetMeasurement{Type: "traceroute", Results: []model.MeasurementResponse{{
+		Probe: model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+		Result: model.ResultData{Status: "finished", Hops: hops(`[
+			{"resolvedAddress": "10.0.0.1", "resolvedHostname": "10.0.0.1", "timings": [{"rtt": 0.5}, {"rtt": 0.4}]},
+			{"resolvedAddress": "", "timings": []},
+			{"resolvedAddress": "142.250.185.78", "resolvedHostname": "fra16s52-in-f14.1e100.net", "timings": [{"rtt": 10.1}]}
+		]`)},
+	}}}
+
+	client.ResolveASNs(context.Background(), data)
+	output, err := client.FormatTable(data, model.Context{CI: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `> EU, DE, Berlin, ASN:1, Network
+Hop  Host                                        ASN      Organization  RTT
+1.   10.0.0.1                                    -        -             0.5 ms  0.4 ms
+2.   ???                                         -        -             *
+3.   fra16s52-in-f14.1e100.net (142.250.185.78)  AS15169  GOOGLE, US    10.1 ms`, output)
+
+	output, err = client.FormatTable(data, model.Context{CI: true, NoEnrich: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `> EU, DE, Berlin, ASN:1, Network
+Hop  Host                                        RTT
+1.   10.0.0.1                                    0.5 ms  0.4 ms
+2.   ???                                         *
+3.   fra16s52-in-f14.1e100.net (142.250.185.78)  10.1 ms`, output)
+}
+
+func TestResolveASNsInterrupted(t *testing.T) {
+	lookup := client.ASNLookup
+	t.Cleanup(func() { client.ASNLookup = lookup })
+	client.ASNLookup = func(c context.Context, ip net.IP) (client.ASNInfo, error) {
+		<-c.Done()
+		return client.ASNInfo{}, c.Err()
+	}
+
+	data := model.GetMeasurement{Type: "traceroute", Results: []model.MeasurementResponse{{
+		Result: model.ResultData{Status: "finished", Hops: hops(`[{"resolvedAddress": "192.0.2.200"}, {"resolvedAddress": "192.0.2.201"}]`)},
+	}}}
+
+	c, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	client.ResolveASNs(c, data)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Interrupted lookups are not cached and run again
+	client.ASNLookup = func(c context.Context, ip net.IP) (client.ASNInfo, error) {
+		return client.ASNInfo{ASN: 64496}, nil
+	}
+	info, ok := client.LookupASN(context.Background(), "192.0.2.200")
+	assert.True(t, ok)
+	assert.Equal(t, 64496, info.ASN)
+}
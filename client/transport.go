@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// HTTPClient is used for every request when set, e.g. to go through a proxy or trust a custom CA
+var HTTPClient *http.Client
+
+// ConnectTimeout limits the time to open a connection to the API, zero means no limit
+var ConnectTimeout = 10 * time.Second
+
+// Connections to the API are kept alive and reused by every request, polling a measurement reuses the same one
+var sharedTransport = newTransport()
+
+// Create a transport with a pool of keep-alive connections, HTTP/2 when the server supports it
+// and the proxy of the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		// ConnectTimeout is read on every dial so it can be changed after the transport is created
+		DialContext: func(c context.Context, network, addr string) (net.Conn, error) {
+			d := net.Dialer{Timeout: ConnectTimeout, KeepAlive: 30 * time.Second}
+			return d.DialContext(c, network, addr)
+		},
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// Return the client of every request, HTTPClient or one of the shared transport.
+// Clients are cheap, the pooled connections belong to the transport, so a new one picks up changes of Timeout.
+func httpClient() *http.Client {
+	if HTTPClient != nil {
+		return HTTPClient
+	}
+	return &http.Client{Transport: sharedTransport, Timeout: Timeout}
+}
+
+// NewTransport creates a transport going through proxy, falling back to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables, and trusting the certificates of caCert in addition to the system ones.
+// Insecure disables the verification of the certificate of the server.
+func NewTransport(proxy string, caCert string, insecure bool) (*http.Transport, error) {
+	t := newTransport()
+
+	if proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url: %s", proxy)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	if caCert != "" || insecure {
+		t.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecure}
+	}
+
+	if caCert != "" {
+		pem, err := os.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificate: %s", caCert)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no PEM certificate found in " + caCert)
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+
+	return t, nil
+}
@@ -0,0 +1,131 @@
+package client_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+	t.Cleanup(func() { client.HTTPClient = nil })
+
+	// The certificate of the test server is not trusted by default
+	_, err := client.PostAPI(context.Background(), opts)
+	assert.Error(t, err)
+
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	err = os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+	assert.NoError(t, err)
+
+	transport, err := client.NewTransport("", caCert, false)
+	assert.NoError(t, err)
+	client.HTTPClient = &http.Client{Transport: transport}
+
+	res, err := client.PostAPI(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", res.ID)
+}
+
+func TestInsecure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+	t.Cleanup(func() { client.HTTPClient = nil })
+
+	transport, err := client.NewTransport("", "", true)
+	assert.NoError(t, err)
+	client.HTTPClient = &http.Client{Transport: transport}
+
+	res, err := client.PostAPI(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", res.ID)
+}
+
+func TestProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+	}))
+	defer proxy.Close()
+	client.ApiUrl = "http://api.globalping.test/v1"
+	t.Cleanup(func() { client.HTTPClient = nil })
+
+	transport, err := client.NewTransport(proxy.URL, "", false)
+	assert.NoError(t, err)
+	client.HTTPClient = &http.Client{Transport: transport}
+
+	_, err = client.PostAPI(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://api.globalping.test/v1/measurements", proxied)
+}
+
+func TestNewTransportErrors(t *testing.T) {
+	_, err := client.NewTransport("not a url", "", false)
+	assert.EqualError(t, err, "invalid proxy url: not a url")
+
+	_, err = client.NewTransport("", filepath.Join(t.TempDir(), "missing.pem"), false)
+	assert.ErrorContains(t, err, "failed to read the CA certificate")
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, []byte("nothing"), 0600)
+	_, err = client.NewTransport("", empty, false)
+	assert.EqualError(t, err, "no PEM certificate found in "+empty)
+}
+
+// Polling a measurement reuses the connection of the previous request
+func BenchmarkGetAPIKeepAlive(b *testing.B) {
+	benchmarkGetAPI(b, nil)
+}
+
+// Opening a new connection for every request, as a baseline of BenchmarkGetAPIKeepAlive
+func BenchmarkGetAPINewConnection(b *testing.B) {
+	benchmarkGetAPI(b, &http.Client{Transport: &http.Transport{DisableKeepAlives: true}})
+}
+
+func benchmarkGetAPI(b *testing.B, httpClient *http.Client) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"abcd","status":"in-progress","probesCount":1}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	// Trust the certificate of the test server with both transports
+	transport, err := client.NewTransport("", "", true)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Transport: transport}
+	} else {
+		httpClient.Transport.(*http.Transport).TLSClientConfig = transport.TLSClientConfig
+	}
+	client.HTTPClient = httpClient
+	b.Cleanup(func() { client.HTTPClient = nil })
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := client.GetAPI(context.Background(), "abcd")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,10 +1,11 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jsdelivr/globalping-cli/model"
@@ -50,11 +51,12 @@ func generateHeader(result model.MeasurementResponse, ctx model.Context) string
 	var output strings.Builder
 
 	// Continent + Country + (State) + City + ASN + Network + (Region Tag)
-	output.WriteString(result.Probe.Continent + ", " + result.Probe.Country + ", ")
+	location := result.Probe.Continent + ", " + result.Probe.Country + ", "
 	if result.Probe.State != "" {
-		output.WriteString("(" + result.Probe.State + "), ")
+		location += "(" + result.Probe.State + "), "
 	}
-	output.WriteString(result.Probe.City + ", ASN:" + fmt.Sprint(result.Probe.ASN) + ", " + result.Probe.Network)
+	location += result.Probe.City
+	output.WriteString(", ASN:" + fmt.Sprint(result.Probe.ASN) + ", " + result.Probe.Network)
 
 	// Check tags to see if there's a region code
 	if len(result.Probe.Tags) > 0 {
@@ -67,59 +69,89 @@ func generateHeader(result model.MeasurementResponse, ctx model.Context) string
 		}
 	}
 
+	// Echo the resolver that answered a dns query
+	if ctx.Cmd == "dns" && result.Result.Resolver != "" {
+		output.WriteString(" - resolver " + result.Result.Resolver)
+	}
+
+	// Show the address family the target was resolved to
+	if ip := net.ParseIP(result.Result.ResolvedAddress); ip != nil {
+		if ip.To4() != nil {
+			output.WriteString(" - IPv4 " + result.Result.ResolvedAddress)
+		} else {
+			output.WriteString(" - IPv6 " + result.Result.ResolvedAddress)
+		}
+	}
+
 	if ctx.CI {
-		return "> " + output.String()
+		return "> " + location + output.String()
+	} else if result.Result.Status == "failed" {
+		return arrow + failed.Render(location+output.String())
 	} else {
-		return arrow + highlight.Render(output.String())
+		// The probe metadata is dimmed next to its location
+		return arrow + highlight.Render(location) + dimmed.Render(output.String())
 	}
 }
 
-func LiveView(id string, data model.GetMeasurement, ctx model.Context) {
-	var err error
+// Used to fit every probe section in the terminal in live view, each section keeps its header and latest lines
+func sliceSections(sections []string, w, h int) string {
+	if len(sections) == 0 {
+		return ""
+	}
 
-	// Create new writer
-	writer, _ := pterm.DefaultArea.Start()
-	w, h, _ := pterm.GetTerminalSize()
+	// Split the available height between probes, keeping at least the header and one line each
+	per := (h - 2) / len(sections)
+	if per < 2 {
+		per = 2
+	}
 
-	// String builder for output
-	var output strings.Builder
+	sliced := make([]string, len(sections))
+	for i, section := range sections {
+		header, body, _ := strings.Cut(section, "\n")
+		sliced[i] = sliceOutput(header, w, 3) + "\n" + sliceOutput(body, w, per+1)
+	}
 
-	// Poll API every 100 milliseconds until the measurement is complete
-	for data.Status == "in-progress" {
-		time.Sleep(100 * time.Millisecond)
-		data, err = GetAPI(id)
+	return strings.Join(sliced, "\n\n")
+}
 
-		// Reset string builder
-		output.Reset()
+// LiveView renders the measurement while it is in progress and returns its final state
+func LiveView(c context.Context, id string, data model.GetMeasurement, ctx model.Context) (model.GetMeasurement, error) {
+	// Create new writer
+	writer, _ := pterm.DefaultArea.Start()
+	w, h, _ := pterm.GetTerminalSize()
 
-		// Output every result in case of multiple probes
-		for _, result := range data.Results {
-			// Output slightly different format if state is available
-			output.WriteString(generateHeader(result, ctx) + "\n")
+	// Rendered section of every probe, only rebuilt when its output changes
+	sections := make([]string, len(data.Results))
 
-			// Output only latency values if flag is set
-			output.WriteString(strings.TrimSpace(result.Result.RawOutput) + "\n\n")
+	for update := range StreamResults(c, id) {
+		if update.Err != nil {
+			writer.Stop()
+			return model.GetMeasurement{}, update.Err
+		}
+		data = update.Data
 
+		for len(sections) < len(update.Data.Results) {
+			sections = append(sections, "")
 		}
 
-		if err != nil {
-			writer.Stop()
-			fmt.Println(err)
-			return
+		for _, i := range update.Changed {
+			result := update.Data.Results[i]
+			sections[i] = generateHeader(result, ctx) + "\n" + resultOutput(result)
 		}
 
-		writer.Update(sliceOutput(output.String(), w, h))
+		writer.Update(sliceSections(sections, w, h))
 	}
 
 	// Stop live updater and output to stdout
 	writer.RemoveWhenDone = true
 	writer.Stop()
-	fmt.Println(strings.TrimSpace(output.String()))
+	fmt.Println(strings.TrimSpace(strings.Join(sections, "\n\n")))
+	return data, nil
 }
 
 // If json flag is used, only output json
-func OutputJson(id string) {
-	output, err := GetApiJson(id)
+func OutputJson(c context.Context, id string) {
+	output, err := FormatJson(c, id)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -127,8 +159,17 @@ func OutputJson(id string) {
 	fmt.Println(output)
 }
 
-// If latency flag is used, only output latency values
-func OutputLatency(id string, data model.GetMeasurement, ctx model.Context) {
+// FormatJson returns the raw JSON of a measurement with its share URL
+func FormatJson(c context.Context, id string) (string, error) {
+	output, err := GetApiJson(c, id)
+	if err != nil {
+		return "", err
+	}
+	return WithShareUrl(output, id), nil
+}
+
+// FormatLatency returns the latency values of every probe
+func FormatLatency(data model.GetMeasurement, ctx model.Context) (string, error) {
 	// String builder for output
 	var output strings.Builder
 
@@ -139,70 +180,71 @@ func OutputLatency(id string, data model.GetMeasurement, ctx model.Context) {
 
 		if ctx.CI {
 			if ctx.Cmd == "ping" {
-				output.WriteString(fmt.Sprintf("Min: %v ms\n", result.Result.Stats["min"]))
-				output.WriteString(fmt.Sprintf("Max: %v ms\n", result.Result.Stats["max"]))
-				output.WriteString(fmt.Sprintf("Avg: %v ms\n\n", result.Result.Stats["avg"]))
+				s := pingStats(result)
+				output.WriteString(fmt.Sprintf("Min: %v ms\n", msOrNil(s.Min)))
+				output.WriteString(fmt.Sprintf("Max: %v ms\n", msOrNil(s.Max)))
+				output.WriteString(fmt.Sprintf("Avg: %v ms\n", msOrNil(s.Avg)))
+				if rtt, ok := PingRttStats(result); ok {
+					output.WriteString(fmt.Sprintf("P50: %.3f ms\n", rtt.P50))
+					output.WriteString(fmt.Sprintf("P90: %.3f ms\n", rtt.P90))
+					output.WriteString(fmt.Sprintf("P99: %.3f ms\n", rtt.P99))
+					output.WriteString(fmt.Sprintf("Jitter: %.3f ms\n", rtt.Jitter))
+				}
+				output.WriteString("\n")
 			}
 
 			if ctx.Cmd == "dns" {
-				timings, err := DecodeTimings(ctx.Cmd, result.Result.TimingsRaw)
-				if err != nil {
-					fmt.Println(err)
-					return
-				}
-				output.WriteString(fmt.Sprintf("Total: %v ms\n", timings.Interface["total"]))
+				timings := result.Result.Timings.Phases()
+				output.WriteString(fmt.Sprintf("Total: %v ms\n", msOrNil(timings["total"])))
 			}
 
 			if ctx.Cmd == "http" {
-				timings, err := DecodeTimings(ctx.Cmd, result.Result.TimingsRaw)
-				if err != nil {
-					fmt.Println(err)
-					return
-				}
-				output.WriteString(fmt.Sprintf("Total: %v ms\n", timings.Interface["total"]))
-				output.WriteString(fmt.Sprintf("Download: %v ms\n", timings.Interface["download"]))
-				output.WriteString(fmt.Sprintf("First byte: %v ms\n", timings.Interface["firstByte"]))
-				output.WriteString(fmt.Sprintf("DNS: %v ms\n", timings.Interface["dns"]))
-				output.WriteString(fmt.Sprintf("TLS: %v ms\n", timings.Interface["tls"]))
-				output.WriteString(fmt.Sprintf("TCP: %v ms\n", timings.Interface["tcp"]))
+				timings := result.Result.Timings.Phases()
+				output.WriteString(fmt.Sprintf("Total: %v ms\n", msOrNil(timings["total"])))
+				output.WriteString(fmt.Sprintf("Download: %v ms\n", msOrNil(timings["download"])))
+				output.WriteString(fmt.Sprintf("First byte: %v ms\n", msOrNil(timings["firstByte"])))
+				output.WriteString(fmt.Sprintf("DNS: %v ms\n", msOrNil(timings["dns"])))
+				output.WriteString(fmt.Sprintf("TLS: %v ms\n", msOrNil(timings["tls"])))
+				output.WriteString(fmt.Sprintf("TCP: %v ms\n", msOrNil(timings["tcp"])))
 			}
 		} else {
 			if ctx.Cmd == "ping" {
-				output.WriteString(bold.Render("Min: ") + fmt.Sprintf("%v ms\n", result.Result.Stats["min"]))
-				output.WriteString(bold.Render("Max: ") + fmt.Sprintf("%v ms\n", result.Result.Stats["max"]))
-				output.WriteString(bold.Render("Avg: ") + fmt.Sprintf("%v ms\n\n", result.Result.Stats["avg"]))
+				s := pingStats(result)
+				output.WriteString(bold.Render("Min: ") + colorLatency(msOrNil(s.Min), "%v ms") + "\n")
+				output.WriteString(bold.Render("Max: ") + colorLatency(msOrNil(s.Max), "%v ms") + "\n")
+				output.WriteString(bold.Render("Avg: ") + colorLatency(msOrNil(s.Avg), "%v ms") + "\n")
+				if rtt, ok := PingRttStats(result); ok {
+					output.WriteString(bold.Render("P50: ") + colorLatency(rtt.P50, "%.3f ms") + "\n")
+					output.WriteString(bold.Render("P90: ") + colorLatency(rtt.P90, "%.3f ms") + "\n")
+					output.WriteString(bold.Render("P99: ") + colorLatency(rtt.P99, "%.3f ms") + "\n")
+					output.WriteString(bold.Render("Jitter: ") + fmt.Sprintf("%.3f ms\n", rtt.Jitter))
+				}
+				output.WriteString("\n")
 			}
 
 			if ctx.Cmd == "dns" {
-				timings, err := DecodeTimings(ctx.Cmd, result.Result.TimingsRaw)
-				if err != nil {
-					fmt.Println(err)
-					return
-				}
-				output.WriteString(bold.Render("Total: ") + fmt.Sprintf("%v ms\n", timings.Interface["total"]))
+				timings := result.Result.Timings.Phases()
+				output.WriteString(bold.Render("Total: ") + colorLatency(msOrNil(timings["total"]), "%v ms") + "\n")
 			}
 
 			if ctx.Cmd == "http" {
-				timings, err := DecodeTimings(ctx.Cmd, result.Result.TimingsRaw)
-				if err != nil {
-					fmt.Println(err)
-					return
-				}
-				output.WriteString(bold.Render("Total: ") + fmt.Sprintf("%v ms\n", timings.Interface["total"]))
-				output.WriteString(bold.Render("Download: ") + fmt.Sprintf("%v ms\n", timings.Interface["download"]))
-				output.WriteString(bold.Render("First byte: ") + fmt.Sprintf("%v ms\n", timings.Interface["firstByte"]))
-				output.WriteString(bold.Render("DNS: ") + fmt.Sprintf("%v ms\n", timings.Interface["dns"]))
-				output.WriteString(bold.Render("TLS: ") + fmt.Sprintf("%v ms\n", timings.Interface["tls"]))
-				output.WriteString(bold.Render("TCP: ") + fmt.Sprintf("%v ms\n", timings.Interface["tcp"]))
+				timings := result.Result.Timings.Phases()
+				output.WriteString(bold.Render("Total: ") + colorLatency(msOrNil(timings["total"]), "%v ms") + "\n")
+				output.WriteString(bold.Render("Download: ") + fmt.Sprintf("%v ms\n", msOrNil(timings["download"])))
+				output.WriteString(bold.Render("First byte: ") + fmt.Sprintf("%v ms\n", msOrNil(timings["firstByte"])))
+				output.WriteString(bold.Render("DNS: ") + fmt.Sprintf("%v ms\n", msOrNil(timings["dns"])))
+				output.WriteString(bold.Render("TLS: ") + fmt.Sprintf("%v ms\n", msOrNil(timings["tls"])))
+				output.WriteString(bold.Render("TCP: ") + fmt.Sprintf("%v ms\n", msOrNil(timings["tcp"])))
 			}
 		}
 
 	}
 
-	fmt.Println(strings.TrimSpace(output.String()))
+	return strings.TrimSpace(output.String()), nil
 }
 
-func OutputCI(id string, data model.GetMeasurement, ctx model.Context) {
+// FormatCI returns the raw output of every probe without colors or live updates
+func FormatCI(data model.GetMeasurement, ctx model.Context) string {
 	// String builder for output
 	var output strings.Builder
 
@@ -211,55 +253,107 @@ func OutputCI(id string, data model.GetMeasurement, ctx model.Context) {
 		// Output slightly different format if state is available
 		output.WriteString(generateHeader(result, ctx) + "\n")
 
-		// Output only latency values if flag is set
-		output.WriteString(strings.TrimSpace(result.Result.RawOutput) + "\n\n")
+		output.WriteString(resultOutput(result) + "\n")
+		if result.Result.Status == "in-progress" {
+			output.WriteString(warn.Render("Incomplete: the probe did not finish in time") + "\n")
+		}
+		output.WriteString("\n")
 	}
 
-	fmt.Println(strings.TrimSpace(output.String()))
+	return strings.TrimSpace(output.String())
 }
 
-func OutputResults(id string, ctx model.Context) {
+// OutputResults waits for the measurement to finish while displaying it and returns its final state
+func OutputResults(c context.Context, id string, ctx model.Context) (model.GetMeasurement, error) {
 	// Wait for first result to arrive from a probe before starting display (can be in-progress)
-	data, err := GetAPI(id)
+	p := newPoller(id)
+	data, err := p.get(c)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return model.GetMeasurement{}, err
 	}
 
 	// Probe may not have started yet
-	for len(data.Results) == 0 {
-		time.Sleep(100 * time.Millisecond)
-		data, err = GetAPI(id)
+	for len(data.Results) == 0 && !p.expired() {
+		if err := p.wait(c); err != nil {
+			return model.GetMeasurement{}, err
+		}
+		data, err = p.get(c)
+		if err != nil {
+			return model.GetMeasurement{}, err
+		}
+	}
+
+	// Selected results are only known once every probe finished
+	toFile := ctx.Output != "" && ctx.Output != "-"
+	final := ctx.JsonOutput || ctx.Latency || ctx.Format != "" || toFile || ctx.Quiet || ctx.Selection.Enabled() || ctx.GroupBy != "" || ctx.Query != "" || ctx.Template != ""
+	if ctx.Live && !final {
+		return LiveLogResults(c, id, ctx)
+	}
+	if !ctx.CI && !final {
+		return LiveView(c, id, data, ctx)
+	}
+
+	data, err = WaitForResults(c, id)
+	if err != nil {
+		return model.GetMeasurement{}, err
+	}
+
+	OutputFinished(c, id, data, ctx)
+	return data, nil
+}
+
+// OutputFinished prints a finished measurement in the output selected by the context, without live updates
+func OutputFinished(c context.Context, id string, data model.GetMeasurement, ctx model.Context) {
+	if ctx.Output != "" && ctx.Output != "-" {
+		summary, err := OutputFile(c, id, data, ctx)
 		if err != nil {
 			fmt.Println(err)
 			return
 		}
+		fmt.Println(summary)
+		return
 	}
 
-	if ctx.CI || ctx.JsonOutput || ctx.Latency {
-		// Poll API every 100 milliseconds until the measurement is complete
-		for data.Status == "in-progress" {
-			time.Sleep(100 * time.Millisecond)
-			data, err = GetAPI(id)
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
-		}
+	if ctx.Quiet && !ctx.JsonOutput && ctx.Format == "" {
+		fmt.Println(AggregateSummary(ctx.Cmd, data))
+		return
+	}
+
+	output, err := RenderFinished(c, id, data, ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(output)
+}
+
+// RenderFinished returns a finished measurement in the output selected by the context
+func RenderFinished(c context.Context, id string, data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+	data = SelectResults(cmd, data, ctx.Selection)
+	// Only the table and the AS path show the ASN of the hops
+	if cmd == "traceroute" && !ctx.NoEnrich && ctx.Query == "" && ctx.Template == "" && (ctx.Format == "table" || ctx.Format == "aspath") {
+		ResolveASNs(c, data)
 	}
 
 	switch {
+	case ctx.Query != "":
+		return QueryResults(c, data, ctx.Query)
+	case ctx.Template != "":
+		return FormatTemplate(data, ctx)
+	case ctx.Format != "":
+		f, ok := formatters[ctx.Format]
+		if !ok {
+			return "", fmt.Errorf("err: unknown format: %s", ctx.Format)
+		}
+		return f(data, ctx)
 	case ctx.JsonOutput:
-		OutputJson(id)
-		return
+		return FormatJson(c, id)
 	case ctx.Latency:
-		OutputLatency(id, data, ctx)
-		return
-	case ctx.CI:
-		OutputCI(id, data, ctx)
-		return
+		return FormatLatency(data, ctx)
+	case ctx.GroupBy != "":
+		return FormatGroups(data, ctx), nil
 	default:
-		LiveView(id, data, ctx)
-		return
+		return FormatCI(data, ctx), nil
 	}
 }
@@ -50,3 +50,34 @@ func testHeadersTags(t *testing.T) {
 	newResult.Probe.Tags = []string{"tag", "tag2"}
 	assert.Equal(t, "> Continent, Country, (State), City, ASN:12345, Network (tag2)", generateHeader(newResult, testContext))
 }
+
+func TestSliceSections(t *testing.T) {
+	sections := []string{
+		"> Probe 1\nline 1\nline 2\nline 3",
+		"> Probe 2\nline 1",
+	}
+
+	assert.Equal(t, "> Probe 1\nline 3\n\n> Probe 2\nline 1", sliceSections(sections, 80, 6))
+	assert.Equal(t, "> Pro\nline \n\n> Pro\nline ", sliceSections(sections, 5, 6))
+	assert.Equal(t, "", sliceSections(nil, 80, 6))
+}
+
+func TestGenerateHeaderResolver(t *testing.T) {
+	result := model.MeasurementResponse{
+		Probe:  model.ProbeData{Continent: "EU", Country: "NL", City: "Amsterdam", ASN: 60404, Network: "Liteserver"},
+		Result: model.ResultData{Resolver: "8.8.8.8"},
+	}
+	assert.Equal(t, "> EU, NL, Amsterdam, ASN:60404, Liteserver - resolver 8.8.8.8", generateHeader(result, model.Context{Cmd: "dns", CI: true}))
+	assert.Equal(t, "> EU, NL, Amsterdam, ASN:60404, Liteserver", generateHeader(result, model.Context{Cmd: "ping", CI: true}))
+}
+
+func TestGenerateHeaderAddressFamily(t *testing.T) {
+	result := model.MeasurementResponse{
+		Probe:  model.ProbeData{Continent: "EU", Country: "NL", City: "Amsterdam", ASN: 60404, Network: "Liteserver"},
+		Result: model.ResultData{ResolvedAddress: "2606:4700::6810:84e5"},
+	}
+	assert.Equal(t, "> EU, NL, Amsterdam, ASN:60404, Liteserver - IPv6 2606:4700::6810:84e5", generateHeader(result, model.Context{Cmd: "ping", CI: true}))
+
+	result.Result.ResolvedAddress = "104.16.132.229"
+	assert.Equal(t, "> EU, NL, Amsterdam, ASN:60404, Liteserver - IPv4 104.16.132.229", generateHeader(result, model.Context{Cmd: "ping", CI: true}))
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Relative latency change between two runs that gets highlighted in watch mode
+const significantChange = 0.2
+
+var (
+	worse  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#E5484D"))
+	better = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#17D4A7"))
+)
+
+// Watch keeps the results of the previous run to highlight what changed in watch mode
+type Watch struct {
+	cmd  string
+	prev map[string]float64
+	// Recent latencies of every probe drawn as sparklines
+	recent map[string][]float64
+}
+
+func NewWatch(cmd string) *Watch {
+	return &Watch{cmd: cmd, prev: map[string]float64{}, recent: map[string][]float64{}}
+}
+
+// Render returns a table of the latency of every probe, marking significant changes since the previous run.
+// Measurement types without a latency value are rendered with their raw output.
+func (w *Watch) Render(data model.GetMeasurement, ctx model.Context) string {
+	if len(data.Results) > 0 {
+		if _, ok := KeyMetric(w.cmd, data.Results[0]); !ok {
+			var output strings.Builder
+			for _, result := range data.Results {
+				output.WriteString(generateHeader(result, ctx) + "\n")
+				output.WriteString(resultOutput(result) + "\n\n")
+			}
+			return strings.TrimSpace(output.String())
+		}
+	}
+
+	var output strings.Builder
+	tw := tabwriter.NewWriter(&output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROBE\tLATENCY\tCHANGE\tTREND")
+
+	cur := map[string]float64{}
+	for _, result := range data.Results {
+		label := probeLabel(result.Probe)
+		v, ok := KeyMetric(w.cmd, result)
+		if !ok {
+			fmt.Fprintf(tw, "%s\t%s\t\t%s\n", label, metricCell(w.cmd, result), Sparkline(w.recent[label]))
+			continue
+		}
+		cur[label] = v
+		w.recent[label] = appendRecent(w.recent[label], v)
+
+		change := ""
+		if prev, ok := w.prev[label]; ok {
+			change = formatChange(prev, v, ctx)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", label, metricCell(w.cmd, result), change, Sparkline(w.recent[label]))
+	}
+	tw.Flush()
+
+	w.prev = cur
+	return strings.TrimSpace(output.String())
+}
+
+// Format the latency change between two runs, highlighting significant changes
+func formatChange(prev, cur float64, ctx model.Context) string {
+	delta := cur - prev
+	change := fmt.Sprintf("%+.2f ms", delta)
+
+	if prev == 0 || math.Abs(delta)/prev < significantChange {
+		return change
+	}
+
+	if delta > 0 {
+		change += " ▲"
+		if !ctx.CI {
+			return worse.Render(change)
+		}
+		return change
+	}
+
+	change += " ▼"
+	if !ctx.CI {
+		return better.Render(change)
+	}
+	return change
+}
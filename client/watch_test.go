@@ -0,0 +1,38 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchRender(t *testing.T) {
+	ctx := model.Context{CI: true}
+	w := client.NewWatch("ping")
+
+	first := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 10), pingResult("Munich", 20)}}
+	assert.Equal(t, `PROBE              LATENCY            CHANGE  TREND
+Berlin, DE, ASN:1  10.00 ms, 0% loss          ▁
+Munich, DE, ASN:1  20.00 ms, 0% loss          ▁`, w.Render(first, ctx))
+
+	second := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 10.5), pingResult("Munich", 40)}}
+	assert.Equal(t, `PROBE              LATENCY            CHANGE       TREND
+Berlin, DE, ASN:1  10.50 ms, 0% loss  +0.50 ms     ▁█
+Munich, DE, ASN:1  40.00 ms, 0% loss  +20.00 ms ▲  ▁█`, w.Render(second, ctx))
+
+	third := model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 5), pingResult("Munich", 40)}}
+	assert.Contains(t, w.Render(third, ctx), "-5.50 ms ▼  ▇█▁")
+}
+
+func TestWatchRenderRaw(t *testing.T) {
+	w := client.NewWatch("traceroute")
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{{
+		Probe:  model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+		Result: model.ResultData{RawOutput: "traceroute to google.com\n"},
+	}}}
+
+	assert.Equal(t, "> EU, DE, Berlin, ASN:1, Network\ntraceroute to google.com", w.Render(data, model.Context{CI: true}))
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Width of the waterfall bars in characters
+const waterfallWidth = 40
+
+// Phases of an http request in the order they happen
+var waterfallPhases = []struct {
+	label string
+	value func(t *model.Timings) *float64
+}{
+	{"DNS", func(t *model.Timings) *float64 { return t.DNS }},
+	{"TCP", func(t *model.Timings) *float64 { return t.TCP }},
+	{"TLS", func(t *model.Timings) *float64 { return t.TLS }},
+	{"First byte", func(t *model.Timings) *float64 { return t.FirstByte }},
+	{"Download", func(t *model.Timings) *float64 { return t.Download }},
+}
+
+// Total time of a request, the sum of its phases if the probe did not report it
+func waterfallTotal(t *model.Timings) float64 {
+	if v, ok := t.TotalMs(); ok {
+		return v
+	}
+	sum := 0.0
+	for _, p := range waterfallPhases {
+		if v := p.value(t); v != nil {
+			sum += *v
+		}
+	}
+	return sum
+}
+
+// Bar of a phase starting after start milliseconds, on a scale of scale milliseconds for the whole width
+func waterfallBar(start, d, scale float64) string {
+	from := int(math.Round(start / scale * waterfallWidth))
+	to := int(math.Round((start + d) / scale * waterfallWidth))
+	if from > waterfallWidth-1 {
+		from = waterfallWidth - 1
+	}
+	if to <= from {
+		to = from + 1
+	}
+	if to > waterfallWidth {
+		to = waterfallWidth
+	}
+	return strings.Repeat(" ", from) + strings.Repeat("█", to-from) + strings.Repeat(" ", waterfallWidth-to)
+}
+
+// FormatWaterfall renders the timings of every probe of an http measurement as a waterfall: one bar per phase,
+// starting where the previous phase ended. All the probes share the same scale, the longest phase of every probe is
+// highlighted and every phase shows its share of the total time.
+func FormatWaterfall(data model.GetMeasurement, ctx model.Context) (string, error) {
+	cmd := measurementType(data, ctx)
+	if cmd != "http" {
+		return "", fmt.Errorf("err: the waterfall format is not supported for %s measurements", cmd)
+	}
+
+	scale := 0.0
+	for _, result := range data.Results {
+		if result.Result.Timings != nil {
+			scale = math.Max(scale, waterfallTotal(result.Result.Timings))
+		}
+	}
+
+	var output strings.Builder
+	for _, result := range data.Results {
+		output.WriteString(generateHeader(result, ctx) + "\n")
+		t := result.Result.Timings
+		if t == nil || scale == 0 {
+			output.WriteString(fmt.Sprintf("No timings, probe %s\n\n", result.Result.Status))
+			continue
+		}
+
+		total := waterfallTotal(t)
+		longest := ""
+		max := -1.0
+		for _, p := range waterfallPhases {
+			if v := p.value(t); v != nil && *v > max {
+				longest, max = p.label, *v
+			}
+		}
+
+		start := 0.0
+		for _, p := range waterfallPhases {
+			v := p.value(t)
+			if v == nil {
+				continue
+			}
+			bar := waterfallBar(start, *v, scale)
+			if p.label == longest {
+				bar = bad.Render(bar)
+			} else {
+				bar = good.Render(bar)
+			}
+			share := 0.0
+			if total > 0 {
+				share = *v / total * 100
+			}
+			output.WriteString(fmt.Sprintf("%-10s |%s| %6.0f ms %3.0f%%\n", p.label, bar, *v, share))
+			start += *v
+		}
+		output.WriteString(fmt.Sprintf("%-10s  %s  %6.0f ms\n\n", "Total", strings.Repeat(" ", waterfallWidth), total))
+	}
+
+	return strings.TrimRight(output.String(), "\n"), nil
+}
@@ -0,0 +1,50 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatWaterfall(t *testing.T) {
+	data := model.GetMeasurement{Type: "http", Results: []model.MeasurementResponse{
+		{
+			Probe:  model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+			Result: model.ResultData{Status: "finished", Timings: &model.Timings{Total: ms(200), DNS: ms(20), TCP: ms(20), TLS: ms(40), FirstByte: ms(100), Download: ms(20)}},
+		},
+		{
+			Probe:  model.ProbeData{Continent: "AS", Country: "JP", City: "Tokyo", ASN: 2, Network: "Network"},
+			Result: model.ResultData{Status: "finished", Timings: &model.Timings{Total: ms(400), DNS: ms(300), TCP: ms(40), FirstByte: ms(50), Download: ms(10)}},
+		},
+		{
+			Probe:  model.ProbeData{Continent: "NA", Country: "US", City: "Miami", ASN: 3, Network: "Network"},
+			Result: model.ResultData{Status: "failed"},
+		},
+	}}
+
+	output, err := client.FormatWaterfall(data, model.Context{CI: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `> EU, DE, Berlin, ASN:1, Network
+DNS        |██                                      |     20 ms  10%
+TCP        |  ██                                    |     20 ms  10%
+TLS        |    ████                                |     40 ms  20%
+First byte |        ██████████                      |    100 ms  50%
+Download   |                  ██                    |     20 ms  10%
+Total                                                    200 ms
+
+> AS, JP, Tokyo, ASN:2, Network
+DNS        |██████████████████████████████          |    300 ms  75%
+TCP        |                              ████      |     40 ms  10%
+First byte |                                  █████ |     50 ms  12%
+Download   |                                       █|     10 ms   2%
+Total                                                    400 ms
+
+> NA, US, Miami, ASN:3, Network
+No timings, probe failed`, output)
+
+	_, err = client.FormatWaterfall(model.GetMeasurement{Type: "ping"}, model.Context{})
+	assert.EqualError(t, err, "err: the waterfall format is not supported for ping measurements")
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// WebhookPayload is the JSON posted to --webhook when a measurement finishes, the text field makes it readable by
+// Slack-compatible endpoints
+type WebhookPayload struct {
+	Text       string      `json:"text"`
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Target     string      `json:"target"`
+	From       string      `json:"from"`
+	ShareUrl   string      `json:"shareUrl"`
+	Failed     bool        `json:"failed"`
+	Violations []string    `json:"violations,omitempty"`
+	Results    []LogRecord `json:"results"`
+}
+
+// MeasurementFailed returns true if a threshold is breached or a probe did not finish, unless the --fail-on-probe-errors
+// policy tolerates it
+func MeasurementFailed(data model.GetMeasurement, violations []Violation, policy string) bool {
+	if len(violations) > 0 {
+		return true
+	}
+	if probeErrorsTolerated(data, policy) {
+		return false
+	}
+	for _, result := range data.Results {
+		if result.Result.Status != "finished" {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWebhookPayload builds the webhook payload of a finished measurement
+func NewWebhookPayload(data model.GetMeasurement, ctx model.Context, violations []Violation, now time.Time) WebhookPayload {
+	cmd := measurementType(data, ctx)
+
+	p := WebhookPayload{
+		ID:       data.ID,
+		Type:     cmd,
+		Target:   ctx.Target,
+		From:     ctx.From,
+		ShareUrl: ShareUrl(data.ID),
+		Failed:   MeasurementFailed(data, violations, ctx.Thresholds.FailOnProbeErrors),
+		Results:  LogRecords(data, ctx, now),
+	}
+	for _, v := range violations {
+		p.Violations = append(p.Violations, v.String())
+	}
+
+	status := "passed"
+	if p.Failed {
+		status = "failed"
+	}
+	p.Text = fmt.Sprintf("globalping %s %s from %s %s on %d probes: %s", cmd, ctx.Target, ctx.From, status, len(data.Results), p.ShareUrl)
+	return p
+}
+
+// PostWebhook posts a JSON payload to a webhook URL
+func PostWebhook(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.New("err: failed to marshal the webhook payload - please report this bug")
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("err: invalid webhook url")
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := httpClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.New("err: failed to call the webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("err: the webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
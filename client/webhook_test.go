@@ -0,0 +1,41 @@
+package client_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook(t *testing.T) {
+	failed := pingResult("Munich", 0)
+	failed.Result.Status = "failed"
+	data := model.GetMeasurement{ID: "abcd", Type: "ping", Results: []model.MeasurementResponse{pingResult("Berlin", 10), failed}}
+	violations := []client.Violation{{Probe: "Munich, DE, ASN:1", Reason: "probe status is failed"}}
+
+	assert.False(t, client.MeasurementFailed(model.GetMeasurement{Results: []model.MeasurementResponse{pingResult("Berlin", 10)}}, nil, ""))
+	assert.True(t, client.MeasurementFailed(data, nil, ""))
+	assert.False(t, client.MeasurementFailed(data, nil, client.FailPolicyAll))
+	assert.False(t, client.MeasurementFailed(data, nil, client.FailPolicyNone))
+	assert.True(t, client.MeasurementFailed(model.GetMeasurement{Results: []model.MeasurementResponse{failed}}, nil, client.FailPolicyAll))
+
+	var received client.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	payload := client.NewWebhookPayload(data, model.Context{Target: "google.com", From: "Germany"}, violations, time.Now())
+	assert.NoError(t, client.PostWebhook(server.URL, payload))
+	assert.Equal(t, "globalping ping google.com from Germany failed on 2 probes: https://globalping.io?measurement=abcd", received.Text)
+	assert.True(t, received.Failed)
+	assert.Equal(t, []string{"Munich, DE, ASN:1: probe status is failed"}, received.Violations)
+	assert.Len(t, received.Results, 2)
+}
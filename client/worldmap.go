@@ -0,0 +1,130 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Continents of the map grid, placed roughly where they are on a world map
+var mapGrid = [][]string{
+	{"NA", "EU", "AS"},
+	{"SA", "AF", "OC"},
+}
+
+const mapCellWidth = 30
+
+var mapCell = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#666666")).Padding(0, 1)
+
+// Latencies and failures of the probes in one continent or region
+type mapArea struct {
+	name     string
+	values   []float64
+	probes   int
+	failures int
+}
+
+func (a *mapArea) add(cmd string, result model.MeasurementResponse) {
+	a.probes++
+	if result.Result.Status != "finished" {
+		a.failures++
+		return
+	}
+	if v, ok := KeyMetric(cmd, result); ok {
+		a.values = append(a.values, v)
+	}
+}
+
+// Median latency of the area, colored by severity, falling back to the probe count for types without a latency
+func (a *mapArea) latency() string {
+	if len(a.values) == 0 {
+		if a.failures == a.probes {
+			return failed.Render("failed")
+		}
+		return "-"
+	}
+	sorted := append([]float64{}, a.values...)
+	sort.Float64s(sorted)
+	return colorLatency(Percentile(sorted, 50), "%.1f ms")
+}
+
+// Cell of one continent: its median latency and probe count, then one line per region
+func (a *mapArea) render(regions map[string]*mapArea) string {
+	if a.probes == 0 {
+		return mapCell.Width(mapCellWidth).Render(a.name + "\n" + dimmed.Render("no probes"))
+	}
+
+	probes := fmt.Sprintf("%d probes", a.probes)
+	if a.probes == 1 {
+		probes = "1 probe"
+	}
+	if a.failures > 0 {
+		probes += fmt.Sprintf(", %s", failed.Render(fmt.Sprintf("%d failed", a.failures)))
+	}
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render(a.name) + "  " + a.latency(),
+		dimmed.Render(probes),
+	}
+
+	names := make([]string, 0, len(regions))
+	for name := range regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		latency := regions[name].latency()
+		// Long region names are cut to keep the latency on the same line
+		label := truncate(name, mapCellWidth-3-lipgloss.Width(latency))
+		lines = append(lines, label+strings.Repeat(" ", mapCellWidth-2-lipgloss.Width(label)-lipgloss.Width(latency))+latency)
+	}
+	return mapCell.Width(mapCellWidth).Render(strings.Join(lines, "\n"))
+}
+
+// LatencyMap renders a grid of the continents laid out like a world map, with the median latency of the probes in
+// every continent and region colored by severity. Measurements without a single latency, e.g. traceroute, only show
+// the number of probes.
+func LatencyMap(cmd string, data model.GetMeasurement) string {
+	continents := map[string]*mapArea{}
+	regions := map[string]map[string]*mapArea{}
+	for code, name := range Continents {
+		continents[code] = &mapArea{name: name}
+		regions[code] = map[string]*mapArea{}
+	}
+
+	for _, result := range data.Results {
+		code := result.Probe.Continent
+		c, ok := continents[code]
+		if !ok {
+			continue
+		}
+		c.add(cmd, result)
+		if result.Probe.Region != "" {
+			r, ok := regions[code][result.Probe.Region]
+			if !ok {
+				r = &mapArea{name: result.Probe.Region}
+				regions[code][result.Probe.Region] = r
+			}
+			r.add(cmd, result)
+		}
+	}
+
+	var rows []string
+	for _, row := range mapGrid {
+		cells := make([]string, len(row))
+		for i, code := range row {
+			cells[i] = continents[code].render(regions[code])
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+	// Antarctica only has a cell when a probe is there
+	if continents["AN"].probes > 0 {
+		rows = append(rows, continents["AN"].render(regions["AN"]))
+	}
+
+	legend := fmt.Sprintf("%s < %.0f ms  %s < %.0f ms  %s >= %.0f ms",
+		good.Render("■"), LatencyGood, warn.Render("■"), LatencyWarn, bad.Render("■"), LatencyWarn)
+	return lipgloss.JoinVertical(lipgloss.Left, rows...) + "\n" + dimmed.Render("median latency per area  ") + legend
+}
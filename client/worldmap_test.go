@@ -0,0 +1,68 @@
+package client_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ansi = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func mapResult(continent, region string, avg float64) model.MeasurementResponse {
+	r := pingResult("City", avg)
+	r.Probe.Continent = continent
+	r.Probe.Region = region
+	return r
+}
+
+func TestLatencyMap(t *testing.T) {
+	timedOut := mapResult("AS", "Eastern Asia", 0)
+	timedOut.Result.Status = "failed"
+	data := model.GetMeasurement{Results: []model.MeasurementResponse{
+		mapResult("EU", "Western Europe", 10),
+		mapResult("EU", "Western Europe", 30),
+		mapResult("EU", "Northern Europe", 20),
+		mapResult("NA", "Northern America", 95),
+		timedOut,
+	}}
+
+	out := ansi.ReplaceAllString(client.LatencyMap("ping", data), "")
+	lines := strings.Split(out, "\n")
+
+	// Europe is between North America and Asia on the first row, the second row follows below
+	assert.Contains(t, lines[1], "North America  95.0 ms")
+	assert.Contains(t, lines[1], "Europe  20.0 ms")
+	assert.Less(t, strings.Index(lines[1], "North America"), strings.Index(lines[1], "Europe"))
+	assert.Less(t, strings.Index(lines[1], "Europe"), strings.Index(lines[1], "Asia"))
+	assert.Contains(t, lines[2], "3 probes")
+	assert.Contains(t, out, "Northern Europe      20.0 ms")
+	assert.Contains(t, out, "Western Europe       20.0 ms")
+	assert.Contains(t, out, "Asia  failed")
+	assert.Contains(t, out, "1 probe, 1 failed")
+	assert.Contains(t, out, "South America")
+	assert.Contains(t, out, "no probes")
+	assert.NotContains(t, out, "Antarctica")
+
+	// Long region names are cut instead of wrapping the latency
+	out = ansi.ReplaceAllString(client.LatencyMap("ping", model.GetMeasurement{Results: []model.MeasurementResponse{
+		mapResult("OC", "Australia and New Zealand", 250),
+	}}), "")
+	assert.Contains(t, out, "Australia and New Z 250.0 ms")
+	assert.Contains(t, lines[len(lines)-1], "< 50 ms")
+}
+
+func TestLatencyMapWithoutLatency(t *testing.T) {
+	result := model.MeasurementResponse{
+		Probe:  model.ProbeData{Continent: "EU", Region: "Western Europe"},
+		Result: model.ResultData{Status: "finished"},
+	}
+
+	out := ansi.ReplaceAllString(client.LatencyMap("traceroute", model.GetMeasurement{Results: []model.MeasurementResponse{result}}), "")
+	assert.Contains(t, out, "Europe  -")
+	assert.Contains(t, out, "1 probe")
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	anycastType       string
+	anycastMaxLatency time.Duration
+)
+
+// anycastCmd represents the anycast command
+var anycastCmd = &cobra.Command{
+	Use:   "anycast [target] from [location]",
+	Short: "Check which addresses and sites of an anycast target every region reaches",
+	Long: `The anycast command pings the target, or resolves it with --type dns, from --limit probes on every continent unless locations are given. It reports the address every probe reached with its latency, how many distinct addresses were seen in every region, and flags the probes routed to a distant site: an anycast address is expected to be served close to every probe, a latency above --max-latency means the traffic crosses a long way. The exit code is 1 if any probe is flagged.
+
+Examples:
+  # Ping 1.1.1.1 from 2 probes on every continent
+  anycast 1.1.1.1 --limit 2
+
+  # Check the sites of jsdelivr.com reached from 10 probes in Europe, flagging the ones above 30 ms
+  anycast jsdelivr.com from Europe --limit 10 --max-latency 30ms
+
+  # Compare the addresses cdn.jsdelivr.net resolves to on every continent
+  anycast cdn.jsdelivr.net --type dns --limit 3`,
+	Args: checkCommandFormat(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := createContext(cmd.CalledAs(), args)
+		if err != nil {
+			return err
+		}
+		if anycastType != "ping" && anycastType != "dns" {
+			return fmt.Errorf("the type must be one of %s", strings.Join(client.AnycastTypes, ", "))
+		}
+
+		m, err := buildCompareMeasurement(anycastType, ctx.Target)
+		if err != nil {
+			return err
+		}
+		if ctx.From == "world" {
+			// --limit probes on every continent
+			m.Locations = make([]model.Locations, len(client.PropagationContinents))
+			for i, c := range client.PropagationContinents {
+				m.Locations[i] = model.Locations{Continent: c, Limit: ctx.Limit}
+			}
+			m.Limit = ctx.Limit * len(client.PropagationContinents)
+		}
+		if dryRun {
+			return dryRunMeasurements([]model.PostMeasurement{m})
+		}
+
+		res, err := client.PostAPI(runCtx, m)
+		if err != nil {
+			return postError(err)
+		}
+		recordHistory(res.ID, m.Type, ctx.Target)
+
+		data, err := client.WaitForResults(runCtx, res.ID)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		output, distant := client.FormatAnycast(m.Type, data, anycastMaxLatency)
+		if ctx.JsonOutput {
+			client.OutputJson(runCtx, res.ID)
+		} else {
+			fmt.Println(output)
+		}
+		if distant > 0 {
+			exitCode = 1
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(anycastCmd)
+	anycastCmd.Flags().StringVar(&anycastType, "type", "ping", "Measurement used to reach the target, ping or dns")
+	anycastCmd.Flags().DurationVar(&anycastMaxLatency, "max-latency", 50*time.Millisecond, "Latency above which a probe is flagged as routed to a distant site, 0 disables the check")
+}
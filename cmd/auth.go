@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/auth"
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var loginToken string
+
+// authCmd represents the auth command group
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage the Globalping API token",
+	Long: `The auth commands store the API token of your Globalping account, which is then sent with every request to get higher rate limits.
+The token can also be provided with the GLOBALPING_TOKEN environment variable, which takes precedence over the stored token.`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Store an API token",
+	Long: `Store an API token in ~/.globalping/token, readable only by the current user.
+
+Examples:
+  # Store a token passed as a flag
+  auth login --token <token>
+
+  # Read the token from stdin
+  echo <token> | auth login`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := loginToken
+		if token == "" {
+			fmt.Print("Enter your API token: ")
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return errors.New("failed to read token")
+			}
+			token = strings.TrimSpace(line)
+		}
+
+		err := auth.Save(token)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Token saved to " + auth.Path)
+		return nil
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored API token",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := auth.Remove()
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		fmt.Println("Logged out")
+		return nil
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which API token is used",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, source, err := auth.Token()
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		switch source {
+		case auth.SourceEnv:
+			fmt.Printf("Authenticated with token %s from the %s environment variable\n", auth.Mask(token), auth.EnvToken)
+		case auth.SourceFile:
+			fmt.Printf("Authenticated with token %s stored in %s\n", auth.Mask(token), auth.Path)
+		default:
+			fmt.Println("Not authenticated, requests are made anonymously")
+		}
+		return nil
+	},
+}
+
+// loadToken sets the API token used by the client, an unreadable token file falls back to anonymous requests
+func loadToken() {
+	token, _, err := auth.Token()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	client.ApiToken = token
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authStatusCmd)
+
+	authLoginCmd.Flags().StringVar(&loginToken, "token", "", "The API token to store (read from stdin if empty)")
+
+	cobra.OnInitialize(loadToken)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/baseline"
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+var (
+	baselineAction    string
+	baselineName      string
+	baselineTolerance client.Tolerance
+)
+
+// baselineValue backs the --baseline flag: the action and the name of the baseline, e.g. save:prod or compare:prod
+type baselineValue struct{}
+
+func (b *baselineValue) String() string {
+	if baselineAction == "" {
+		return ""
+	}
+	return baselineAction + ":" + baselineName
+}
+
+func (b *baselineValue) Set(v string) error {
+	action, name, ok := strings.Cut(v, ":")
+	if !ok || name == "" || (action != "save" && action != "compare") {
+		return fmt.Errorf("invalid baseline %q, use save:<name> or compare:<name>", v)
+	}
+	baselineAction, baselineName = action, name
+	return nil
+}
+
+func (b *baselineValue) Type() string {
+	return "string"
+}
+
+// baselineResults saves a finished measurement as the baseline selected with --baseline save:<name>, or compares it
+// with the baseline selected with --baseline compare:<name> and sets a failing exit code on regressions
+func baselineResults(measurementType string, data model.GetMeasurement) {
+	quiet := ctx.JsonOutput || ctx.Format != "" || ctx.Quiet
+
+	switch baselineAction {
+	case "save":
+		err := baseline.Save(baselineName, data)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitCode = 1
+			return
+		}
+		if !quiet {
+			fmt.Printf("Saved baseline %s\n", baselineName)
+		}
+	case "compare":
+		base, err := baseline.Load(baselineName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitCode = 1
+			return
+		}
+		if base.Type != measurementType {
+			fmt.Fprintf(os.Stderr, "err: baseline %s is a %s measurement, not %s\n", baselineName, base.Type, measurementType)
+			exitCode = 1
+			return
+		}
+
+		violations := client.CompareBaseline(measurementType, base, data, baselineTolerance)
+		for _, v := range violations {
+			fmt.Fprintln(os.Stderr, "baseline regression: "+v.String())
+		}
+		if len(violations) > 0 {
+			exitCode = 1
+		} else if !quiet {
+			fmt.Printf("No regression against baseline %s\n", baselineName)
+		}
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().Var(&baselineValue{}, "baseline", "Save the results as a named baseline with save:<name>, or compare them with a baseline with compare:<name> and fail on regressions")
+	rootCmd.PersistentFlags().Float64Var(&baselineTolerance.Latency, "tolerance", 10, "Latency increase allowed by --baseline compare, in percent of the baseline latency of every probe")
+	rootCmd.PersistentFlags().Float64Var(&baselineTolerance.Loss, "loss-tolerance", 0, "Packet loss increase allowed by --baseline compare, in percentage points")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/baseline"
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineFlag(t *testing.T) {
+	t.Cleanup(func() { baselineAction, baselineName = "", "" })
+
+	v := &baselineValue{}
+	assert.NoError(t, v.Set("save:prod"))
+	assert.Equal(t, "save", baselineAction)
+	assert.Equal(t, "prod", baselineName)
+	assert.Equal(t, "save:prod", v.String())
+
+	assert.NoError(t, v.Set("compare:prod"))
+	assert.Equal(t, "compare", baselineAction)
+
+	for _, value := range []string{"prod", "save:", "load:prod"} {
+		assert.EqualError(t, v.Set(value), `invalid baseline "`+value+`", use save:<name> or compare:<name>`)
+	}
+}
+
+func TestBaselineResults(t *testing.T) {
+	dir := baseline.Dir
+	baseline.Dir = filepath.Join(t.TempDir(), "baselines")
+	t.Cleanup(func() {
+		baseline.Dir = dir
+		baselineAction, baselineName, baselineTolerance = "", "", client.Tolerance{}
+		ctx = model.Context{}
+		exitCode = 0
+	})
+	ctx.Quiet = true
+
+	result := func(avg float64) model.GetMeasurement {
+		return model.GetMeasurement{ID: "abcd", Type: "ping", Results: []model.MeasurementResponse{{
+			Probe:  model.ProbeData{City: "Berlin", Country: "DE", ASN: 3320},
+			Result: model.ResultData{Status: "finished", Stats: &model.PingStats{Avg: &avg}},
+		}}}
+	}
+
+	baselineAction, baselineName = "save", "prod"
+	baselineResults("ping", result(10))
+	assert.Equal(t, 0, exitCode)
+
+	baselineAction, baselineTolerance.Latency = "compare", 10
+	baselineResults("ping", result(10.5))
+	assert.Equal(t, 0, exitCode)
+
+	baselineResults("ping", result(20))
+	assert.Equal(t, 1, exitCode)
+
+	exitCode = 0
+	baselineResults("http", result(10))
+	assert.Equal(t, 1, exitCode)
+}
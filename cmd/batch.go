@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/manifest"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// batchCmd represents the batch command
+var batchCmd = &cobra.Command{
+	Use:   "batch [file]",
+	Short: "Run the measurements of a manifest file and report which ones passed",
+	Long: `The batch command runs every measurement of a YAML manifest, at most --parallel at the same time, and prints a combined report. A measurement passes when it finished on every probe within its thresholds, the exit code is 1 if any failed.
+Measurements can mix types, targets and locations, every one is recorded in the history:
+
+  measurements:
+    - name: cdn
+      type: http
+      target: cdn.jsdelivr.net
+      from: Europe, North America
+      limit: 10
+      path: /npm/react
+      thresholds:
+        max-latency: 500ms
+        expect-status: 200
+    - type: ping
+      target: jsdelivr.com
+      from: Germany:2, Japan:2
+      packets: 5
+      thresholds:
+        max-loss: 5
+    - type: dns
+      target: jsdelivr.com
+      query-type: AAAA
+      resolver: 1.1.1.1
+
+The name defaults to the type and the target, the location to world and the limit to 1.
+
+Examples:
+  # Run the measurements of checks.yml
+  batch checks.yml
+
+  # Run them 10 at a time and print the report in JSON
+  batch checks.yml --parallel 10 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := manifest.Load(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		measurements := make([]model.PostMeasurement, len(f.Measurements))
+		for i, e := range f.Measurements {
+			measurements[i] = e.PostMeasurement()
+		}
+		if dryRun {
+			return dryRunMeasurements(measurements)
+		}
+
+		// Failed measurements are reported with their name below
+		results, _ := newRunner().Run(runCtx, measurements)
+
+		report := make([]client.ManifestResult, len(results))
+		failed := false
+		for i, r := range results {
+			e := f.Measurements[i]
+			if r.ID != "" {
+				err = history.Add(history.Entry{ID: r.ID, Type: e.Type, Target: e.Target, From: e.From, CreatedAt: time.Now().UTC()})
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+				}
+			}
+			report[i] = client.NewManifestResult(e.Name, e.Context(), r)
+			if !report[i].Passed {
+				failed = true
+			}
+		}
+
+		if failed {
+			exitCode = 1
+		}
+
+		if ctx.JsonOutput {
+			b, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+		fmt.Println(client.ManifestReport(report))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+}
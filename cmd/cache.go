@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jsdelivr/globalping-cli/cache"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command group
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of finished measurements",
+	Long: `Finished measurements are cached in ~/.globalping/cache, so showing or formatting them again does not call the API. They are kept for the cache-ttl of the config file (default 168h), --no-cache always fetches them from the API.
+
+Examples:
+  # Remove every cached measurement
+  cache clear`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached measurement",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := cache.Clear()
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		fmt.Printf("Removed %d cached measurements\n", n)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
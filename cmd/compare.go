@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare [type] [target a] [target b]",
+	Short: "Run the same measurement against two targets and compare the results",
+	Long: `The compare command runs the same measurement against two targets from the same probes and prints the latency of each probe side by side with the delta.
+Supported types are ping, traceroute, dns, mtr and http, latency is compared for ping, dns and http.
+
+Examples:
+  # Compare the ping latency of two hosts from 5 probes in Europe
+  compare ping a.example.com b.example.com --from Europe --limit 5
+
+  # Compare the HTTP response time of two CDNs
+  compare http cdn.jsdelivr.net/npm/react fastly.jsdelivr.net/npm/react --from "North America"`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if ctx.From == "" {
+			ctx.From = "world"
+		}
+		if err := expandFrom(); err != nil {
+			return err
+		}
+
+		build := func(target string) (model.PostMeasurement, error) {
+			return withValidLimit(withLocationLimits(func() (model.PostMeasurement, error) {
+				return buildCompareMeasurement(args[0], target)
+			}))()
+		}
+		a, err := build(args[1])
+		if err != nil {
+			return err
+		}
+		b, err := build(args[2])
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return dryRunMeasurements([]model.PostMeasurement{a, b})
+		}
+
+		// Run the second measurement from exactly the same probes as the first one
+		r := newRunner()
+		r.SameProbes = true
+		results, err := r.Run(runCtx, []model.PostMeasurement{a, b})
+		if results == nil {
+			return postError(err)
+		}
+		for i, m := range []model.PostMeasurement{a, b} {
+			if results[i].ID != "" {
+				recordHistory(results[i].ID, m.Type, m.Target)
+			}
+		}
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		dataA, dataB := results[0].Data, results[1].Data
+
+		fmt.Println(client.CompareResults(args[0], dataA, dataB, args[1], args[2]))
+		return nil
+	},
+}
+
+// buildCompareMeasurement builds a measurement with default options for one of the compared targets
+func buildCompareMeasurement(measurementType, target string) (model.PostMeasurement, error) {
+	switch measurementType {
+	case "ping", "traceroute", "mtr":
+		v, err := ipVersion(target)
+		if err != nil {
+			return model.PostMeasurement{}, err
+		}
+		m := model.PostMeasurement{
+			Type:      measurementType,
+			Target:    target,
+			Locations: createLocations(ctx.From),
+			Limit:     ctx.Limit,
+		}
+		if v != 0 {
+			m.Options = &model.MeasurementOptions{IPVersion: v}
+		}
+		return m, nil
+	case "dns":
+		return model.PostMeasurement{
+			Type:      measurementType,
+			Target:    target,
+			Locations: createLocations(ctx.From),
+			Limit:     ctx.Limit,
+		}, nil
+	case PostMeasurementTypeHttp:
+		ctx.Target = target
+		return buildHttpMeasurementRequest()
+	}
+	return model.PostMeasurement{}, fmt.Errorf("unsupported measurement type: %s", measurementType)
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+}
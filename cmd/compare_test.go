@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCompareMeasurement(t *testing.T) {
+	ctx = model.Context{From: "Europe", Limit: 3}
+
+	m, err := buildCompareMeasurement("ping", "a.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, model.PostMeasurement{
+		Type:      "ping",
+		Target:    "a.example.com",
+		Locations: []model.Locations{{Magic: "Europe"}},
+		Limit:     3,
+	}, m)
+
+	m, err = buildCompareMeasurement("http", "https://b.example.com/path")
+	assert.NoError(t, err)
+	assert.Equal(t, "b.example.com", m.Target)
+	assert.Equal(t, "/path", m.Options.Request.Path)
+	assert.Equal(t, "https", m.Options.Protocol)
+
+	_, err = buildCompareMeasurement("whois", "a.example.com")
+	assert.EqualError(t, err, "unsupported measurement type: whois")
+}
+
+func TestBuildDiagnoseMeasurements(t *testing.T) {
+	ctx = model.Context{From: "Europe", Limit: 2}
+	t.Cleanup(func() { ctx = model.Context{} })
+
+	measurements, err := buildDiagnoseMeasurements("https://cdn.jsdelivr.net/npm/react")
+	assert.NoError(t, err)
+	assert.Len(t, measurements, 4)
+	for i, typ := range []string{"ping", "traceroute", "dns", "http"} {
+		assert.Equal(t, typ, measurements[i].Type)
+		assert.Equal(t, "cdn.jsdelivr.net", measurements[i].Target)
+	}
+	assert.Equal(t, "/npm/react", measurements[3].Options.Request.Path)
+}
+
+func TestCompareExpandsLocations(t *testing.T) {
+	t.Cleanup(func() {
+		ctx = model.Context{}
+		locationAliases = nil
+	})
+	locationAliases = map[string]string{"edge-pops": "Frankfurt:2,Tokyo:2"}
+	ctx = model.Context{From: "@edge-pops", Limit: 1}
+
+	assert.NoError(t, expandFrom())
+	m, err := buildCompareMeasurement("ping", "a.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Locations{{Magic: "Frankfurt", Limit: 2}, {Magic: "Tokyo", Limit: 2}}, m.Locations)
+
+	ctx.From = "@missing"
+	assert.Error(t, expandFrom())
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate the autocompletion script for the specified shell",
+	Long: `The completion command generates the autocompletion script of the CLI for bash, zsh, fish or powershell.
+Locations given with --from or after "from" are completed with continents, regions, countries and the cities, networks and tags of the online probes.
+
+Examples:
+  # Load completions in the current bash session
+  source <(globalping completion bash)
+
+  # Load completions for every zsh session
+  globalping completion zsh > "${fpath[1]}/_globalping"
+
+  # Load completions in fish
+  globalping completion fish | source
+
+  # Load completions in powershell
+  globalping completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.ExactValidArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
+
+// Maximum time spent fetching the online probes while completing a location
+const completionTimeout = 2 * time.Second
+
+// completeLocations completes the last location of a comma separated list, the static keywords are used if the probes
+// cannot be fetched in time
+func completeLocations(toComplete string) ([]string, cobra.ShellCompDirective) {
+	c, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	probes, _ := client.GetProbes(c)
+
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, toComplete = toComplete[:i+1], toComplete[i+1:]
+	}
+	current := strings.ToLower(strings.TrimSpace(toComplete))
+
+	var matches []string
+	for _, k := range client.LocationKeywords(probes) {
+		if strings.HasPrefix(strings.ToLower(k), current) {
+			matches = append(matches, prefix+k)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFromFlag completes the locations of --from
+func completeFromFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeLocations(toComplete)
+}
+
+// completeMeasurementArgs completes the location following "from", targets are not completed
+func completeMeasurementArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 && args[len(args)-1] == "from" {
+		return completeLocations(toComplete)
+	}
+	if len(args) > 0 && strings.HasPrefix("from", toComplete) {
+		return []string{"from"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	for _, c := range []*cobra.Command{pingCmd, tracerouteCmd, dnsCmd, mtrCmd, httpCmd, tlsCmd} {
+		c.ValidArgsFunction = completeMeasurementArgs
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/spf13/cobra"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteLocations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"location":{"continent":"EU","country":"DE","city":"Frankfurt","asn":3320,"network":"Deutsche Telekom AG"}}]`))
+	}))
+	defer server.Close()
+	probesUrl := client.ProbesApiUrl
+	client.ProbesApiUrl = server.URL
+	t.Cleanup(func() { client.ProbesApiUrl = probesUrl })
+
+	matches, directive := completeMeasurementArgs(pingCmd, []string{"google.com", "from"}, "fra")
+	assert.Equal(t, []string{"France", "Frankfurt"}, matches)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	matches, _ = completeFromFlag(pingCmd, nil, "Germany,Deu")
+	assert.Equal(t, []string{"Germany,Deutsche Telekom AG"}, matches)
+
+	matches, _ = completeMeasurementArgs(pingCmd, []string{"google.com"}, "fr")
+	assert.Equal(t, []string{"from"}, matches)
+
+	matches, _ = completeMeasurementArgs(pingCmd, nil, "fr")
+	assert.Empty(t, matches)
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/auth"
+	"github.com/jsdelivr/globalping-cli/cache"
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/config"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command group
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the persistent CLI defaults",
+	Long: `The config commands manage the defaults stored in ~/.globalping/config.yml. Flags and arguments always take precedence over the config file.
+
+Supported keys:
+  from      Default location, e.g. "Europe" (default "world")
+  limit     Default number of probes
+  format    Default output format: json, latency, ci or a --format value
+  api-url   Base URL of the Globalping API (default "https://api.globalping.io/v1")
+  timeout   Timeout of every API request, e.g. 30s
+  cache-ttl Duration finished measurements are cached on disk, e.g. 24h (default 168h)
+  influxdb-url, influxdb-org, influxdb-bucket, influxdb-token
+            InfluxDB v2 settings used by export and --export influxdb
+  locations.<name>
+            Named set of locations used as --from @<name>
+  profiles.<name>.<key>
+            Measurement profile run with: globalping run <name> <target>, the type key selects the measurement
+            and every other key is one of its flags, e.g. profiles.cdn-check.limit
+  environments.<name>.url, environments.<name>.token
+            Named Globalping API selected with --env <name>, e.g. a staging or self-hosted API, its token replaces
+            the stored token
+  env       Environment used when no --env flag is given
+  fail-on-probe-errors
+            Exit code policy of failed probes: any fails if one probe fails, all only if every probe fails,
+            none never
+
+Tokens are masked by config list, config get prints them in clear text.
+
+Examples:
+  # Run measurements from Europe by default
+  config set from Europe
+
+  # Output results in JSON by default
+  config set format json
+
+  # Exit with a non-zero code only when every probe fails
+  config set fail-on-probe-errors all
+
+  # Unset the default limit
+  config set limit ""
+
+  # Define a set of locations and use it with: ping google.com from @edge-pops
+  config set locations.edge-pops "aws-eu-west-1,aws-us-east-1,gcp-asia"
+
+  # Define a profile and run it with: run cdn-check cdn.jsdelivr.net
+  config set profiles.cdn-check.type http
+  config set profiles.cdn-check.expect-status 200
+
+  # Define a staging API and measure with it: ping google.com --env staging
+  config set environments.staging.url https://api.staging.example.com/v1
+  config set environments.staging.token <token>`,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Set a config value, an empty value unsets it",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := config.Load()
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		err = c.Set(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		err = c.Save()
+		if err != nil {
+			fmt.Println(err)
+		}
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print a config value",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := config.Load()
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		v, err := c.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(v)
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print every config value, tokens are masked",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := config.Load()
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		return listConfig(os.Stdout, c)
+	},
+}
+
+// listConfig prints every config value, tokens are masked and only printed in clear text by config get
+func listConfig(out io.Writer, c *config.Config) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, k := range config.Keys() {
+		v, _ := c.Get(k)
+		if strings.HasSuffix(k, "token") {
+			v = auth.Mask(v)
+		}
+		fmt.Fprintf(w, "%s\t%s\n", k, v)
+	}
+	for _, name := range sortedKeys(c.Locations) {
+		fmt.Fprintf(w, "locations.%s\t%s\n", name, c.Locations[name])
+	}
+	for _, name := range profileNames(c) {
+		for _, k := range sortedKeys(c.Profiles[name]) {
+			fmt.Fprintf(w, "profiles.%s.%s\t%s\n", name, k, c.Profiles[name][k])
+		}
+	}
+	for _, name := range environmentNames(c) {
+		e := c.Environments[name]
+		fmt.Fprintf(w, "environments.%s.url\t%s\n", name, e.Url)
+		if e.Token != "" {
+			fmt.Fprintf(w, "environments.%s.token\t%s\n", name, auth.Mask(e.Token))
+		}
+	}
+	return w.Flush()
+}
+
+// applyConfig sets the defaults from the config file for every flag that was not explicitly set, it only fails when
+// the environment selected with --env cannot be used
+func applyConfig(cmd *cobra.Command) error {
+	c, err := config.Load()
+	if err != nil {
+		if apiEnv != "" {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, err)
+		return nil
+	}
+
+	changed := func(name string) bool {
+		f := cmd.Flag(name)
+		return f == nil || f.Changed
+	}
+
+	locationAliases = c.Locations
+
+	if c.From != "" && ctx.From == "" {
+		ctx.From = c.From
+	}
+	if c.Limit > 0 && !changed("limit") {
+		ctx.Limit = c.Limit
+	}
+
+	switch c.Format {
+	case "json":
+		if !changed("json") {
+			ctx.JsonOutput = true
+		}
+	case "latency":
+		if !changed("latency") {
+			ctx.Latency = true
+		}
+	case "ci":
+		if !changed("ci") {
+			ctx.CI = true
+		}
+	default:
+		if c.Format != "" && !changed("format") {
+			ctx.Format = c.Format
+		}
+	}
+
+	if c.FailOnProbeErrors != "" && !changed("fail-on-probe-errors") {
+		ctx.Thresholds.FailOnProbeErrors = c.FailOnProbeErrors
+	}
+
+	if c.ApiUrl != "" {
+		client.SetBaseUrl(c.ApiUrl)
+	}
+	if d := c.TimeoutDuration(); d > 0 && !changed("timeout") {
+		client.Timeout = d
+	}
+	if d := c.CacheTTLDuration(); d > 0 {
+		cache.TTL = d
+	}
+
+	name := apiEnv
+	if name == "" {
+		name = c.Env
+	}
+	if name != "" {
+		e, err := c.Environment(name)
+		if err != nil {
+			return err
+		}
+		client.SetBaseUrl(e.Url)
+		client.ApiToken = e.Token
+	}
+	return nil
+}
+
+// environmentNames returns the names of the environments in alphabetical order
+func environmentNames(c *config.Config) []string {
+	names := make([]string, 0, len(c.Environments))
+	for name := range c.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeEnvFlag completes --env with the environments of the config file
+func completeEnvFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	c, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return environmentNames(c), cobra.ShellCompDirectiveNoFileComp
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys(m map[string]string) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configListCmd)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/config"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyConfig(t *testing.T) {
+	config.Path = filepath.Join(t.TempDir(), "config.yml")
+	c := &config.Config{From: "Europe", Limit: 5, Format: "json"}
+	assert.NoError(t, c.Save())
+
+	ctx = model.Context{Limit: 1}
+	applyConfig(pingCmd)
+	assert.Equal(t, "Europe", ctx.From)
+	assert.Equal(t, 5, ctx.Limit)
+	assert.True(t, ctx.JsonOutput)
+
+	// Explicit flags take precedence over the config file
+	ctx = model.Context{From: "Asia"}
+	limitFlag := rootCmd.PersistentFlags().Lookup("limit")
+	assert.NoError(t, rootCmd.PersistentFlags().Set("limit", "2"))
+	defer func() {
+		limitFlag.Value.Set("1")
+		limitFlag.Changed = false
+	}()
+	applyConfig(pingCmd)
+	assert.Equal(t, "Asia", ctx.From)
+	assert.Equal(t, 2, ctx.Limit)
+}
+
+func TestApplyConfigEnvironment(t *testing.T) {
+	config.Path = filepath.Join(t.TempDir(), "config.yml")
+	c := &config.Config{
+		Env: "staging",
+		Environments: map[string]config.Environment{
+			"staging": {Url: "https://api.staging.example.com/v1", Token: "staging-token"},
+			"local":   {Url: "http://localhost:3000/v1/"},
+		},
+	}
+	assert.NoError(t, c.Save())
+
+	apiUrl, probesApiUrl, limitsApiUrl, token := client.ApiUrl, client.ProbesApiUrl, client.LimitsApiUrl, client.ApiToken
+	t.Cleanup(func() {
+		client.ApiUrl, client.ProbesApiUrl, client.LimitsApiUrl, client.ApiToken = apiUrl, probesApiUrl, limitsApiUrl, token
+		apiEnv = ""
+		ctx = model.Context{}
+	})
+
+	// The env key of the config file selects the default environment
+	client.ApiToken = "stored-token"
+	assert.NoError(t, applyConfig(pingCmd))
+	assert.Equal(t, "https://api.staging.example.com/v1/measurements", client.ApiUrl)
+	assert.Equal(t, "https://api.staging.example.com/v1/probes", client.ProbesApiUrl)
+	assert.Equal(t, "staging-token", client.ApiToken)
+
+	// --env takes precedence, an environment without a token never gets the stored token
+	apiEnv = "local"
+	client.ApiToken = "stored-token"
+	assert.NoError(t, applyConfig(pingCmd))
+	assert.Equal(t, "http://localhost:3000/v1/measurements", client.ApiUrl)
+	assert.Equal(t, "", client.ApiToken)
+
+	apiEnv = "prod"
+	assert.EqualError(t, applyConfig(pingCmd), `unknown environment "prod" - define it with: globalping config set environments.prod.url https://api.example.com/v1`)
+}
+
+func TestListConfigMasksTokens(t *testing.T) {
+	c := &config.Config{
+		InfluxToken:  "influx-secret-token",
+		Environments: map[string]config.Environment{"staging": {Url: "https://staging.example.com/v1", Token: "staging-secret-token"}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, listConfig(&buf, c))
+	assert.Contains(t, buf.String(), "infl***********oken")
+	assert.Contains(t, buf.String(), "environments.staging.token  stag************oken")
+	assert.NotContains(t, buf.String(), "secret")
+}
+
+func TestValidateExport(t *testing.T) {
+	config.Path = filepath.Join(t.TempDir(), "config.yml")
+	t.Cleanup(func() {
+		exportTo = ""
+		resultsExporter = nil
+	})
+
+	assert.NoError(t, validateExport())
+	assert.Nil(t, resultsExporter)
+
+	exportTo = "prometheus"
+	assert.EqualError(t, validateExport(), `unsupported export destination "prometheus", supported destinations are influxdb`)
+
+	exportTo = "influxdb"
+	assert.ErrorContains(t, validateExport(), "the InfluxDB url and bucket are required")
+
+	c := &config.Config{InfluxUrl: "http://localhost:8086", InfluxBucket: "globalping"}
+	assert.NoError(t, c.Save())
+	assert.NoError(t, validateExport())
+	assert.Equal(t, "globalping", resultsExporter.Bucket)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// dashboardCmd represents the dashboard command
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard [type] [target] from [location]",
+	Short: "Show a live measurement in an interactive terminal dashboard",
+	Long: `The dashboard command runs a measurement and shows its results live in an interactive terminal UI with the list of probes, the output of the selected probe and aggregate stats.
+Supported types are ping, traceroute, dns, mtr and http.
+
+Keybindings:
+  up/down, k/j  select a probe
+  r             run the measurement again from the same probes
+  f             switch between the raw output and the latency stats
+  c             copy the share URL of the measurement to the clipboard
+  q, ctrl+c     quit
+
+Examples:
+  # Watch a ping to google.com from 5 probes in Europe
+  dashboard ping google.com from Europe --limit 5
+
+  # Watch the HTTP timings of jsdelivr.com from the US
+  dashboard http jsdelivr.com from USA --limit 3 --latency`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := createContext(args[0], args[1:])
+		if err != nil {
+			return err
+		}
+		if ctx.CI && !dryRun {
+			return errors.New("the dashboard requires an interactive terminal")
+		}
+
+		build, err := dashboardBuilder(ctx.Cmd)
+		if err != nil {
+			return err
+		}
+		m, err := build()
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return dryRunMeasurements([]model.PostMeasurement{m})
+		}
+
+		post := func() (string, error) {
+			res, err := client.PostAPI(runCtx, m)
+			if err != nil {
+				return "", err
+			}
+			recordHistory(res.ID, m.Type, ctx.Target)
+
+			// Re-runs use the same probes as the first measurement
+			m.Locations = []model.Locations{{Magic: res.ID}}
+			return res.ID, nil
+		}
+
+		err = client.RunDashboard(runCtx, ctx, post)
+		if err != nil {
+			fmt.Println(err)
+		}
+		return nil
+	},
+}
+
+// dashboardBuilder returns the builder of the measurement command with the given name
+func dashboardBuilder(measurementType string) (func() (model.PostMeasurement, error), error) {
+	switch measurementType {
+	case "ping":
+		return buildPingMeasurement, nil
+	case "traceroute":
+		return buildTracerouteMeasurement, nil
+	case "dns":
+		return buildDnsMeasurement, nil
+	case "mtr":
+		return buildMtrMeasurement, nil
+	case PostMeasurementTypeHttp:
+		return buildHttpMeasurementRequest, nil
+	}
+	return nil, fmt.Errorf("unsupported measurement type: %s", measurementType)
+}
+
+func init() {
+	rootCmd.AddCommand(dashboardCmd)
+}
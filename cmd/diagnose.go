@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// diagnoseCmd represents the diagnose command
+var diagnoseCmd = &cobra.Command{
+	Use:   "diagnose [target] from [location]",
+	Short: "Run ping, traceroute, dns and http against a target and report where it fails",
+	Long: `The diagnose command runs ping, traceroute, dns and http measurements one after the other against a single target from the same probes, then prints a report with one row per probe showing whether the failure is at the DNS, connectivity or HTTP layer.
+The target is either a hostname or a URL, the URL is used for the http measurement and its host for the other ones.
+
+Examples:
+  # Diagnose jsdelivr.com from 3 probes in Europe
+  diagnose jsdelivr.com from Europe --limit 3
+
+  # Diagnose an URL from a probe in Germany
+  diagnose https://cdn.jsdelivr.net/npm/react from Germany`,
+	Args: checkCommandFormat(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := createContext(cmd.CalledAs(), args)
+		if err != nil {
+			return err
+		}
+
+		if err := rejectOutput("diagnose"); err != nil {
+			return err
+		}
+		if err := rejectCopy("diagnose"); err != nil {
+			return err
+		}
+
+		measurements, err := buildDiagnoseMeasurements(ctx.Target)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return dryRunMeasurements(measurements)
+		}
+
+		results := map[string]model.GetMeasurement{}
+		var firstID string
+		for _, m := range measurements {
+			// Every step runs from the probes of the first one
+			if firstID != "" {
+				m.Locations = []model.Locations{{Magic: firstID}}
+			}
+
+			if !ctx.CI {
+				client.Logf(client.LevelNormal, "Running %s...", m.Type)
+			}
+
+			res, err := client.PostAPI(runCtx, m)
+			if err != nil {
+				return postError(err)
+			}
+			if firstID == "" {
+				firstID = res.ID
+			}
+			recordHistory(res.ID, m.Type, m.Target)
+
+			data, err := client.WaitForResults(runCtx, res.ID)
+			if err != nil {
+				fmt.Println(err)
+				return nil
+			}
+			results[m.Type] = data
+		}
+
+		fmt.Println(client.FormatDiagnosis(results))
+		return nil
+	},
+}
+
+// buildDiagnoseMeasurements builds the measurements of every diagnose step, in the order they are run
+func buildDiagnoseMeasurements(target string) ([]model.PostMeasurement, error) {
+	urlData, err := parseUrlData(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var measurements []model.PostMeasurement
+	for _, step := range client.DiagnoseSteps {
+		stepTarget := urlData.Host
+		if step == PostMeasurementTypeHttp {
+			stepTarget = target
+		}
+		m, err := buildCompareMeasurement(step, stepTarget)
+		if err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, m)
+	}
+	return measurements, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diagnoseCmd)
+}
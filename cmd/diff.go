@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff [id|file] [id|file]",
+	Short: "Compare the results of two measurements probe by probe",
+	Long: `The diff command aligns the results of two measurements of the same type by probe and prints the status, latency, packet loss and hop count of both with their deltas, to validate a change before and after it is rolled out.
+Probes are matched by city, country and network, probes missing from one of the measurements are then matched by country and network only. Probes that stopped finishing, lost packets or got at least 20% slower are highlighted as regressions.
+Measurements are given by ID, by "last" for the most recent measurement of the history, or as JSON files saved with --json.
+
+Examples:
+  # Compare two measurements
+  diff UKbdVoWpIr6ec0cy nMFbFUHpq7DdAjqs
+
+  # Compare a saved measurement with the last one
+  diff before.json last`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := loadDiffMeasurement(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		b, err := loadDiffMeasurement(args[1])
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		if a.Type != b.Type {
+			fmt.Printf("err: cannot compare a %s measurement with a %s measurement\n", a.Type, b.Type)
+			return nil
+		}
+
+		detectCI()
+		fmt.Println(client.DiffResults(a.Type, a, b, ctx))
+		return nil
+	},
+}
+
+// loadDiffMeasurement loads a measurement like show, "last" is the most recent measurement of the history
+func loadDiffMeasurement(arg string) (model.GetMeasurement, error) {
+	id, err := resolveMeasurementID(arg)
+	if err != nil {
+		return model.GetMeasurement{}, err
+	}
+	return loadMeasurement(id)
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
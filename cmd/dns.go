@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/jsdelivr/globalping-cli/client"
 	"github.com/jsdelivr/globalping-cli/model"
@@ -10,7 +13,7 @@ import (
 
 // dnsCmd represents the dns command
 var dnsCmd = &cobra.Command{
-	Use:     "dns [target] from [location]",
+	Use:     "dns [target...] from [location]",
 	GroupID: "Measurements",
 	Short:   "Use the native dig command",
 	Long: `Performs DNS lookups and displays the answers that are returned from the name server(s) that were queried. 
@@ -23,6 +26,24 @@ Examples:
   # Resolve google.com from 2 probes from London or Belgium with trace enabled
   dns google.com from London,Belgium --limit 2 --trace
 
+  # Query the A, AAAA, MX and TXT records of jsdelivr.com from the same 2 probes
+  dns jsdelivr.com from Europe --limit 2 --type A,AAAA,MX,TXT
+
+  # Check that the answers of jsdelivr.com are DNSSEC validated by the resolver of 3 probes
+  dns jsdelivr.com from Europe --limit 3 --dnssec
+
+  # Compare the answers of Google and Cloudflare public resolvers from the same 2 probes
+  dns jsdelivr.com from Europe --limit 2 --resolver-list 8.8.8.8,1.1.1.1
+
+  # Check if the new A record of example.com propagated, with 3 probes on every continent
+  dns example.com --propagation --expect 1.2.3.4 --limit 3
+
+  # Print only the addresses jsdelivr.com resolves to, like dig +short
+  dns jsdelivr.com --short
+
+  # Print only the CNAME records of the answers of 3 probes in Europe
+  dns cdn.jsdelivr.net from Europe --limit 3 --answer-filter CNAME
+
   # Resolve jsdelivr.com from a probe that is from the AWS network and is located in Montreal with latency output
   dns jsdelivr.com from aws+montreal --latency
 
@@ -37,37 +58,223 @@ Examples:
 			return err
 		}
 
-		// Make post struct
-		opts = model.PostMeasurement{
-			Type:      "dns",
-			Target:    ctx.Target,
-			Locations: createLocations(ctx.From),
-			Limit:     ctx.Limit,
-			Options: &model.MeasurementOptions{
-				Protocol: protocol,
-				Port:     port,
-				Resolver: resolver,
-				Query: &model.QueryOptions{
-					Type: queryType,
-				},
-				Trace: trace,
-			},
+		if answerFilter != "" {
+			ctx.AnswerFilter = strings.Split(strings.ToUpper(answerFilter), ",")
+			for i, t := range ctx.AnswerFilter {
+				ctx.AnswerFilter[i] = strings.TrimSpace(t)
+			}
+		}
+		// Print the typed answers instead of the raw dig output
+		if short && ctx.Format == "" && !ctx.JsonOutput && !ctx.Latency {
+			ctx.Format = "short"
+		}
+		if answerFilter != "" && ctx.Format == "" && !ctx.JsonOutput && !ctx.Latency && !trace && !dnssec {
+			ctx.Format = "answers"
 		}
 
-		res, showHelp, err := client.PostAPI(opts)
-		if err != nil {
-			if showHelp {
-				return err
+		// Show the delegation path instead of the raw dig output
+		if trace && ctx.Format == "" && !ctx.JsonOutput && !ctx.Latency {
+			ctx.Format = "trace"
+		}
+		if dnssec && ctx.Format == "" && !ctx.JsonOutput && !ctx.Latency {
+			ctx.Format = "dnssec"
+		}
+
+		if propagation {
+			return dnsPropagation()
+		}
+
+		if resolverList != "" {
+			for _, target := range ctx.Targets {
+				ctx.Target = target
+				if err := dnsMultiResolver(strings.Split(resolverList, ",")); err != nil {
+					return err
+				}
 			}
-			fmt.Println(err)
 			return nil
 		}
 
-		client.OutputResults(res.ID, ctx)
-		return nil
+		types := strings.Split(queryType, ",")
+		if len(types) > 1 {
+			for _, target := range ctx.Targets {
+				ctx.Target = target
+				if err := dnsMultiType(types); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		return runMeasurements(buildDnsMeasurement)
 	},
 }
 
+// dnsPropagation queries the target from every continent, unless locations are given, and reports which probes and
+// regions already resolve it to the expected values
+func dnsPropagation() error {
+	if err := rejectOutput("--propagation"); err != nil {
+		return err
+	}
+	if expect == "" {
+		return errors.New("--propagation requires the expected value(s) with --expect")
+	}
+	expected := strings.Split(expect, ",")
+
+	m, err := buildDnsMeasurement()
+	if err != nil {
+		return err
+	}
+	if ctx.From == "world" {
+		// --limit probes on every continent
+		m.Locations = make([]model.Locations, len(client.PropagationContinents))
+		for i, c := range client.PropagationContinents {
+			m.Locations[i] = model.Locations{Continent: c, Limit: ctx.Limit}
+		}
+		m.Limit = ctx.Limit * len(client.PropagationContinents)
+	}
+	if dryRun {
+		return dryRunMeasurements([]model.PostMeasurement{m})
+	}
+
+	res, err := client.PostAPI(runCtx, m)
+	if err != nil {
+		return postError(err)
+	}
+	recordHistory(res.ID, "dns", ctx.Target)
+
+	data, err := client.WaitForResults(runCtx, res.ID)
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
+
+	if ctx.JsonOutput {
+		client.OutputJson(runCtx, res.ID)
+	} else {
+		output, _ := client.FormatPropagation(data, expected)
+		fmt.Println(output)
+	}
+	copyResults(res.ID, data)
+
+	for _, result := range data.Results {
+		if !client.Propagated(result, expected) {
+			exitCode = 1
+			break
+		}
+	}
+	return nil
+}
+
+// dnsMultiType posts one measurement per record type of the current target and prints the answers grouped by probe
+func dnsMultiType(types []string) error {
+	measurements := make([]model.PostMeasurement, len(types))
+	for i, t := range types {
+		types[i] = strings.ToUpper(strings.TrimSpace(t))
+		m, err := buildDnsMeasurement()
+		if err != nil {
+			return err
+		}
+		m.Options.Query = &model.QueryOptions{Type: types[i]}
+		measurements[i] = m
+	}
+
+	return dnsGroup(types, measurements)
+}
+
+// dnsMultiResolver posts one measurement per resolver of the current target and prints the answers grouped by probe
+func dnsMultiResolver(resolvers []string) error {
+	measurements := make([]model.PostMeasurement, len(resolvers))
+	for i, r := range resolvers {
+		resolvers[i] = strings.TrimSpace(r)
+		m, err := buildDnsMeasurement()
+		if err != nil {
+			return err
+		}
+		m.Options.Resolver = resolvers[i]
+		measurements[i] = m
+	}
+
+	return dnsGroup(resolvers, measurements)
+}
+
+// dnsGroup runs related dns measurements concurrently, all from the probes of the first one, and prints their answers
+// grouped by probe with one label per measurement
+func dnsGroup(labels []string, measurements []model.PostMeasurement) error {
+	if err := rejectOutput("several --type or --resolver-list"); err != nil {
+		return err
+	}
+	if err := rejectCopy("several --type or --resolver-list"); err != nil {
+		return err
+	}
+	if dryRun {
+		return dryRunMeasurements(measurements)
+	}
+
+	r := newRunner()
+	r.Workers = len(measurements)
+	r.SameProbes = true
+	// Errors of single measurements are printed with their label below
+	results, err := r.Run(runCtx, measurements)
+	if results == nil {
+		return postError(err)
+	}
+
+	data := make([]model.GetMeasurement, len(results))
+	for i, r := range results {
+		if r.Err != nil {
+			fmt.Printf("%s: %s\n", labels[i], r.Err)
+			exitCode = 1
+			continue
+		}
+		recordHistory(r.ID, "dns", ctx.Target)
+		data[i] = r.Data
+		if ctx.JsonOutput {
+			client.OutputJson(runCtx, r.ID)
+		}
+	}
+
+	if !ctx.JsonOutput {
+		fmt.Println(client.FormatDnsGroups(labels, data, ctx))
+	}
+	for _, d := range data {
+		evaluateResults("dns", d)
+	}
+	return nil
+}
+
+// buildDnsMeasurement builds the measurement request for the dns type
+func buildDnsMeasurement() (model.PostMeasurement, error) {
+	// The address family applies to the connection to the resolver
+	v := 0
+	if ipv4 || ipv6 {
+		if resolver == "" || net.ParseIP(resolver) != nil {
+			return model.PostMeasurement{}, errors.New("-4 and -6 require a hostname --resolver for dns measurements")
+		}
+		var err error
+		v, err = ipVersion(resolver)
+		if err != nil {
+			return model.PostMeasurement{}, err
+		}
+	}
+
+	return model.PostMeasurement{
+		Type:      "dns",
+		Target:    ctx.Target,
+		Locations: createLocations(ctx.From),
+		Limit:     ctx.Limit,
+		Options: &model.MeasurementOptions{
+			Protocol: protocol,
+			Port:     port,
+			Resolver: resolver,
+			Query: &model.QueryOptions{
+				Type: queryType,
+			},
+			Trace:     trace,
+			IPVersion: v,
+		},
+	}, nil
+}
+
 func init() {
 	rootCmd.AddCommand(dnsCmd)
 
@@ -75,8 +282,18 @@ func init() {
 	dnsCmd.Flags().StringVar(&protocol, "protocol", "", "Specifies the protocol to use for the DNS query (TCP or UDP) (default \"udp\")")
 	dnsCmd.Flags().IntVar(&port, "port", 0, "Send the query to a non-standard port on the server (default 53)")
 	dnsCmd.Flags().StringVar(&resolver, "resolver", "", "Resolver is the name or IP address of the name server to query (default empty)")
-	dnsCmd.Flags().StringVar(&queryType, "type", "", "Specifies the type of DNS query to perform (default \"A\")")
-	dnsCmd.Flags().BoolVar(&trace, "trace", false, "Toggle tracing of the delegation path from the root name servers (default false)")
+	dnsCmd.Flags().StringVar(&resolverList, "resolver-list", "", "Comma separated resolvers to compare, runs one measurement per resolver from the same probes")
+	dnsCmd.Flags().StringVar(&queryType, "type", "", "Specifies the type of DNS query to perform, several comma separated types run one measurement each (default \"A\")")
+	dnsCmd.Flags().BoolVar(&trace, "trace", false, "Toggle tracing of the delegation path from the root name servers and print it for each probe (default false)")
+
+	dnsCmd.Flags().BoolVar(&dnssec, "dnssec", false, "Show the AD flag set by the resolver of each probe when it validated the answers with DNSSEC (default false)")
+	dnsCmd.Flags().BoolVar(&short, "short", false, "Print only the values of the answers, like dig +short (default false)")
+	dnsCmd.Flags().StringVar(&answerFilter, "answer-filter", "", "Comma separated record types of the answers to print, e.g. A,CNAME, the others are left out")
+	dnsCmd.Flags().BoolVar(&propagation, "propagation", false, "Check the propagation of a record, compares the answers of --limit probes on every continent to --expect (default false)")
+	dnsCmd.Flags().StringVar(&expect, "expect", "", "Comma separated values the answers must contain in propagation mode, e.g. 1.2.3.4")
+
+	// Threshold flags
+	dnsCmd.Flags().DurationVar(&ctx.Thresholds.MaxLatency, "max-latency", 0, "Exit with a non-zero code if the latency of any probe exceeds the given duration, e.g. 100ms")
 
 	// Extra flags
 	dnsCmd.Flags().BoolVar(&ctx.Latency, "latency", false, "Output only stats of a measurement (default false)")
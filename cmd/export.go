@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/config"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportTo     string
+	exportDest   string
+	influxUrl    string
+	influxOrg    string
+	influxBucket string
+	influxToken  string
+	exportFilter history.Filter
+
+	// resultsExporter receives the results of every measurement with --export, set by validateExport
+	resultsExporter *client.InfluxExporter
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [id...]",
+	Short: "Push the results of past measurements to a metrics database or a Grafana dashboard",
+	Long: `The export command fetches past measurements and pushes the results of every probe to a metrics database, or converts them into a Grafana dashboard. The results of new measurements are pushed after each run with --export influxdb.
+Without IDs, the measurements of the local history are exported, narrowed down with --type, --target and --last.
+The InfluxDB connection settings are read from the flags, falling back to the influxdb-* keys of the config file.
+The Grafana dashboard plots the latency trend of every target, the data is embedded with the TestData datasource bundled with Grafana. It is printed to stdout unless written to a file with --output, then imported from Dashboards > Import.
+
+Examples:
+  # Push two measurements to InfluxDB
+  export UKbdVoWpIr6ec0cy nV6BsB1kxdhYGGHQ --to influxdb --url http://localhost:8086 --org acme --bucket globalping --token $INFLUX_TOKEN
+
+  # Push the results of every run of a cron job, using the settings of the config file
+  ping google.com from Europe --limit 5 --export influxdb
+
+  # Create a Grafana dashboard of the last 50 ping measurements of jsdelivr.com
+  export --to grafana --type ping --target jsdelivr.com --last 50 -o dashboard.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch exportDest {
+		case "grafana":
+			measurements, err := exportedMeasurements(args)
+			if err != nil {
+				fmt.Println(err)
+				return nil
+			}
+			return exportGrafana(measurements)
+		case "influxdb":
+		default:
+			return fmt.Errorf("unsupported export destination %q, supported destinations are influxdb, grafana", exportDest)
+		}
+
+		exporter, err := newExporter(exportDest)
+		if err != nil {
+			return err
+		}
+
+		measurements, err := exportedMeasurements(args)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		var lines []string
+		for _, m := range measurements {
+			lines = append(lines, client.InfluxLines(m.data, model.Context{Cmd: m.data.Type, Target: m.target}, m.createdAt)...)
+		}
+
+		err = exporter.Push(lines)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		fmt.Printf("Exported %d results to InfluxDB\n", len(lines))
+		return nil
+	},
+}
+
+// A past measurement fetched for the export
+type exportedMeasurement struct {
+	data      model.GetMeasurement
+	target    string
+	createdAt time.Time
+}
+
+// exportedMeasurements fetches the measurements of the given IDs, or of the history entries matching the filter
+// flags if no ID is given
+func exportedMeasurements(ids []string) ([]exportedMeasurement, error) {
+	entries := make([]history.Entry, 0, len(ids))
+	for _, id := range ids {
+		entry, _, _ := history.Find(id)
+		entry.ID = id
+		entries = append(entries, entry)
+	}
+	if len(ids) == 0 {
+		var err error
+		entries, err = history.List(exportFilter)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return nil, errors.New("no measurement found in the history")
+		}
+	}
+
+	measurements := make([]exportedMeasurement, 0, len(entries))
+	for _, entry := range entries {
+		data, err := client.GetAPI(runCtx, entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		target := data.Target
+		if target == "" {
+			target = entry.Target
+		}
+		createdAt, err := time.Parse(time.RFC3339, data.CreatedAt)
+		if err != nil {
+			createdAt = entry.CreatedAt
+		}
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		measurements = append(measurements, exportedMeasurement{data: data, target: target, createdAt: createdAt})
+	}
+	return measurements, nil
+}
+
+// exportGrafana writes the Grafana dashboard of the measurements to the file selected with --output or to stdout
+func exportGrafana(measurements []exportedMeasurement) error {
+	runs := make([]client.GrafanaRun, len(measurements))
+	for i, m := range measurements {
+		runs[i] = client.GrafanaRun{Time: m.createdAt, Target: m.target, Data: m.data}
+	}
+
+	dashboard, err := client.GrafanaDashboard("Globalping", runs)
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
+
+	if ctx.Output == "" || ctx.Output == "-" {
+		fmt.Println(string(dashboard))
+		return nil
+	}
+	err = os.WriteFile(ctx.Output, append(dashboard, '\n'), 0o644)
+	if err != nil {
+		fmt.Printf("err: failed to write %s\n", ctx.Output)
+		return nil
+	}
+	fmt.Printf("Exported a Grafana dashboard of %d measurements to %s\n", len(measurements), ctx.Output)
+	return nil
+}
+
+// newExporter creates the exporter selected with --to or --export, flags take precedence over the config file
+func newExporter(to string) (*client.InfluxExporter, error) {
+	if to != "influxdb" {
+		return nil, fmt.Errorf("unsupported export destination %q, supported destinations are influxdb", to)
+	}
+
+	c, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	e := client.NewInfluxExporter(
+		overrideOpt(c.InfluxUrl, influxUrl),
+		overrideOpt(c.InfluxOrg, influxOrg),
+		overrideOpt(c.InfluxBucket, influxBucket),
+		overrideOpt(c.InfluxToken, influxToken),
+	)
+	if e.Url == "" || e.Bucket == "" {
+		return nil, errors.New("the InfluxDB url and bucket are required, set them with --url and --bucket or the influxdb-url and influxdb-bucket config keys")
+	}
+	return e, nil
+}
+
+// validateExport checks the --export flag and its settings before any measurement is run
+func validateExport() error {
+	if exportTo == "" {
+		return nil
+	}
+	var err error
+	resultsExporter, err = newExporter(exportTo)
+	return err
+}
+
+// exportResults pushes the results of a finished measurement to the destination selected with --export
+func exportResults(data model.GetMeasurement) {
+	if resultsExporter == nil {
+		return
+	}
+	err := resultsExporter.Push(client.InfluxLines(data, ctx, time.Now()))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	rootCmd.PersistentFlags().StringVar(&exportTo, "export", "", "Push the results to a metrics database after each run (influxdb), configured with the influxdb-* config keys")
+
+	exportCmd.Flags().StringVar(&exportDest, "to", "influxdb", "Destination of the results (influxdb, grafana)")
+	exportCmd.Flags().StringVar(&influxUrl, "url", "", "Base URL of the InfluxDB v2 API, e.g. http://localhost:8086")
+	exportCmd.Flags().StringVar(&influxOrg, "org", "", "InfluxDB organization")
+	exportCmd.Flags().StringVar(&influxBucket, "bucket", "", "InfluxDB bucket the results are written to")
+	exportCmd.Flags().StringVar(&influxToken, "token", "", "InfluxDB API token")
+	exportCmd.Flags().StringVar(&exportFilter.Type, "type", "", "Without IDs, only export measurements of the given type (ping, traceroute, dns, mtr, http)")
+	exportCmd.Flags().StringVar(&exportFilter.Target, "target", "", "Without IDs, only export measurements whose target contains the given text")
+	exportCmd.Flags().IntVar(&exportFilter.Last, "last", 0, "Without IDs, only export the N most recent measurements")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// getCmd represents the get command
+var getCmd = &cobra.Command{
+	Use:   "get [id]",
+	Short: "Fetch the results of a measurement by ID, waiting for it to finish",
+	Long: `The get command fetches a measurement by ID and outputs it like the command that created it, waiting for the probes that are still running. Every output flag like --format, --latency, --json or --output is supported.
+It is the way to recover the results of a measurement when the CLI was interrupted or stopped waiting because of --wait, the ID of the measurement is printed in both cases. "last" is the most recent measurement of the history.
+
+Examples:
+  # Fetch the results of measurement UKbdVoWpIr6ec0cy
+  get UKbdVoWpIr6ec0cy
+
+  # Fetch the results of the last measurement as a markdown table
+  get last --format markdown
+
+  # Fetch the results of measurement UKbdVoWpIr6ec0cy, waiting at most 30 more seconds for the probes
+  get UKbdVoWpIr6ec0cy --wait 30s`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !client.ValidFormat(ctx.Format) {
+			return fmt.Errorf("unknown format %q - supported formats: %s", ctx.Format, strings.Join(client.FormatNames(), ", "))
+		}
+
+		id, err := resolveMeasurementID(args[0])
+		if err != nil {
+			return err
+		}
+
+		// The type and target of the measurement select how it is rendered
+		data, err := client.GetAPI(runCtx, id)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		ctx.Cmd = data.Type
+		ctx.Target = data.Target
+		detectCI()
+
+		renderMeasurement(id, data.Type)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/spf13/cobra"
+)
+
+var historyFilter history.Filter
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List measurements previously run from this machine",
+	Long: `The history command lists the measurements recorded in the local history file (~/.globalping/history.json).
+
+Examples:
+  # List all recorded measurements
+  history
+
+  # List the last 5 ping measurements
+  history --type ping --last 5
+
+  # List measurements whose target contains jsdelivr
+  history --target jsdelivr
+
+  # Output the results of a past measurement again
+  history open UKbdVoWpIr6ec0cy`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := history.List(historyFilter)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No measurements found in history")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTYPE\tTARGET\tFROM\tDATE")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.ID, e.Type, e.Target, e.From, e.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+		}
+		return w.Flush()
+	},
+}
+
+// historyOpenCmd outputs the results of a measurement recorded in the history
+var historyOpenCmd = &cobra.Command{
+	Use:   "open [id]",
+	Short: "Output the results of a past measurement",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entry, ok, err := history.Find(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		if !ok {
+			return errors.New("measurement not found in history")
+		}
+
+		ctx.Cmd = entry.Type
+		ctx.Target = entry.Target
+		ctx.From = entry.From
+		_, err = client.OutputResults(runCtx, entry.ID, ctx)
+		if err != nil {
+			fmt.Println(err)
+		}
+		return nil
+	},
+}
+
+// historyClearCmd removes every measurement from the history
+var historyClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all measurements from the history",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := history.Clear()
+		if err != nil {
+			fmt.Println(err)
+		}
+		return nil
+	},
+}
+
+// recordHistory saves a posted measurement in the local history, failures are not fatal for the measurement
+func recordHistory(id, measurementType, target string) {
+	err := history.Add(history.Entry{
+		ID:        id,
+		Type:      measurementType,
+		Target:    target,
+		From:      ctx.From,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// resolveMeasurementID returns the measurement ID to reuse, "last" is the most recent measurement of the history
+func resolveMeasurementID(v string) (string, error) {
+	if v != "last" {
+		return v, nil
+	}
+
+	entries, err := history.List(history.Filter{Last: 1})
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", errors.New("no previous measurement found in the history")
+	}
+	return entries[0].ID, nil
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyOpenCmd)
+	historyCmd.AddCommand(historyClearCmd)
+
+	historyCmd.Flags().StringVar(&historyFilter.Type, "type", "", "Only list measurements of the given type (ping, traceroute, dns, mtr, http)")
+	historyCmd.Flags().StringVar(&historyFilter.Target, "target", "", "Only list measurements whose target contains the given text")
+	historyCmd.Flags().IntVar(&historyFilter.Last, "last", 0, "Only list the N most recent measurements")
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMeasurement(t *testing.T) {
+	path := history.Path
+	history.Path = filepath.Join(t.TempDir(), "history.json")
+	t.Cleanup(func() {
+		history.Path = path
+		fromMeasurement = ""
+		ctx = model.Context{}
+	})
+
+	ctx = model.Context{}
+	fromMeasurement = "last"
+	assert.EqualError(t, createContext("traceroute", []string{"google.com"}), "no previous measurement found in the history")
+
+	assert.NoError(t, history.Add(history.Entry{ID: "abcd", Type: "ping", Target: "google.com", CreatedAt: time.Now()}))
+	assert.NoError(t, createContext("traceroute", []string{"google.com"}))
+	assert.Equal(t, "abcd", ctx.From)
+	assert.Equal(t, []model.Locations{{Magic: "abcd"}}, createLocations(ctx.From))
+
+	assert.EqualError(t, createContext("traceroute", []string{"google.com", "from", "Germany"}), "--from-measurement cannot be used together with a location")
+}
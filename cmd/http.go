@@ -78,12 +78,47 @@ func overrideOptInt(orig, new int) int {
 	return orig
 }
 
+// parseHeaders parses "Name: value" header flags into the request headers map
+func parseHeaders(input []string) (map[string]string, error) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+
+	res := make(map[string]string, len(input))
+	for _, h := range input {
+		name, value, ok := strings.Cut(h, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, errors.Errorf("invalid header %q, expected \"Name: value\"", h)
+		}
+		res[name] = strings.TrimSpace(value)
+	}
+	return res, nil
+}
+
+// Methods supported by the http measurement
+var httpMethods = []string{"GET", "HEAD", "OPTIONS"}
+
+// parseMethod validates the --method flag, an empty method uses the API default
+func parseMethod(input string) (string, error) {
+	if input == "" {
+		return "", nil
+	}
+	m := strings.ToUpper(input)
+	for _, allowed := range httpMethods {
+		if m == allowed {
+			return m, nil
+		}
+	}
+	return "", errors.Errorf("invalid method %q, supported methods are %s", input, strings.Join(httpMethods, ", "))
+}
+
 // httpCmd represents the http command
 var httpCmd = &cobra.Command{
-	Use:     "http [target] from [location]",
+	Use:     "http [target...] from [location]",
 	GroupID: "Measurements",
-	Short:   "Perform a HEAD or GET request to a host",
-	Long: `The http command sends an HTTP request to a host and can perform HEAD or GET operations. GET is limited to 10KB responses, everything above will be cut by the API.
+	Short:   "Perform a HEAD, GET or OPTIONS request to a host",
+	Long: `The http command sends an HTTP request to a host and can perform HEAD, GET or OPTIONS operations. GET is limited to 10KB responses, everything above will be cut by the API.
 
 Examples:
   # HTTP HEAD request to jsdelivr.com from 2 probes in New York (protocol, port and path are inferred from the URL)
@@ -92,11 +127,38 @@ Examples:
   # HTTP GET request to google.com from 2 probes from London or Belgium
   http google.com from London,Belgium --limit 2 --method get
 
+  # HTTP GET request to jsdelivr.com printing the response body returned to each probe
+  http jsdelivr.com from Germany --body
+
+  # Save the page returned to 3 probes in Europe to page-<country>-<city>.html, keeping the first 5000 bytes
+  http jsdelivr.com from Europe --limit 3 --save-body page.html --body-limit 5000
+
+  # Follow the redirects of http://jsdelivr.com from 3 probes and print every hop
+  http jsdelivr.com from Europe --limit 3 --follow-redirects
+
+  # Check which probes get the page from the cache of the CDN, compressed and with HSTS
+  http cdn.jsdelivr.net/npm/react from Europe --limit 3 --analyze
+
+  # Find out which CDN POPs serve jsdelivr.com to 10 probes in Europe
+  http jsdelivr.com from Europe --limit 10 --format cdn
+
+  # Compare where the time of the request goes in every region, with one probe per continent
+  http jsdelivr.com from Europe,North America,Asia,Oceania --format waterfall
+
   # HTTP HEAD request to jsdelivr.com from a probe that is from the AWS network and is located in Montreal using HTTP2
   http jsdelivr.com from aws+montreal --protocol http2
 
   # HTTP GET request google.com with ASN 12345 with json output
-  http google.com from 12345 --json`,
+  http google.com from 12345 --json
+
+  # HTTP HEAD request to a load balancer IP with a Host header override and a cache-busting header
+  http 203.0.113.10 from Germany --header "Host: www.example.com" --header "Cache-Control: no-cache"
+
+  # Exit with a non-zero code unless every probe gets a 200 response
+  http jsdelivr.com from Europe --limit 5 --expect-status 200
+
+  # Check that every probe gets the page from nginx with the expected content
+  http example.com from Europe --limit 5 --expect-body-contains "Example Domain" --expect-header "Server: nginx"`,
 	Args: checkCommandFormat(),
 	RunE: httpCmdRun,
 }
@@ -109,24 +171,95 @@ func httpCmdRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// build http measurement
-	m, err := buildHttpMeasurementRequest()
+	ctx.Thresholds.ExpectHeaders, err = parseHeaders(expectHeaders)
 	if err != nil {
 		return err
 	}
 
-	opts = m
-	res, showHelp, err := client.PostAPI(opts)
-	if err != nil {
-		if showHelp {
+	if followRedirects {
+		return followRedirectChain()
+	}
+
+	return runMeasurements(buildHttpMeasurementRequest)
+}
+
+// followRedirectChain measures the target and then every URL it redirects to, from the same probes,
+// printing each hop until a response is not a redirect or maxRedirects is reached
+func followRedirectChain() error {
+	if err := rejectOutput("--follow-redirects"); err != nil {
+		return err
+	}
+	if err := rejectCopy("--follow-redirects"); err != nil {
+		return err
+	}
+	target := ctx.Target
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		target = "http://" + target
+	}
+
+	seen := map[string]bool{}
+	prevID := ""
+	for hop := 1; ; hop++ {
+		seen[target] = true
+		ctx.Target = target
+
+		m, err := buildHttpMeasurementRequest()
+		if err != nil {
 			return err
 		}
-		fmt.Println(err)
-		return nil
-	}
+		if prevID != "" {
+			m.Locations = []model.Locations{{Magic: prevID}}
+		}
+		// The following hops depend on the responses
+		if dryRun {
+			return dryRunMeasurements([]model.PostMeasurement{m})
+		}
+
+		res, err := client.PostAPI(runCtx, m)
+		if err != nil {
+			return postError(err)
+		}
+		recordHistory(res.ID, m.Type, target)
+		prevID = res.ID
+
+		data, err := client.WaitForResults(runCtx, res.ID)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		if ctx.JsonOutput {
+			client.OutputJson(runCtx, res.ID)
+		} else {
+			fmt.Println(client.FormatRedirectHop(hop, target, data))
+		}
 
-	client.OutputResults(res.ID, ctx)
-	return nil
+		next, ok, err := client.NextRedirect(target, data)
+		if err != nil {
+			fmt.Println(err)
+			evaluateResults(m.Type, data)
+			exitCode = 1
+			return nil
+		}
+		if !ok {
+			evaluateResults(m.Type, data)
+			return nil
+		}
+		if seen[next] {
+			fmt.Printf("err: redirect loop to %s\n", next)
+			exitCode = 1
+			return nil
+		}
+		if hop > maxRedirects {
+			fmt.Printf("err: stopped after %d redirects\n", maxRedirects)
+			exitCode = 1
+			return nil
+		}
+
+		// The next URL is complete, flags overriding parts of the first one no longer apply
+		target = next
+		path, query, host, protocol, port = "", "", "", "", 0
+	}
 }
 
 const PostMeasurementTypeHttp = "http"
@@ -142,6 +275,30 @@ func buildHttpMeasurementRequest() (model.PostMeasurement, error) {
 		return m, err
 	}
 
+	requestMethod, err := parseMethod(method)
+	if err != nil {
+		return m, err
+	}
+	// Only GET requests return a body
+	if requestMethod == "" && (ctx.ShowBody || ctx.SaveBody != "") {
+		requestMethod = "GET"
+	}
+
+	requestHeaders, err := parseHeaders(headers)
+	if err != nil {
+		return m, err
+	}
+
+	v, err := ipVersion(urlData.Host)
+	if err != nil {
+		return m, err
+	}
+
+	// Body assertions need the body, only returned for GET requests
+	if requestMethod == "" && ctx.Thresholds.ExpectBodyContains != "" {
+		requestMethod = "GET"
+	}
+
 	m.Target = urlData.Host
 	m.Locations = createLocations(ctx.From)
 	m.Limit = ctx.Limit
@@ -149,12 +306,14 @@ func buildHttpMeasurementRequest() (model.PostMeasurement, error) {
 		Protocol: overrideOpt(urlData.Protocol, protocol),
 		Port:     overrideOptInt(urlData.Port, port),
 		Packets:  packets,
+		// Address family of the connection to the target
+		IPVersion: v,
 		Request: &model.RequestOptions{
-			Path:  overrideOpt(urlData.Path, path),
-			Query: overrideOpt(urlData.Query, query),
-			Host:  overrideOpt(urlData.Host, host),
-			// TODO: Headers: headers,
-			Method: method,
+			Path:    overrideOpt(urlData.Path, path),
+			Query:   overrideOpt(urlData.Query, query),
+			Host:    overrideOpt(urlData.Host, host),
+			Headers: requestHeaders,
+			Method:  requestMethod,
 		},
 		Resolver: resolver,
 	}
@@ -169,11 +328,25 @@ func init() {
 	httpCmd.Flags().StringVar(&path, "path", "", "A URL pathname (default \"/\")")
 	httpCmd.Flags().StringVar(&query, "query", "", "A query-string")
 	httpCmd.Flags().StringVar(&host, "host", "", "Specifies the Host header, which is going to be added to the request (default host defined in target)")
-	httpCmd.Flags().StringVar(&method, "method", "", "Specifies the HTTP method to use (HEAD or GET) (default \"HEAD\")")
+	httpCmd.Flags().StringArrayVar(&headers, "header", nil, "Adds a request header in the \"Name: value\" format, can be repeated")
+	httpCmd.Flags().StringVar(&method, "method", "", "Specifies the HTTP method to use (GET, HEAD or OPTIONS) (default \"HEAD\")")
 	httpCmd.Flags().StringVar(&protocol, "protocol", "", "Specifies the query protocol (HTTP, HTTPS, HTTP2) (default \"HTTP\")")
 	httpCmd.Flags().IntVar(&port, "port", 0, "Specifies the port to use (default 80 for HTTP, 443 for HTTPS and HTTP2)")
 	httpCmd.Flags().StringVar(&resolver, "resolver", "", "Specifies the resolver server used for DNS lookup")
 
+	httpCmd.Flags().BoolVar(&followRedirects, "follow-redirects", false, "Run a new measurement from the same probes for every Location header and print the redirect chain, it stops if the probes are not all redirected to the same URL (default false)")
+	httpCmd.Flags().IntVar(&maxRedirects, "max-redirects", 10, "Maximum number of redirects followed with --follow-redirects")
+
+	// Threshold flags
+	httpCmd.Flags().DurationVar(&ctx.Thresholds.MaxLatency, "max-latency", 0, "Exit with a non-zero code if the latency of any probe exceeds the given duration, e.g. 100ms")
+	httpCmd.Flags().IntVar(&ctx.Thresholds.ExpectStatus, "expect-status", 0, "Exit with a non-zero code if any probe gets a different HTTP status code")
+	httpCmd.Flags().StringVar(&ctx.Thresholds.ExpectBodyContains, "expect-body-contains", "", "Exit with a non-zero code if the response body of any probe does not contain the given text, sends a GET request unless --method is set")
+	httpCmd.Flags().StringArrayVar(&expectHeaders, "expect-header", nil, "Exit with a non-zero code if any probe gets a response header that does not contain the expected value, in the \"Name: value\" format, can be repeated")
+
 	// Extra flags
+	httpCmd.Flags().BoolVar(&ctx.Analyze, "analyze", false, "Print the response headers of each probe with their cache status, compression, HSTS policy and redirect (default false)")
+	httpCmd.Flags().BoolVar(&ctx.ShowBody, "body", false, "Print the response body returned to each probe, sends a GET request unless --method is set (default false)")
+	httpCmd.Flags().StringVar(&ctx.SaveBody, "save-body", "", "Save the response body returned to each probe to a file named after the probe location, e.g. out.html becomes out-DE-Berlin.html")
+	httpCmd.Flags().IntVar(&ctx.BodyLimit, "body-limit", 0, "Maximum number of bytes of each response body printed or saved (default no limit)")
 	httpCmd.Flags().BoolVar(&ctx.Latency, "latency", false, "Output only stats of a measurement (default false)")
 }
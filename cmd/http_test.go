@@ -3,6 +3,7 @@ package cmd
 import (
 	"testing"
 
+	"github.com/jsdelivr/globalping-cli/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,3 +43,43 @@ func TestOverrideOpt(t *testing.T) {
 	assert.Equal(t, 10, overrideOptInt(0, 10))
 	assert.Equal(t, 10, overrideOptInt(10, 0))
 }
+
+func TestParseHeaders(t *testing.T) {
+	res, err := parseHeaders([]string{"Host: www.example.com", "Authorization:Bearer abc:def", "X-Empty:"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Host": "www.example.com", "Authorization": "Bearer abc:def", "X-Empty": ""}, res)
+
+	res, err = parseHeaders(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	_, err = parseHeaders([]string{"no-colon"})
+	assert.EqualError(t, err, `invalid header "no-colon", expected "Name: value"`)
+}
+
+func TestBuildHttpMeasurementRequestHeaders(t *testing.T) {
+	ctx = model.Context{Target: "https://example.com/path", From: "Germany", Limit: 1}
+	headers = []string{"Cache-Control: no-cache"}
+	t.Cleanup(func() {
+		ctx = model.Context{}
+		headers = nil
+	})
+
+	m, err := buildHttpMeasurementRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Cache-Control": "no-cache"}, m.Options.Request.Headers)
+	assert.Equal(t, "/path", m.Options.Request.Path)
+}
+
+func TestParseMethod(t *testing.T) {
+	m, err := parseMethod("get")
+	assert.NoError(t, err)
+	assert.Equal(t, "GET", m)
+
+	m, err = parseMethod("")
+	assert.NoError(t, err)
+	assert.Equal(t, "", m)
+
+	_, err = parseMethod("POST")
+	assert.EqualError(t, err, `invalid method "POST", supported methods are GET, HEAD, OPTIONS`)
+}
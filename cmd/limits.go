@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// limitsCmd represents the limits command
+var limitsCmd = &cobra.Command{
+	Use:   "limits",
+	Short: "Show your current rate limits and credits",
+	Long: `The limits command shows how many measurements you can still create, when the rate limit resets and your remaining credits.
+Every probe used by a measurement counts as one request, e.g. a measurement with --limit 5 costs 5.
+Authenticate with "globalping auth login" to get higher limits.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limits, err := client.GetLimits(runCtx)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		if ctx.JsonOutput {
+			b, err := json.MarshalIndent(limits, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+
+		create := limits.RateLimit.Measurements.Create
+		fmt.Printf("Authentication: %s\n", create.Type)
+		fmt.Printf("Measurements: %d of %d remaining\n", create.Remaining, create.Limit)
+		if create.Reset > 0 {
+			reset := time.Duration(create.Reset) * time.Second
+			fmt.Printf("Resets in: %s (%s)\n", reset, time.Now().Add(reset).Format("15:04:05"))
+		}
+		if limits.Credits != nil {
+			fmt.Printf("Credits: %d remaining\n", limits.Credits.Remaining)
+		}
+		fmt.Println("Cost: 1 per probe used by a measurement")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(limitsCmd)
+}
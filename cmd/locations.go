@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var locationsCategory string
+
+// locationsCmd represents the locations command
+var locationsCmd = &cobra.Command{
+	Use:   "locations",
+	Short: "List the locations probes can be selected from",
+	Long: `The locations command lists the magic location keywords accepted by --from and "from", grouped by category with the number of probes currently online in each of them.
+Categories are continents, regions, countries, US states, cloud regions, eyeball networks (residential ISPs), other networks and tags.
+
+Examples:
+  # List every location
+  locations
+
+  # List the countries with online probes
+  locations --category country
+
+  # List the cloud regions with json output
+  locations --category cloud --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if locationsCategory != "" && !validCategory(locationsCategory) {
+			return fmt.Errorf("unknown category %q - supported categories: %s", locationsCategory, strings.Join(client.LocationCategories, ", "))
+		}
+
+		probes, err := client.GetProbes(runCtx)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		var locations []client.Location
+		for _, l := range client.OnlineLocations(probes) {
+			if locationsCategory == "" || l.Category == locationsCategory {
+				locations = append(locations, l)
+			}
+		}
+
+		if ctx.JsonOutput {
+			b, err := json.MarshalIndent(locations, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+
+		printLocations(os.Stdout, locations)
+		return nil
+	},
+}
+
+func validCategory(category string) bool {
+	for _, c := range client.LocationCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// printLocations prints one table per category, locations are already sorted by category
+func printLocations(out io.Writer, locations []client.Location) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for i, l := range locations {
+		if i == 0 || locations[i-1].Category != l.Category {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "%s\tPROBES\n", strings.ToUpper(l.Category))
+		}
+		fmt.Fprintf(w, "%s\t%d\n", l.Keyword, l.Probes)
+	}
+	w.Flush()
+}
+
+// Maximum time spent fetching the online probes to validate the locations
+const validateTimeout = 5 * time.Second
+
+// warnUnmatchedLocations warns about the locations no online probe matches, with the closest keywords of the online
+// probes. It is only called once the API found no probes, so successful measurements never wait for the probes list.
+func warnUnmatchedLocations() {
+	if fromMeasurement != "" || ctx.From == "world" {
+		return
+	}
+
+	c, cancel := context.WithTimeout(runCtx, validateTimeout)
+	defer cancel()
+	probes, err := client.GetProbes(c)
+	if err != nil || len(probes) == 0 {
+		return
+	}
+
+	for _, l := range client.UnmatchedLocations(createLocations(ctx.From), probes) {
+		suggestions := client.SuggestLocations(l, probes, 3)
+		if len(suggestions) == 0 {
+			client.Logf(client.LevelNormal, "warning: no online probes match '%s'", l)
+			continue
+		}
+		client.Logf(client.LevelNormal, "warning: no online probes match '%s', did you mean '%s'?", l, strings.Join(suggestions, "', '"))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(locationsCmd)
+
+	locationsCmd.Flags().StringVar(&locationsCategory, "category", "", "Only list the locations of the given category (e.g. country)")
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintLocations(t *testing.T) {
+	var buf bytes.Buffer
+	printLocations(&buf, []client.Location{
+		{Keyword: "Europe", Category: client.CategoryContinent, Probes: 3},
+		{Keyword: "Germany", Category: client.CategoryCountry, Probes: 2},
+		{Keyword: "France", Category: client.CategoryCountry, Probes: 1},
+	})
+
+	assert.Equal(t, `CONTINENT  PROBES
+Europe     3
+
+COUNTRY  PROBES
+Germany  2
+France   1
+`, buf.String())
+}
+
+func TestWarnUnmatchedLocationsOnNoProbes(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"location":{"continent":"EU","country":"DE","city":"Frankfurt","asn":3320,"network":"Deutsche Telekom AG"}}]`))
+	}))
+	defer server.Close()
+	probesUrl := client.ProbesApiUrl
+	client.ProbesApiUrl = server.URL
+	var buf bytes.Buffer
+	client.Log = &buf
+	t.Cleanup(func() {
+		client.ProbesApiUrl = probesUrl
+		client.Log = os.Stderr
+		ctx = model.Context{}
+	})
+
+	ctx = model.Context{From: "Frankfrt"}
+	assert.Nil(t, postError(errors.New("err: measurement failed")))
+	assert.Equal(t, 0, requests)
+
+	err := postError(&client.APIError{Type: client.ErrorTypeNoProbes, Message: "no probes"})
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+	assert.Contains(t, buf.String(), "warning: no online probes match 'Frankfrt', did you mean 'Frankfurt'?")
+}
@@ -1,16 +1,15 @@
 package cmd
 
 import (
-	"fmt"
+	"errors"
 
-	"github.com/jsdelivr/globalping-cli/client"
 	"github.com/jsdelivr/globalping-cli/model"
 	"github.com/spf13/cobra"
 )
 
 // mtrCmd represents the mtr command
 var mtrCmd = &cobra.Command{
-	Use:     "mtr [target] from [location]",
+	Use:     "mtr [target...] from [location]",
 	GroupID: "Measurements",
 	Short:   "Use the native mtr command",
 	Long: `mtr combines the functionality of the traceroute and ping programs in a single network diagnostic tool.
@@ -25,6 +24,21 @@ Examples:
   # MTR jsdelivr.com from a probe that is from the AWS network and is located in Montreal using the TCP protocol
   mtr jsdelivr.com from aws+montreal --protocol tcp
 
+  # MTR google.com printing the native mtr output with live updates
+  mtr google.com from Germany --raw
+
+  # MTR google.com printing the lines of the mtr output as they are updated
+  mtr google.com from Germany --live
+
+  # Show the networks crossed by 5 probes in Europe to reach 1.1.1.1 and the most common transit networks
+  mtr 1.1.1.1 from Europe --limit 5 --format aspath
+
+  # Print the results of the probes that finished within 60 seconds, the other ones are marked incomplete
+  mtr google.com from world --limit 50 --wait 60s
+
+  # Find the hop where 10 probes in Europe start losing packets to jsdelivr.com
+  mtr jsdelivr.com from Europe --limit 10 --analyze
+
   # MTR jsdelivr.com with ASN 12345 with json output
   mtr jsdelivr.com from 12345 --json`,
 	Args: checkCommandFormat(),
@@ -35,41 +49,60 @@ Examples:
 			return err
 		}
 
-		// Make post struct
-		opts = model.PostMeasurement{
-			Type:      "mtr",
-			Target:    ctx.Target,
-			Locations: createLocations(ctx.From),
-			Limit:     ctx.Limit,
-			Options: &model.MeasurementOptions{
-				Protocol: protocol,
-				Port:     port,
-				Packets:  packets,
-			},
-		}
-
-		res, showHelp, err := client.PostAPI(opts)
-		if err != nil {
-			if showHelp {
-				return err
-			}
-			fmt.Println(err)
-			return nil
+		// Render the hops as a table unless the native output is requested
+		if !rawOutput && !ctx.Live && ctx.Format == "" && !ctx.JsonOutput {
+			ctx.Format = "table"
 		}
 
-		client.OutputResults(res.ID, ctx)
-		return nil
+		return runMeasurements(buildMtrMeasurement)
 	},
 }
 
+// buildMtrMeasurement builds the measurement request for the mtr type
+func buildMtrMeasurement() (model.PostMeasurement, error) {
+	p, err := validateProtocol(protocol, "ICMP", "TCP", "UDP")
+	if err != nil {
+		return model.PostMeasurement{}, err
+	}
+	if err := validatePort(port); err != nil {
+		return model.PostMeasurement{}, err
+	}
+	if port != 0 && (p == "" || p == "ICMP") {
+		return model.PostMeasurement{}, errors.New("--port is only supported with the TCP or UDP protocol")
+	}
+	if err := validatePackets(packets); err != nil {
+		return model.PostMeasurement{}, err
+	}
+
+	v, err := ipVersion(ctx.Target)
+	if err != nil {
+		return model.PostMeasurement{}, err
+	}
+
+	return model.PostMeasurement{
+		Type:      "mtr",
+		Target:    ctx.Target,
+		Locations: createLocations(ctx.From),
+		Limit:     ctx.Limit,
+		Options: &model.MeasurementOptions{
+			Protocol:  p,
+			Port:      port,
+			IPVersion: v,
+			Packets:   packets,
+		},
+	}, nil
+}
+
 func init() {
 	rootCmd.AddCommand(mtrCmd)
 
 	// mtr specific flags
 	mtrCmd.Flags().StringVar(&protocol, "protocol", "", "Specifies the protocol used for tracerouting (ICMP, TCP or UDP) (default \"icmp\")")
-	mtrCmd.Flags().IntVar(&port, "port", 0, "Specifies the port to use for the traceroute. Only applicable for TCP protocol (default 53)")
-	mtrCmd.Flags().IntVar(&packets, "packets", 0, "Specifies the number of packets to send to each hop (default 3)")
+	mtrCmd.Flags().IntVar(&port, "port", 0, "Specifies the port to use for the traceroute. Only applicable for TCP and UDP protocols (default 80)")
+	mtrCmd.Flags().IntVar(&packets, "packets", 0, "Specifies the number of packets to send to each hop, between 1 and 16 (default 3)")
 
 	// Extra flags
+	mtrCmd.Flags().BoolVar(&ctx.Analyze, "analyze", false, "Print the hop where the packet loss to the target begins for each probe, grouping the probes by suspected hop (default false)")
+	mtrCmd.Flags().BoolVar(&rawOutput, "raw", false, "Print the native mtr output with live updates instead of the hops table (default false)")
 	// mtrCmd.Flags().BoolVar(&ctx.Latency, "latency", false, "Output only stats of a measurement (default false)")
 }
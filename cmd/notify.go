@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+var (
+	webhookUrl       string
+	webhookOnFailure bool
+	notify           string
+)
+
+// notifyResults posts the results of a finished measurement to the webhook selected with --webhook
+func notifyResults(data model.GetMeasurement, violations []client.Violation) {
+	if webhookUrl == "" {
+		return
+	}
+	if webhookOnFailure && !client.MeasurementFailed(data, violations, ctx.Thresholds.FailOnProbeErrors) {
+		return
+	}
+
+	var payload interface{}
+	switch notify {
+	case "slack":
+		payload = client.NewSlackMessage(data, ctx, violations)
+	default:
+		payload = client.NewWebhookPayload(data, ctx, violations, time.Now())
+	}
+
+	err := client.PostWebhook(webhookUrl, payload)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// validateNotify checks the --notify flag, which formats the webhook payload for a chat service
+func validateNotify() error {
+	if notify != "" && notify != "slack" {
+		return fmt.Errorf("unsupported notification format %q, supported formats are slack", notify)
+	}
+	if notify != "" && webhookUrl == "" {
+		return errors.New("--notify requires the incoming webhook URL in --webhook")
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&webhookUrl, "webhook", "", "POST the results as JSON to a URL when the measurement finishes, Slack incoming webhooks are supported")
+	rootCmd.PersistentFlags().StringVar(&notify, "notify", "", "Format the webhook payload for a chat service (slack)")
+	rootCmd.PersistentFlags().BoolVar(&webhookOnFailure, "webhook-on-failure", false, "Only call the webhook when a probe fails or a threshold is breached (default false)")
+}
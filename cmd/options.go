@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// validateProtocol checks the --protocol flag against the values accepted by the API for a measurement type and
+// returns it in upper case, an empty protocol uses the API default
+func validateProtocol(value string, allowed ...string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	p := strings.ToUpper(value)
+	for _, a := range allowed {
+		if p == a {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid protocol %q, supported protocols are %s", value, strings.Join(allowed, ", "))
+}
+
+// validatePort checks the --port flag, zero uses the API default
+func validatePort(port int) error {
+	if port < 0 || port > 65535 {
+		return fmt.Errorf("invalid port %d, must be between 1 and 65535", port)
+	}
+	return nil
+}
+
+// validatePackets checks the --packets flag against the limits of the API, zero uses the API default
+func validatePackets(packets int) error {
+	if packets < 0 || packets > 16 {
+		return fmt.Errorf("invalid number of packets %d, must be between 1 and 16", packets)
+	}
+	return nil
+}
+
+// ipVersion returns the ipVersion option selected with -4/-6, zero lets the probe choose
+func ipVersion(target string) (int, error) {
+	if !ipv4 && !ipv6 {
+		return 0, nil
+	}
+	if ipv4 && ipv6 {
+		return 0, errors.New("-4 and -6 cannot be used together")
+	}
+	if net.ParseIP(target) != nil {
+		return 0, errors.New("-4 and -6 can only be used with a hostname target")
+	}
+	if ipv6 {
+		return 6, nil
+	}
+	return 4, nil
+}
+
+// parseExclusions returns the locations negated with ! in --from and the ones listed in --exclude
+func parseExclusions(from string, exclude string) []string {
+	var excluded []string
+	for _, v := range strings.Split(from, ",") {
+		v = strings.TrimSpace(v)
+		if strings.HasPrefix(v, "!") {
+			excluded = append(excluded, strings.TrimSpace(v[1:]))
+		}
+	}
+	for _, v := range strings.Split(exclude, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			excluded = append(excluded, v)
+		}
+	}
+	return excluded
+}
+
+// checkExclusions fails when locations are excluded since the API only accepts positive location filters
+func checkExclusions(from string, exclude string) error {
+	excluded := parseExclusions(from, exclude)
+	if len(excluded) == 0 {
+		return nil
+	}
+	return fmt.Errorf("excluding locations (%s) is not supported by the Globalping API - combine filters with + to narrow the locations instead, e.g. \"Europe+AS3320\"", strings.Join(excluded, ", "))
+}
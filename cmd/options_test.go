@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProtocol(t *testing.T) {
+	p, err := validateProtocol("tcp", "ICMP", "TCP", "UDP")
+	assert.NoError(t, err)
+	assert.Equal(t, "TCP", p)
+
+	p, err = validateProtocol("", "ICMP", "TCP", "UDP")
+	assert.NoError(t, err)
+	assert.Equal(t, "", p)
+
+	_, err = validateProtocol("sctp", "ICMP", "TCP", "UDP")
+	assert.EqualError(t, err, `invalid protocol "sctp", supported protocols are ICMP, TCP, UDP`)
+}
+
+func TestValidatePortAndPackets(t *testing.T) {
+	assert.NoError(t, validatePort(0))
+	assert.NoError(t, validatePort(443))
+	assert.EqualError(t, validatePort(70000), "invalid port 70000, must be between 1 and 65535")
+
+	assert.NoError(t, validatePackets(16))
+	assert.EqualError(t, validatePackets(17), "invalid number of packets 17, must be between 1 and 16")
+}
+
+func TestBuildMtrMeasurement(t *testing.T) {
+	t.Cleanup(func() {
+		protocol, port, packets = "", 0, 0
+	})
+
+	protocol, port, packets = "udp", 53, 5
+	m, err := buildMtrMeasurement()
+	assert.NoError(t, err)
+	assert.Equal(t, "UDP", m.Options.Protocol)
+	assert.Equal(t, 53, m.Options.Port)
+	assert.Equal(t, 5, m.Options.Packets)
+
+	protocol = "icmp"
+	_, err = buildMtrMeasurement()
+	assert.EqualError(t, err, "--port is only supported with the TCP or UDP protocol")
+}
+
+func TestBuildTracerouteMeasurement(t *testing.T) {
+	t.Cleanup(func() {
+		protocol, port = "", 0
+	})
+
+	protocol, port = "tcp", 443
+	m, err := buildTracerouteMeasurement()
+	assert.NoError(t, err)
+	assert.Equal(t, "TCP", m.Options.Protocol)
+	assert.Equal(t, 443, m.Options.Port)
+
+	protocol = "udp"
+	_, err = buildTracerouteMeasurement()
+	assert.EqualError(t, err, "--port is only supported with the TCP protocol")
+
+	protocol, port = "gre", 0
+	_, err = buildTracerouteMeasurement()
+	assert.EqualError(t, err, `invalid protocol "gre", supported protocols are ICMP, TCP, UDP`)
+}
+
+func TestIPVersion(t *testing.T) {
+	t.Cleanup(func() {
+		ipv4, ipv6 = false, false
+	})
+
+	v, err := ipVersion("google.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, v)
+
+	ipv6 = true
+	v, err = ipVersion("google.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 6, v)
+
+	_, err = ipVersion("1.1.1.1")
+	assert.EqualError(t, err, "-4 and -6 can only be used with a hostname target")
+
+	ipv4 = true
+	_, err = ipVersion("google.com")
+	assert.EqualError(t, err, "-4 and -6 cannot be used together")
+}
+
+func TestIPVersionBuilders(t *testing.T) {
+	t.Cleanup(func() {
+		ipv4, ipv6, resolver = false, false, ""
+		ctx = model.Context{}
+	})
+
+	ipv6 = true
+	ctx = model.Context{Target: "https://example.com/path", From: "world", Limit: 1}
+	m, err := buildHttpMeasurementRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, 6, m.Options.IPVersion)
+
+	ctx.Target = "example.com"
+	_, err = buildDnsMeasurement()
+	assert.EqualError(t, err, "-4 and -6 require a hostname --resolver for dns measurements")
+
+	resolver = "dns.google"
+	m, err = buildDnsMeasurement()
+	assert.NoError(t, err)
+	assert.Equal(t, 6, m.Options.IPVersion)
+}
+
+func TestCheckExclusions(t *testing.T) {
+	assert.NoError(t, checkExclusions("Europe,Germany", ""))
+	assert.Equal(t, []string{"Russia", "comcast"}, parseExclusions("Europe, !Russia", "comcast"))
+	assert.EqualError(t, checkExclusions("Europe,!Russia", ""), `excluding locations (Russia) is not supported by the Globalping API - combine filters with + to narrow the locations instead, e.g. "Europe+AS3320"`)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/pterm/pterm"
+)
+
+// canPickLocation returns true if the location can be asked interactively, the terminal must be used for both input and
+// output and nothing else must be read from stdin
+func canPickLocation() bool {
+	if os.Getenv("CI") != "" || readStdin || ctx.JsonOutput || ctx.Format != "" || ctx.Quiet {
+		return false
+	}
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		o, err := f.Stat()
+		if err != nil || (o.Mode()&os.ModeCharDevice) != os.ModeCharDevice {
+			return false
+		}
+	}
+	return true
+}
+
+// pickLocation asks for the location of the measurement with a fuzzy searchable list of the locations of the online
+// probes, "world" is returned if the probes cannot be fetched
+func pickLocation() string {
+	probes, err := client.GetProbes(runCtx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return "world"
+	}
+
+	options := []string{fmt.Sprintf("world (%d probes)", len(probes))}
+	keywords := map[string]string{options[0]: "world"}
+	for _, l := range client.OnlineLocations(probes) {
+		label := fmt.Sprintf("%s (%s, %d probes)", l.Keyword, l.Category, l.Probes)
+		options = append(options, label)
+		keywords[label] = l.Keyword
+	}
+
+	selected, err := pterm.DefaultInteractiveSelect.
+		WithOptions(options).
+		WithMaxHeight(10).
+		Show("Select the location of the probes")
+	if err != nil {
+		return "world"
+	}
+	return keywords[selected]
+}
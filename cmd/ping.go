@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/jsdelivr/globalping-cli/client"
@@ -10,7 +11,7 @@ import (
 
 // pingCmd represents the ping command
 var pingCmd = &cobra.Command{
-	Use:     "ping [target] from [location]",
+	Use:     "ping [target...] from [location]",
 	GroupID: "Measurements",
 	Short:   "Use the native ping command",
 	Long: `The ping command sends an ICMP ECHO_REQUEST to obtain an ICMP ECHO_RESPONSE from a host or gateway.
@@ -26,7 +27,59 @@ Examples:
   ping jsdelivr.com from aws+montreal --latency
 
   # Ping jsdelivr.com with ASN 12345 with json output
-  ping jsdelivr.com from 12345 --json`,
+  ping jsdelivr.com from 12345 --json
+
+  # Ping the IPv6 address of google.com with 10 packets
+  ping google.com from Europe -6 --packets 10
+
+  # Ping several targets from 2 probes in Europe
+  ping google.com cloudflare.com from Europe --limit 2
+
+  # Ping every target listed in hosts.txt
+  ping --targets-file hosts.txt from Europe
+
+  # Ping targets read from stdin, 10 at a time
+  cat hosts.txt | ping --stdin --from aws --parallel 10
+
+  # Exit with a non-zero code if any probe has a latency above 100ms or more than 5% packet loss
+  ping google.com from Europe --limit 10 --max-latency 100ms --max-loss 5
+
+  # Print one tab separated line per probe with its country, city, network and average latency, sorted by latency
+  ping google.com from world --limit 50 --format brief | sort -t$'\t' -k4 -n
+
+  # Ping google.com from 100 probes and only display the 5 slowest ones in Germany
+  ping google.com from world --limit 100 --filter country=DE --worst 5
+
+  # Ping google.com from 200 probes and display the latency, packet loss and success rate of every country
+  ping google.com from world --limit 200 --group-by country
+
+  # Print the average latency of every probe, without installing jq
+  ping google.com from Europe --limit 5 --jq '.results[].result.stats.avg'
+
+  # Print the country and average latency of every probe with a Go template
+  ping google.com from Europe --limit 5 --template '{{.Probe.Country}} {{.Stats.Avg}}ms'
+
+  # Copy a markdown table of the results to the clipboard, e.g. to paste it in a ticket
+  ping google.com from Europe --limit 5 --copy=markdown
+
+  # Exit with a non-zero code if any probe fails, or with =all only when every probe fails
+  ping google.com from Europe --limit 10 --fail-on-probe-errors
+
+  # Report probes over 100ms as GitHub Actions annotations and write a job summary
+  ping google.com from Europe --limit 10 --max-latency 100ms --ci=github
+
+  # Record a baseline from 10 probes, then fail later runs from the same probes that are 20% slower
+  ping google.com from Europe --limit 10 --baseline save:google
+  ping google.com --from-measurement last --baseline compare:google --tolerance 20
+
+  # Print the request body and the cost of a measurement without creating it, e.g. to post it with curl
+  ping google.com from Germany:2,Japan:3 --dry-run
+
+  # Ping google.com from the same 3 probes every 10 seconds and highlight changes
+  ping google.com from Europe --limit 3 --watch --interval 10s
+
+  # Continuously ping google.com from a probe in Germany until interrupted
+  ping google.com from Germany --infinite`,
 	Args: checkCommandFormat(),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Create context
@@ -35,37 +88,116 @@ Examples:
 			return err
 		}
 
-		// Make post struct
-		opts = model.PostMeasurement{
-			Type:      "ping",
-			Target:    ctx.Target,
-			Locations: createLocations(ctx.From),
-			Limit:     ctx.Limit,
-			Options: &model.MeasurementOptions{
-				Packets: packets,
-			},
-		}
-
-		res, showHelp, err := client.PostAPI(opts)
-		if err != nil {
-			if showHelp {
+		if ctx.Infinite && !dryRun {
+			m, err := withValidLimit(withLocationLimits(buildPingMeasurement))()
+			if err != nil {
 				return err
 			}
-			fmt.Println(err)
-			return nil
+			opts = m
+			return pingInfinite()
 		}
 
-		client.OutputResults(res.ID, ctx)
-		return nil
+		return runMeasurements(buildPingMeasurement)
 	},
 }
 
+// pingInfinite repeatedly runs ping measurements from the same probes and streams per-packet lines until interrupted
+func pingInfinite() error {
+	agg := client.NewPingAggregator()
+	errCh := make(chan error, 1)
+
+	go func() {
+		for {
+			res, err := client.PostAPI(runCtx, opts)
+			if err != nil {
+				// Interrupted by the user, the summary is printed below
+				if errors.Is(err, client.ErrInterrupted) {
+					err = nil
+				} else {
+					err = postError(err)
+				}
+				errCh <- err
+				return
+			}
+
+			// Reuse the probes of the first measurement for every following iteration
+			opts.Locations = []model.Locations{{Magic: res.ID}}
+
+			data, err := client.WaitForResults(runCtx, res.ID)
+			if err != nil {
+				if !errors.Is(err, client.ErrInterrupted) {
+					fmt.Println(err)
+				}
+				errCh <- nil
+				return
+			}
+
+			// Interrupted while the results were fetched, they are left out of the summary
+			if runCtx.Err() != nil {
+				errCh <- nil
+				return
+			}
+
+			lines, err := agg.Add(data)
+			if err != nil {
+				fmt.Println(err)
+				errCh <- nil
+				return
+			}
+			if lines != "" {
+				fmt.Println(lines)
+			}
+		}
+	}()
+
+	select {
+	case <-runCtx.Done():
+		// Wait for the measurement in flight so no packet line is printed after the summary
+		<-errCh
+	case err := <-errCh:
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(agg.Summary())
+	return nil
+}
+
+// buildPingMeasurement builds the measurement request for the ping type
+func buildPingMeasurement() (model.PostMeasurement, error) {
+	if err := validatePackets(packets); err != nil {
+		return model.PostMeasurement{}, err
+	}
+	v, err := ipVersion(ctx.Target)
+	if err != nil {
+		return model.PostMeasurement{}, err
+	}
+
+	return model.PostMeasurement{
+		Type:      "ping",
+		Target:    ctx.Target,
+		Locations: createLocations(ctx.From),
+		Limit:     ctx.Limit,
+		Options: &model.MeasurementOptions{
+			Packets:   packets,
+			IPVersion: v,
+		},
+	}, nil
+}
+
 func init() {
 	rootCmd.AddCommand(pingCmd)
 
 	// ping specific flags
-	pingCmd.Flags().IntVar(&packets, "packets", 0, "Specifies the desired amount of ECHO_REQUEST packets to be sent (default 3)")
+	pingCmd.Flags().IntVar(&packets, "packets", 0, "Specifies the desired amount of ECHO_REQUEST packets to be sent, between 1 and 16 (default 3)")
+
+	// Threshold flags
+	pingCmd.Flags().DurationVar(&ctx.Thresholds.MaxLatency, "max-latency", 0, "Exit with a non-zero code if the latency of any probe exceeds the given duration, e.g. 100ms")
+	pingCmd.Flags().Var(&maxLossValue{}, "max-loss", "Exit with a non-zero code if the packet loss of any probe exceeds the given percentage, 0 fails on any loss")
 
 	// Extra flags
 	pingCmd.Flags().BoolVar(&ctx.Latency, "latency", false, "Output only the stats of a measurement (default false)")
+	pingCmd.Flags().BoolVar(&ctx.Infinite, "infinite", false, "Keep pinging the target until interrupted, then print a summary (default false)")
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+var probeFilter client.ProbeFilter
+
+// probesCmd represents the probes command
+var probesCmd = &cobra.Command{
+	Use:   "probes",
+	Short: "List the probes currently online",
+	Long: `The probes command lists the probes that are currently online and can be used to run measurements.
+
+Examples:
+  # List all online probes
+  probes
+
+  # List probes in Germany
+  probes --country DE
+
+  # List probes in Europe on the AWS network with json output
+  probes --continent EU --network amazon --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		probes, err := client.GetProbes(runCtx)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		probes = client.FilterProbes(probes, probeFilter)
+
+		if ctx.JsonOutput {
+			b, err := json.MarshalIndent(probes, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+
+		printProbesTable(probes)
+		return nil
+	},
+}
+
+func printProbesTable(probes []model.Probe) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTINENT\tCOUNTRY\tSTATE\tCITY\tASN\tNETWORK\tTAGS")
+	for _, p := range probes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", p.Location.Continent, p.Location.Country, p.Location.State, p.Location.City, p.Location.ASN, p.Location.Network, strings.Join(p.Tags, ","))
+	}
+	w.Flush()
+	fmt.Printf("\n%d probes online\n", len(probes))
+}
+
+func init() {
+	rootCmd.AddCommand(probesCmd)
+
+	probesCmd.Flags().StringVar(&probeFilter.Continent, "continent", "", "Only list probes in the given continent code (e.g. EU)")
+	probesCmd.Flags().StringVar(&probeFilter.Country, "country", "", "Only list probes in the given country code (e.g. DE)")
+	probesCmd.Flags().StringVar(&probeFilter.Network, "network", "", "Only list probes whose network name contains the given text")
+	probesCmd.Flags().IntVar(&probeFilter.ASN, "asn", 0, "Only list probes in the given ASN")
+	probesCmd.Flags().StringVar(&probeFilter.Tag, "tag", "", "Only list probes with the given tag (e.g. aws-eu-central-1)")
+}
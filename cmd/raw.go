@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+var rawFile string
+
+// rawCmd represents the raw command
+var rawCmd = &cobra.Command{
+	Use:   "raw",
+	Short: "Post a measurement written as the JSON body of the Globalping API",
+	Long: `The raw command posts a measurement written in JSON as is, so options of the Globalping API without a flag in the CLI yet can be used. The body is read from --file, or from stdin without it, and the results are output like the measurement commands, with every output flag like --format, --json or --output.
+The body is the one of POST https://api.globalping.io/v1/measurements, e.g.:
+
+  {
+    "type": "http",
+    "target": "jsdelivr.com",
+    "locations": [{ "magic": "Europe", "limit": 2 }],
+    "measurementOptions": { "request": { "method": "GET", "path": "/" } }
+  }
+
+Examples:
+  # Post the measurement of spec.json
+  raw --file spec.json
+
+  # Post a measurement from stdin and print the results as JSON
+  echo '{"type":"ping","target":"jsdelivr.com"}' | raw --json
+
+  # Check the body of a measurement built with the flags of the CLI, then post it
+  ping jsdelivr.com from Germany --dry-run > spec.json && raw --file spec.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !client.ValidFormat(ctx.Format) {
+			return fmt.Errorf("unknown format %q - supported formats: %s", ctx.Format, strings.Join(client.FormatNames(), ", "))
+		}
+
+		body, err := readRawMeasurement(rawFile)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		// Decoded for the output only, the body is posted as is
+		var m model.PostMeasurement
+		err = json.Unmarshal(body, &m)
+		if err != nil {
+			fmt.Printf("err: invalid measurement: %s\n", err)
+			return nil
+		}
+		if m.Type == "" || m.Target == "" {
+			fmt.Println("err: invalid measurement: the type and the target are required")
+			return nil
+		}
+		// Defaults of the API
+		if m.Limit == 0 {
+			m.Limit = 1
+		}
+		if len(m.Locations) == 0 {
+			m.Locations = []model.Locations{{Magic: "world"}}
+		}
+
+		if dryRun {
+			fmt.Println(string(body))
+			previewCost([]model.PostMeasurement{m})
+			return nil
+		}
+
+		ctx.Cmd = m.Type
+		ctx.Target = m.Target
+		ctx.Targets = []string{m.Target}
+		from := make([]string, len(m.Locations))
+		for i, l := range m.Locations {
+			from[i] = l.Magic
+		}
+		ctx.From = strings.Join(from, ",")
+		detectCI()
+
+		res, err := client.PostRawAPI(runCtx, body)
+		if err != nil {
+			return postError(err)
+		}
+		outputMeasurement(res.ID, m.Type)
+		return nil
+	},
+}
+
+// readRawMeasurement reads the JSON body of a measurement from a file, or from stdin if the path is empty or "-"
+func readRawMeasurement(path string) ([]byte, error) {
+	var b []byte
+	var err error
+	if path == "" || path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+		path = "stdin"
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err: failed to read %s", path)
+	}
+
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil, fmt.Errorf("err: %s is empty", path)
+	}
+	return b, nil
+}
+
+func init() {
+	rootCmd.AddCommand(rawCmd)
+	rawCmd.Flags().StringVarP(&rawFile, "file", "f", "", "JSON file of the measurement, \"-\" or no file reads stdin")
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRawMeasurement(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.json")
+	assert.NoError(t, os.WriteFile(path, []byte("\n{\"type\":\"ping\",\"target\":\"jsdelivr.com\"}\n"), 0o644))
+
+	b, err := readRawMeasurement(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"ping","target":"jsdelivr.com"}`, string(b))
+
+	empty := filepath.Join(dir, "empty.json")
+	assert.NoError(t, os.WriteFile(empty, []byte(" \n"), 0o644))
+	_, err = readRawMeasurement(empty)
+	assert.EqualError(t, err, "err: "+empty+" is empty")
+
+	_, err = readRawMeasurement(filepath.Join(dir, "missing.json"))
+	assert.EqualError(t, err, "err: failed to read "+filepath.Join(dir, "missing.json"))
+}
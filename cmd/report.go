@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report [id|target|file]",
+	Short: "Generate a standalone HTML report of a measurement",
+	Long: `The report command generates a standalone HTML page with a table of the results of every probe, a map of the probes and a latency chart, suitable for attaching to an incident ticket. The styles and scripts are inlined, the page works offline.
+The measurement is the ID of a past measurement, a JSON file saved with --json or --output results.json, or a target of the local history, in which case its latest measurement is used.
+The report is printed to stdout unless written to a file with --output.
+
+Examples:
+  # Save the report of measurement UKbdVoWpIr6ec0cy
+  report UKbdVoWpIr6ec0cy --format html -o report.html
+
+  # Report the latest measurement of jsdelivr.com
+  report jsdelivr.com -o report.html`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if ctx.Format != "" && ctx.Format != "html" {
+			return fmt.Errorf("unsupported report format %q, supported formats are html", ctx.Format)
+		}
+		ctx.Format = "html"
+
+		data, err := loadReport(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		ctx.Cmd = data.Type
+		if data.Target != "" {
+			ctx.Target = data.Target
+		}
+		client.OutputFinished(runCtx, data.ID, data, ctx)
+		return nil
+	},
+}
+
+// loadReport reads a saved measurement if the argument is a file, fetches the latest measurement of the target if it
+// is in the history, or fetches the argument as a measurement ID
+func loadReport(arg string) (model.GetMeasurement, error) {
+	if _, err := os.Stat(arg); err == nil {
+		return client.LoadMeasurement(arg)
+	}
+
+	entries, err := history.List(history.Filter{Target: arg})
+	if err != nil {
+		return model.GetMeasurement{}, err
+	}
+	// The filter matches targets containing the argument, only an exact match is used
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Target == arg {
+			ctx.Target = arg
+			return client.GetAPI(runCtx, entries[i].ID)
+		}
+	}
+	return client.GetAPI(runCtx, arg)
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+}
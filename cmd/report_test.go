@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadReport(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"` + filepath.Base(r.URL.Path) + `","type":"ping","status":"finished","results":[]}`))
+	}))
+	defer server.Close()
+
+	apiUrl := client.ApiUrl
+	client.ApiUrl = server.URL
+	path := history.Path
+	history.Path = filepath.Join(t.TempDir(), "history.json")
+	t.Cleanup(func() {
+		client.ApiUrl = apiUrl
+		history.Path = path
+		ctx = model.Context{}
+	})
+
+	assert.NoError(t, history.Add(history.Entry{ID: "first", Type: "ping", Target: "jsdelivr.com", CreatedAt: time.Now()}))
+	assert.NoError(t, history.Add(history.Entry{ID: "latest", Type: "ping", Target: "jsdelivr.com", CreatedAt: time.Now()}))
+	assert.NoError(t, history.Add(history.Entry{ID: "other", Type: "ping", Target: "cdn.jsdelivr.com", CreatedAt: time.Now()}))
+
+	// A target of the history selects its latest measurement
+	data, err := loadReport("jsdelivr.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "latest", data.ID)
+	assert.Equal(t, "jsdelivr.com", ctx.Target)
+
+	// Anything else is a measurement ID
+	data, err = loadReport("UKbdVoWpIr6ec0cy")
+	assert.NoError(t, err)
+	assert.Equal(t, "UKbdVoWpIr6ec0cy", data.ID)
+	assert.Equal(t, []string{"/measurements/latest", "/measurements/UKbdVoWpIr6ec0cy"}, requested)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// rerunCmd represents the rerun command
+var rerunCmd = &cobra.Command{
+	Use:   "rerun [id]",
+	Short: "Run a previous measurement again and compare the results",
+	Long: `The rerun command fetches a past measurement, posts it again with the same target, locations, limit and options, and prints the results of both runs side by side.
+
+Examples:
+  # Run measurement UKbdVoWpIr6ec0cy again
+  rerun UKbdVoWpIr6ec0cy`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		orig, err := client.GetAPI(runCtx, args[0])
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		entry, _, _ := history.Find(orig.ID)
+		m, err := buildRerunMeasurement(orig, entry)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return dryRunMeasurements([]model.PostMeasurement{m})
+		}
+
+		opts = m
+		ctx.Cmd = m.Type
+		ctx.Target = m.Target
+		ctx.From = entry.From
+
+		res, err := client.PostAPI(runCtx, opts)
+		if err != nil {
+			return postError(err)
+		}
+		recordHistory(res.ID, m.Type, m.Target)
+
+		data, err := client.WaitForResults(runCtx, res.ID)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		fmt.Println(client.CompareResults(m.Type, orig, data, orig.ID, data.ID))
+		return nil
+	},
+}
+
+// buildRerunMeasurement reconstructs the original measurement request, using the history entry when the API response lacks it
+func buildRerunMeasurement(orig model.GetMeasurement, entry history.Entry) (model.PostMeasurement, error) {
+	m := model.PostMeasurement{
+		Type:      orig.Type,
+		Target:    orig.Target,
+		Locations: orig.Locations,
+		Limit:     orig.Limit,
+		Options:   orig.MeasurementOptions,
+	}
+
+	if m.Target == "" {
+		m.Target = entry.Target
+	}
+	if m.Target == "" {
+		return m, errors.New("unable to find the target of the original measurement")
+	}
+
+	if len(m.Locations) == 0 && entry.From != "" {
+		m.Locations = createLocations(entry.From)
+	}
+
+	// Keep the same amount of probes as the original run
+	if m.Limit == 0 {
+		m.Limit = len(orig.Results)
+	}
+
+	return m, nil
+}
+
+func init() {
+	rootCmd.AddCommand(rerunCmd)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildRerunMeasurement(t *testing.T) {
+	orig := model.GetMeasurement{
+		Type:               "ping",
+		Target:             "google.com",
+		Limit:              2,
+		Locations:          []model.Locations{{Country: "DE"}},
+		MeasurementOptions: &model.MeasurementOptions{Packets: 5},
+	}
+
+	m, err := buildRerunMeasurement(orig, history.Entry{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.PostMeasurement{
+		Type:      "ping",
+		Target:    "google.com",
+		Limit:     2,
+		Locations: []model.Locations{{Country: "DE"}},
+		Options:   &model.MeasurementOptions{Packets: 5},
+	}, m)
+}
+
+func TestBuildRerunMeasurementFromHistory(t *testing.T) {
+	orig := model.GetMeasurement{
+		Type:    "dns",
+		Results: []model.MeasurementResponse{{}, {}, {}},
+	}
+
+	m, err := buildRerunMeasurement(orig, history.Entry{Target: "jsdelivr.com", From: "Europe, Asia"})
+	assert.NoError(t, err)
+	assert.Equal(t, "jsdelivr.com", m.Target)
+	assert.Equal(t, []model.Locations{{Magic: "Europe"}, {Magic: "Asia"}}, m.Locations)
+	assert.Equal(t, 3, m.Limit)
+
+	_, err = buildRerunMeasurement(orig, history.Entry{})
+	assert.Error(t, err)
+}
@@ -1,11 +1,23 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/config"
 	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/runner"
 	"github.com/spf13/cobra"
 )
 
@@ -14,21 +26,67 @@ var (
 	// cfgFile string
 
 	// Additional flags
-	packets   int
-	protocol  string
-	port      int
-	resolver  string
-	trace     bool
-	queryType string
-	path      string
-	host      string
-	query     string
-	method    string
-	// TODO: headers   map[string]string
+	packets      int
+	protocol     string
+	port         int
+	resolver     string
+	resolverList string
+	trace        bool
+	dnssec       bool
+	short        bool
+	answerFilter string
+	queryType    string
+	path         string
+	host         string
+	query        string
+	method       string
+	headers      []string
+	rawOutput    bool
+	ipv4         bool
+	ipv6         bool
+
+	expectHeaders   []string
+	followRedirects bool
+	maxRedirects    int
+	propagation     bool
+	expect          string
+
+	apiEnv string
+
+	targetsFile string
+	readStdin   bool
+	parallel    int
+	dryRun      bool
+	exclude     string
+
+	fromMeasurement string
+	logNdjson       string
+
+	resultFilters []string
+	templateFile  string
+
+	proxy    string
+	caCert   string
+	insecure bool
+
+	noColor   bool
+	noCache   bool
+	verbose   bool
+	debugHTTP bool
+
+	// Location aliases of the config file
+	locationAliases map[string]string
 
 	opts    = model.PostMeasurement{}
 	ctx     = model.Context{}
 	version string
+	commit  string
+
+	// runCtx is cancelled on Ctrl-C to stop in-flight requests and polling
+	runCtx = context.Background()
+
+	// exitCode is set by commands that complete but need to report a failure, e.g. when one of several targets failed
+	exitCode int
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -37,79 +95,831 @@ var rootCmd = &cobra.Command{
 	Short: "A global network of probes to run network tests like ping, traceroute and DNS resolve.",
 	Long: `Globalping is a platform that allows anyone to run networking commands such as ping, traceroute, dig and mtr on probes distributed all around the world. 
 	The CLI tool allows you to interact with the API in a simple and human-friendly way to debug networking issues like anycast routing and script automated tests and benchmarks.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyConfig(cmd); err != nil {
+			return err
+		}
+		client.SetColor(client.ColorEnabled(noColor))
+		client.UseCache = !noCache
+		if err := setLogLevel(); err != nil {
+			return err
+		}
+		if err := parseSelection(); err != nil {
+			return err
+		}
+		if err := validateExport(); err != nil {
+			return err
+		}
+		if ctx.Query != "" {
+			if _, err := client.ParseQuery(ctx.Query); err != nil {
+				return err
+			}
+		}
+		if err := loadTemplate(); err != nil {
+			return err
+		}
+		return configureTransport()
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute(ver string) {
+func Execute(ver string, rev string) {
 	version = ver
+	commit = rev
 
 	rootCmd.AddGroup(&cobra.Group{ID: "Measurements", Title: "Measurement Commands:"})
+
+	c, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	runCtx = c
 	err := rootCmd.Execute()
+	stop()
 	if err != nil {
 		os.Exit(1)
 	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
 }
 
 func init() {
 	// Global flags
-	rootCmd.PersistentFlags().StringVarP(&ctx.From, "from", "F", "", "A continent, region (e.g eastern europe), country, US state or city (default \"world\")")
-	rootCmd.PersistentFlags().IntVarP(&ctx.Limit, "limit", "L", 1, "Limit the number of probes to use")
+	rootCmd.PersistentFlags().StringVarP(&ctx.From, "from", "F", "", "A continent, region (e.g eastern europe), country, US state or city, a comma separated group can set its own limit (e.g Germany:3,US:5) (default \"world\", picked interactively in a terminal)")
+	rootCmd.RegisterFlagCompletionFunc("from", completeFromFlag)
+	rootCmd.PersistentFlags().StringVar(&fromMeasurement, "from-measurement", "", "Use the probes of a previous measurement, given by its ID or \"last\" for the most recent one")
+	rootCmd.PersistentFlags().StringVar(&exclude, "exclude", "", "Locations or networks to leave out, also written as !location in --from")
+	rootCmd.PersistentFlags().IntVarP(&ctx.Limit, "limit", "L", 1, "Limit the number of probes to use, at most 500")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the JSON body of the measurement, the probes it would use and its cost without creating it (default false)")
 	rootCmd.PersistentFlags().BoolVarP(&ctx.JsonOutput, "json", "J", false, "Output results in JSON format (default false)")
-	rootCmd.PersistentFlags().BoolVarP(&ctx.CI, "ci", "C", false, "Disable realtime terminal updates and color suitable for CI (default false)")
+	ciFlag := rootCmd.PersistentFlags().VarPF(&ciValue{}, "ci", "C", "Disable realtime terminal updates and color suitable for CI, --ci=github also prints workflow annotations and a job summary (default false)")
+	ciFlag.NoOptDefVal = "true"
+	failFlag := rootCmd.PersistentFlags().VarPF(&failPolicyValue{}, "fail-on-probe-errors", "", "Exit with a non-zero code if any probe fails, --fail-on-probe-errors=all only if every probe fails and =none never (default none)")
+	failFlag.NoOptDefVal = client.FailPolicyAny
+	rootCmd.PersistentFlags().BoolVarP(&ipv4, "ipv4", "4", false, "Resolve hostname targets to an IPv4 address (default false)")
+	rootCmd.PersistentFlags().BoolVarP(&ipv6, "ipv6", "6", false, "Resolve hostname targets to an IPv6 address, only probes with IPv6 connectivity are used (default false)")
+	rootCmd.PersistentFlags().BoolVar(&ctx.Watch, "watch", false, "Run the measurement again every interval and highlight significant changes (default false)")
+	rootCmd.PersistentFlags().DurationVar(&ctx.Interval, "interval", 30*time.Second, "Time between two runs in watch mode")
+	rootCmd.PersistentFlags().StringVar(&targetsFile, "targets-file", "", "Read additional targets from a file, one per line")
+	rootCmd.PersistentFlags().BoolVar(&readStdin, "stdin", false, "Read additional targets from stdin, one per line (default false)")
+	rootCmd.PersistentFlags().IntVar(&parallel, "parallel", 5, "Maximum number of measurements running at the same time when measuring several targets")
+	rootCmd.PersistentFlags().BoolVar(&ctx.Share, "share", false, "Print the globalping.io URL of the results and copy it to the clipboard (default false)")
+	copyFlag := rootCmd.PersistentFlags().VarPF(&copyValue{}, "copy", "", "Copy the share URL of the results to the clipboard, --copy=json copies the JSON results and --copy=markdown a markdown table")
+	copyFlag.NoOptDefVal = client.CopyUrl
+	rootCmd.PersistentFlags().StringVar(&ctx.Selection.Sort, "sort", "", "Order the probes displayed by latency, country or network (default API order)")
+	rootCmd.PersistentFlags().StringArrayVar(&resultFilters, "filter", nil, "Only display the probes matching a field, e.g. country=DE or network=comcast, several values are separated by commas, can be repeated")
+	rootCmd.PersistentFlags().IntVar(&ctx.Selection.Top, "top", 0, "Only display the given number of probes with the lowest latency")
+	rootCmd.PersistentFlags().IntVar(&ctx.Selection.Worst, "worst", 0, "Only display the given number of probes with the highest latency, failed probes first")
+	rootCmd.PersistentFlags().StringVar(&ctx.GroupBy, "group-by", "", "Display the mean and median latency, packet loss and success rate of the probes grouped by country, continent, asn or network")
+	rootCmd.PersistentFlags().StringVar(&ctx.Query, "jq", "", "Print the values selected by a jq query on the results instead of the results, e.g. '.results[].result.stats.avg'")
+	rootCmd.PersistentFlags().StringVar(&ctx.Template, "template", "", "Print every probe with a Go template instead of the results, e.g. '{{.Probe.Country}} {{.Stats.Avg}}ms'")
+	rootCmd.PersistentFlags().StringVar(&templateFile, "template-file", "", "Print every probe with the Go template of a file instead of the results")
+	rootCmd.PersistentFlags().BoolVar(&ctx.Summary, "summary", false, "Print min/median/p95/max latency and the packet loss across all probes (default false)")
+	rootCmd.PersistentFlags().BoolVar(&ctx.Live, "live", false, "Print the output of the probes line by line as it arrives, e.g. to follow long traceroute and mtr measurements (default false)")
+	rootCmd.PersistentFlags().BoolVar(&ctx.Map, "map", false, "Print a world map of the continents with the median latency of every region, color coded (default false)")
+	rootCmd.PersistentFlags().StringVarP(&ctx.Output, "output", "o", "", "Write the results to a file in the selected output, a .json file defaults to JSON, \"-\" is stdout. Several targets are written to one file each, named after the target")
+	rootCmd.PersistentFlags().StringVar(&logNdjson, "log-ndjson", "", "Append one JSON line per probe result to a file, e.g. to collect trends from cron jobs")
+	rootCmd.PersistentFlags().DurationVar(&client.ConnectTimeout, "connect-timeout", 10*time.Second, "Timeout of opening a connection to the API, 0 means no timeout")
+	rootCmd.PersistentFlags().DurationVar(&client.Wait, "wait", 0, "Maximum time to wait for the measurement to finish, the results received so far are then printed and marked incomplete, e.g. 60s (default no limit)")
+	rootCmd.PersistentFlags().DurationVar(&client.Timeout, "timeout", 0, "Timeout of every API request, e.g. 30s, overrides the timeout of the config file (default no timeout)")
+	rootCmd.PersistentFlags().StringVar(&apiEnv, "env", "", "Use a Globalping API defined in the config file with environments.<name>.url, e.g. staging, overrides the env key of the config file")
+	rootCmd.RegisterFlagCompletionFunc("env", completeEnvFlag)
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Always fetch measurements from the API instead of the cache of finished measurements (default false)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors, also disabled by the NO_COLOR environment variable and when the output is not a terminal (default false)")
+	rootCmd.PersistentFlags().StringVar(&proxy, "proxy", "", "Proxy URL of every request, e.g. http://proxy.example.com:3128 (default from HTTP_PROXY, HTTPS_PROXY and NO_PROXY)")
+	rootCmd.PersistentFlags().StringVar(&caCert, "cacert", "", "PEM file of CA certificates trusted in addition to the system ones, e.g. of a TLS intercepting proxy")
+	rootCmd.PersistentFlags().BoolVar(&insecure, "insecure", false, "Do not verify the TLS certificate of the API, only use it for debugging (default false)")
+	rootCmd.PersistentFlags().IntVar(&client.Retry.Count, "retries", 2, "Number of retries of API requests failing with a network error, a 5xx status or a 429 status with Retry-After")
+	rootCmd.PersistentFlags().DurationVar(&client.Retry.Delay, "retry-delay", 500*time.Millisecond, "Delay before the first retry, doubled for every following one")
+	rootCmd.PersistentFlags().Float64Var(&client.Retry.Jitter, "retry-jitter", 0.2, "Fraction of the retry delay randomized to spread the retries of concurrent clients")
+	rootCmd.PersistentFlags().BoolVarP(&ctx.Quiet, "quiet", "q", false, "Print only the final metrics of the measurement, the exit code reports failures (default false)")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log request payloads, polling attempts, retries and rate limit headers to stderr (default false)")
+	rootCmd.PersistentFlags().BoolVar(&debugHTTP, "debug", false, "Dump the raw HTTP traffic to stderr, credentials are redacted (default false)")
+	rootCmd.PersistentFlags().StringVar(&ctx.Format, "format", "", "Output results in an alternative format ("+strings.Join(client.FormatNames(), ", ")+")")
 }
 
 // checkCommandFormat checks if the command is in the correct format if using the from arg
 func checkCommandFormat() cobra.PositionalArgs {
 	return func(cmd *cobra.Command, args []string) error {
-		if len(args) > 1 && args[1] != "from" {
+		if len(args) > 0 && args[0] == "from" {
 			return errors.New("invalid command format")
 		}
 		return nil
 	}
 }
 
+// readTargetsFile reads one target per line, ignoring empty lines and # comments
+func readTargetsFile(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open targets file: %s", name)
+	}
+	defer f.Close()
+
+	return readTargets(f)
+}
+
+func readTargets(r io.Reader) ([]string, error) {
+	var targets []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New("failed to read targets")
+	}
+	return targets, nil
+}
+
 func createContext(cmd string, args []string) error {
 	ctx.Cmd = cmd // Get the command name
 
+	// Targets are all arguments before the from keyword
+	fromIdx := len(args)
+	for i, arg := range args {
+		if arg == "from" {
+			fromIdx = i
+			break
+		}
+	}
+	targets := append([]string{}, args[:fromIdx]...)
+
+	if readStdin {
+		stdinTargets, err := readTargets(os.Stdin)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, stdinTargets...)
+	}
+
+	if targetsFile != "" {
+		fileTargets, err := readTargetsFile(targetsFile)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, fileTargets...)
+	}
+
 	// Target
-	if len(args) == 0 {
+	if len(targets) == 0 {
 		return errors.New("provided target is empty")
 	}
-	ctx.Target = args[0]
+	ctx.Target = targets[0]
+	ctx.Targets = targets
+
+	// Output format
+	if !client.ValidFormat(ctx.Format) {
+		return fmt.Errorf("unknown format %q - supported formats: %s", ctx.Format, strings.Join(client.FormatNames(), ", "))
+	}
+
+	if ctx.Watch && ctx.Interval <= 0 {
+		return errors.New("interval must be greater than zero")
+	}
 
-	// If no from arg is provided, use the default value
-	if len(args) == 1 && ctx.From == "" {
-		ctx.From = "world"
+	// If no from arg is provided, ask for it in a terminal or use the default value
+	if fromIdx == len(args) && ctx.From == "" && fromMeasurement == "" {
+		if canPickLocation() {
+			ctx.From = pickLocation()
+		} else {
+			ctx.From = "world"
+		}
 	}
 
 	// If from args are provided, use it
-	if len(args) > 1 && args[1] == "from" {
-		ctx.From = strings.TrimSpace(strings.Join(args[2:], " "))
+	if fromIdx < len(args) {
+		ctx.From = strings.TrimSpace(strings.Join(args[fromIdx+1:], " "))
+	}
+
+	// Run from the same probes as a previous measurement
+	if fromMeasurement != "" {
+		if fromIdx < len(args) {
+			return errors.New("--from-measurement cannot be used together with a location")
+		}
+		id, err := resolveMeasurementID(fromMeasurement)
+		if err != nil {
+			return err
+		}
+		ctx.From = id
+	}
+
+	if err := expandFrom(); err != nil {
+		return err
+	}
+
+	if err := validateNotify(); err != nil {
+		return err
+	}
+
+	detectCI()
+	return nil
+}
+
+// expandFrom replaces the @aliases of the locations with their definition and checks the excluded locations
+func expandFrom() error {
+	from, err := config.ExpandLocations(ctx.From, locationAliases)
+	if err != nil {
+		return err
+	}
+	ctx.From = from
+	return checkExclusions(ctx.From, exclude)
+}
+
+// loadTemplate reads the template of --template-file and checks that the template of the output is valid
+func loadTemplate() error {
+	if templateFile != "" {
+		if ctx.Template != "" {
+			return errors.New("--template cannot be combined with --template-file")
+		}
+		b, err := os.ReadFile(templateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %s", templateFile)
+		}
+		ctx.Template = string(b)
 	}
+	if ctx.Template == "" {
+		return nil
+	}
+	_, err := client.ParseTemplate(ctx.Template)
+	return err
+}
 
+// parseSelection validates the flags selecting and grouping the displayed probes
+func parseSelection() error {
+	s := &ctx.Selection
+	if s.Sort != "" {
+		valid := false
+		for _, k := range client.SortKeys {
+			valid = valid || s.Sort == k
+		}
+		if !valid {
+			return fmt.Errorf("invalid --sort %q, must be one of %s", s.Sort, strings.Join(client.SortKeys, ", "))
+		}
+	}
+	if s.Top < 0 || s.Worst < 0 {
+		return errors.New("--top and --worst must be positive numbers")
+	}
+	if s.Top > 0 && s.Worst > 0 {
+		return errors.New("--top cannot be combined with --worst")
+	}
+
+	if ctx.GroupBy != "" {
+		valid := false
+		for _, k := range client.GroupByKeys {
+			valid = valid || ctx.GroupBy == k
+		}
+		if !valid {
+			return fmt.Errorf("invalid --group-by %q, must be one of %s", ctx.GroupBy, strings.Join(client.GroupByKeys, ", "))
+		}
+	}
+
+	filters, err := client.ParseFilters(resultFilters)
+	if err != nil {
+		return err
+	}
+	s.Filters = filters
+
+	// The JSON output is the measurement as sent by the API, it cannot be narrowed down
+	if ctx.JsonOutput && s.Enabled() {
+		return errors.New("--sort, --filter, --top and --worst cannot be combined with --json, use --jq to select results")
+	}
+	return nil
+}
+
+// detectCI disables realtime updates and colors in CI or when the output is piped/redirected
+func detectCI() {
 	// Check env for CI
 	if os.Getenv("CI") != "" {
 		ctx.CI = true
 	}
 
 	// Check if it is a terminal or being piped/redirected
-	// We want to disable realtime updates if that is the case
 	o, _ := os.Stdout.Stat()
 	if (o.Mode() & os.ModeCharDevice) != os.ModeCharDevice {
 		ctx.CI = true
 	}
+}
+
+// setLogLevel selects the messages logged to stderr from --quiet, --verbose and --debug
+func setLogLevel() error {
+	switch {
+	case ctx.Quiet && (verbose || debugHTTP):
+		return errors.New("--quiet cannot be combined with --verbose or --debug")
+	case ctx.Quiet:
+		client.Level = client.LevelQuiet
+	case debugHTTP:
+		client.Level = client.LevelDebug
+	case verbose:
+		client.Level = client.LevelVerbose
+	default:
+		client.Level = client.LevelNormal
+	}
+	return nil
+}
+
+// configureTransport sets the HTTP client of every request when a proxy or custom TLS settings are selected
+func configureTransport() error {
+	if proxy == "" && caCert == "" && !insecure {
+		return nil
+	}
+	t, err := client.NewTransport(proxy, caCert, insecure)
+	if err != nil {
+		return err
+	}
+	client.HTTPClient = &http.Client{Transport: t, Timeout: client.Timeout}
+	return nil
+}
+
+// postError prints the error of a failed measurement post with the reason of every invalid field, errors caused by
+// the input of the user are returned so the help of the command is printed
+func postError(err error) error {
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.Type == client.ErrorTypeNoProbes {
+			warnUnmatchedLocations()
+		}
+		for _, k := range sortedKeys(apiErr.Params) {
+			fmt.Printf("err: %s\n", apiErr.Params[k])
+		}
+		if apiErr.Usage() {
+			return err
+		}
+	}
+	fmt.Println(err)
+	return nil
+}
 
+// postMeasurement posts the measurement built in opts, records it in the local history and outputs its results
+func postMeasurement() error {
+	opts.InProgressUpdates = ctx.Live
+	res, err := client.PostAPI(runCtx, opts)
+	if err != nil {
+		return postError(err)
+	}
+	outputMeasurement(res.ID, opts.Type)
 	return nil
 }
 
+// outputMeasurement records a posted measurement in the local history, outputs its results and runs the output flags
+// on them once it is finished
+func outputMeasurement(id, measurementType string) {
+	recordHistory(id, measurementType, ctx.Target)
+	renderMeasurement(id, measurementType)
+}
+
+// renderMeasurement outputs the results of a measurement while waiting for it and runs the output flags on them once
+// it is finished. If the wait is interrupted, the command fetching the results later is printed
+func renderMeasurement(id, measurementType string) {
+	data, err := client.OutputResults(runCtx, id, ctx)
+	if err != nil {
+		fmt.Println(err)
+		if errors.Is(err, client.ErrInterrupted) || errors.Is(err, client.ErrTimeout) {
+			fmt.Fprintln(os.Stderr, client.FetchLaterHint(id))
+		}
+		return
+	}
+
+	noteIncomplete(data)
+	printBodies(data)
+	analyzeResults(data)
+	summarizeResults(measurementType, data)
+	mapResults(measurementType, data)
+	logResults(data)
+	exportResults(data)
+	shareResults(id)
+	copyResults(id, data)
+	evaluateResults(measurementType, data)
+	baselineResults(measurementType, data)
+}
+
+// printBodies prints the response bodies of an http measurement after the human readable output and saves them to files
+func printBodies(data model.GetMeasurement) {
+	quiet := ctx.JsonOutput || ctx.Format != "" || ctx.Quiet
+	if ctx.ShowBody && !quiet {
+		fmt.Println()
+		fmt.Println(client.ResponseBodies(data, ctx))
+	}
+
+	if ctx.SaveBody != "" {
+		files, err := client.SaveBodies(data, ctx.SaveBody, ctx.BodyLimit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		if !quiet {
+			for _, f := range files {
+				fmt.Printf("Saved response body to %s\n", f)
+			}
+		}
+	}
+}
+
+// noteIncomplete reports on stderr that the results were rendered before the measurement finished because of --wait
+func noteIncomplete(data model.GetMeasurement) {
+	if note, ok := client.IncompleteNote(data); ok {
+		fmt.Fprintln(os.Stderr, note)
+	}
+}
+
+// analyzeResults prints the analysis of the results after the human readable output, the response headers of an http
+// measurement or where the packet loss of an mtr measurement begins
+func analyzeResults(data model.GetMeasurement) {
+	// The hops table is the default output of mtr
+	if !ctx.Analyze || ctx.JsonOutput || (ctx.Format != "" && ctx.Format != "table") || ctx.Quiet {
+		return
+	}
+	fmt.Println()
+	if data.Type == "mtr" || ctx.Cmd == "mtr" {
+		fmt.Println(client.FormatLossAnalysis(data, ctx))
+		return
+	}
+	fmt.Println(client.FormatHeaderAnalysis(data, ctx))
+}
+
+// summarizeResults prints the aggregate summary of all probes after the human readable output
+func summarizeResults(measurementType string, data model.GetMeasurement) {
+	// Quiet runs already print the summary instead of the results
+	if !ctx.Summary || ctx.JsonOutput || ctx.Format != "" || ctx.Quiet {
+		return
+	}
+	fmt.Println()
+	fmt.Println(client.AggregateSummary(measurementType, data))
+}
+
+// mapResults prints the latency map of the continents after the human readable output
+func mapResults(measurementType string, data model.GetMeasurement) {
+	if !ctx.Map || ctx.JsonOutput || ctx.Format != "" || ctx.Quiet {
+		return
+	}
+	fmt.Println()
+	fmt.Println(client.LatencyMap(measurementType, data))
+}
+
+// logResults appends the results of every probe to the NDJSON log selected with --log-ndjson
+func logResults(data model.GetMeasurement) {
+	if logNdjson == "" {
+		return
+	}
+	err := client.AppendNdjson(logNdjson, data, ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// shareResults prints the share URL of the measurement after the human readable output and copies it to the clipboard
+// when running in a terminal
+func shareResults(id string) {
+	if !ctx.Share || ctx.JsonOutput || ctx.Format != "" || ctx.Quiet {
+		return
+	}
+	fmt.Printf("Share: %s\n", client.ShareUrl(id))
+	if !ctx.CI {
+		client.CopyToClipboard(client.ShareUrl(id))
+	}
+}
+
+// copyResults copies the artifact selected with --copy to the clipboard once the measurement is finished
+func copyResults(id string, data model.GetMeasurement) {
+	if ctx.Copy == "" {
+		return
+	}
+
+	var text string
+	var err error
+	switch ctx.Copy {
+	case client.CopyJson:
+		text, err = client.FormatJson(runCtx, id)
+	case client.CopyMarkdown:
+		text, err = client.FormatMarkdown(data, ctx)
+	default:
+		text = client.ShareUrl(id)
+	}
+	if err == nil {
+		err = client.WriteClipboard(text)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	client.Logf(client.LevelNormal, "Copied the %s of the results to the clipboard", copyLabels[ctx.Copy])
+}
+
+// Name of every --copy artifact in messages
+var copyLabels = map[string]string{client.CopyUrl: "share URL", client.CopyJson: "JSON", client.CopyMarkdown: "markdown table"}
+
+// evaluateResults reports the threshold violations of a finished measurement on stderr and sets a failing exit code,
+// then emits the output of the selected CI provider
+func evaluateResults(measurementType string, data model.GetMeasurement) {
+	violations := client.CheckThresholds(measurementType, data, ctx.Thresholds)
+	if ctx.Thresholds.Assertions() && !ctx.JsonOutput && ctx.Format == "" {
+		// Response assertions are a synthetic check, report every probe
+		fmt.Println()
+		fmt.Println(client.FormatChecks(measurementType, data, ctx.Thresholds))
+	} else if len(violations) > 0 {
+		fmt.Fprintln(os.Stderr, client.FormatViolations(violations))
+	}
+	if len(violations) > 0 {
+		exitCode = 1
+	}
+	notifyResults(data, violations)
+
+	if ctx.CIProvider == "github" {
+		if annotations := client.GithubAnnotations(measurementType, ctx.Target, data, ctx.Thresholds); annotations != "" {
+			fmt.Println(annotations)
+		}
+		err := client.AppendGithubSummary(client.GithubSummary(measurementType, ctx.Target, data, ctx.Thresholds))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// ciValue backs the --ci flag: a bool that can also select a CI provider with extra output, e.g. --ci=github
+type ciValue struct{}
+
+func (c *ciValue) String() string {
+	if ctx.CIProvider != "" {
+		return ctx.CIProvider
+	}
+	return strconv.FormatBool(ctx.CI)
+}
+
+func (c *ciValue) Set(v string) error {
+	if v == "github" {
+		ctx.CI = true
+		ctx.CIProvider = v
+		return nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return errors.New("must be true, false or github")
+	}
+	ctx.CI = b
+	ctx.CIProvider = ""
+	return nil
+}
+
+func (c *ciValue) Type() string {
+	return "string"
+}
+
+// failPolicyValue backs the --fail-on-probe-errors flag, one of client.FailPolicies
+type failPolicyValue struct{}
+
+func (f *failPolicyValue) String() string {
+	return ctx.Thresholds.FailOnProbeErrors
+}
+
+func (f *failPolicyValue) Set(v string) error {
+	for _, p := range client.FailPolicies {
+		if v == p {
+			ctx.Thresholds.FailOnProbeErrors = v
+			return nil
+		}
+	}
+	return errors.New("must be one of " + strings.Join(client.FailPolicies, ", "))
+}
+
+func (f *failPolicyValue) Type() string {
+	return "string"
+}
+
+// maxLossValue backs the --max-loss flag, unset unless given so an explicit 0 fails on any loss
+type maxLossValue struct{}
+
+func (m *maxLossValue) String() string {
+	if ctx.Thresholds.MaxLoss == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*ctx.Thresholds.MaxLoss, 'f', -1, 64)
+}
+
+func (m *maxLossValue) Set(v string) error {
+	loss, err := strconv.ParseFloat(v, 64)
+	if err != nil || loss < 0 || loss > 100 {
+		return errors.New("must be a percentage between 0 and 100")
+	}
+	ctx.Thresholds.MaxLoss = &loss
+	return nil
+}
+
+func (m *maxLossValue) Type() string {
+	return "float64"
+}
+
+// copyValue backs the --copy flag, one of client.CopyArtifacts
+type copyValue struct{}
+
+func (c *copyValue) String() string {
+	return ctx.Copy
+}
+
+func (c *copyValue) Set(v string) error {
+	for _, a := range client.CopyArtifacts {
+		if v == a {
+			ctx.Copy = v
+			return nil
+		}
+	}
+	return errors.New("must be one of " + strings.Join(client.CopyArtifacts, ", "))
+}
+
+func (c *copyValue) Type() string {
+	return "string"
+}
+
+// runMeasurements builds and posts a measurement for every target. A single target keeps the realtime output,
+// several targets are measured concurrently and their results printed grouped by target once all are finished.
+func runMeasurements(build func() (model.PostMeasurement, error)) error {
+	build = withValidLimit(withLocationLimits(build))
+
+	if dryRun {
+		return previewMeasurements(build)
+	}
+
+	if baselineAction != "" && (ctx.Watch || len(ctx.Targets) > 1) {
+		return errors.New("--baseline only supports a single measurement, without --watch")
+	}
+	if ctx.Copy != "" && (ctx.Watch || len(ctx.Targets) > 1) {
+		return errors.New("--copy only supports a single measurement, without --watch")
+	}
+
+	if ctx.Watch {
+		return watchMeasurement(build)
+	}
+
+	if len(ctx.Targets) <= 1 {
+		m, err := build()
+		if err != nil {
+			return err
+		}
+		opts = m
+		return postMeasurement()
+	}
+
+	targets := ctx.Targets
+	measurements := make([]model.PostMeasurement, len(targets))
+	for i, target := range targets {
+		ctx.Target = target
+		m, err := build()
+		if err != nil {
+			return err
+		}
+		measurements[i] = m
+	}
+
+	// Failed measurements are reported with their target below
+	results, _ := newRunner().Run(runCtx, measurements)
+
+	failed := 0
+	for i, r := range results {
+		if r.ID != "" {
+			recordHistory(r.ID, measurements[i].Type, targets[i])
+		}
+
+		ctx.Target = targets[i]
+		// Quiet runs only print the summary of every target below
+		detailed := !ctx.JsonOutput && !ctx.Quiet
+		if detailed {
+			fmt.Printf("=== %s ===\n", targets[i])
+		}
+		if r.Err != nil {
+			if detailed {
+				fmt.Println(r.Err)
+			}
+			failed++
+		} else {
+			if !ctx.Quiet {
+				c := ctx
+				// Every target gets its own file
+				if ctx.Output != "" && ctx.Output != "-" {
+					c.Output = client.TargetOutput(ctx.Output, targets[i])
+				}
+				client.OutputFinished(runCtx, r.ID, r.Data, c)
+			}
+			noteIncomplete(r.Data)
+			printBodies(r.Data)
+			analyzeResults(r.Data)
+			summarizeResults(measurements[i].Type, r.Data)
+			mapResults(measurements[i].Type, r.Data)
+			logResults(r.Data)
+			exportResults(r.Data)
+			shareResults(r.ID)
+			evaluateResults(measurements[i].Type, r.Data)
+		}
+		if detailed {
+			fmt.Println()
+		}
+	}
+
+	if !ctx.JsonOutput {
+		fmt.Println(client.BatchSummary(ctx.Cmd, targets, results))
+	}
+
+	if failed > 0 {
+		exitCode = 1
+	}
+	return nil
+}
+
+// rejectOutput returns an error if --output selects a file for a feature that prints its own output
+func rejectOutput(feature string) error {
+	if ctx.Output == "" || ctx.Output == "-" {
+		return nil
+	}
+	return fmt.Errorf("--output is not supported with %s", feature)
+}
+
+// rejectCopy returns an error if --copy is set for a feature running several measurements
+func rejectCopy(feature string) error {
+	if ctx.Copy == "" {
+		return nil
+	}
+	return fmt.Errorf("--copy is not supported with %s", feature)
+}
+
+// newRunner creates a runner of at most --parallel measurements, reporting its progress on stderr in a terminal
+func newRunner() *runner.Runner {
+	r := runner.New(parallel)
+	if !ctx.CI && !ctx.JsonOutput && !ctx.Quiet {
+		r.Progress = func(p runner.Progress) {
+			fmt.Fprintf(os.Stderr, "\r%d/%d measurements finished", p.Finished, p.Total)
+			if p.Finished == p.Total {
+				// Clear the line before the results are printed
+				fmt.Fprint(os.Stderr, "\r\033[K")
+			}
+		}
+	}
+	return r
+}
+
+// withValidLimit rejects measurements using more probes than the API accepts
+func withValidLimit(build func() (model.PostMeasurement, error)) func() (model.PostMeasurement, error) {
+	return func() (model.PostMeasurement, error) {
+		m, err := build()
+		if err != nil {
+			return m, err
+		}
+		return m, client.ValidateLimit(m)
+	}
+}
+
+// previewMeasurements builds the measurement of every target and prints it as with dryRunMeasurements
+func previewMeasurements(build func() (model.PostMeasurement, error)) error {
+	measurements := make([]model.PostMeasurement, len(ctx.Targets))
+	for i, target := range ctx.Targets {
+		ctx.Target = target
+		m, err := build()
+		if err != nil {
+			return err
+		}
+		// Set by postMeasurement, which posts a single target without --watch
+		m.InProgressUpdates = ctx.Live && len(ctx.Targets) == 1 && !ctx.Watch
+		measurements[i] = m
+	}
+	return dryRunMeasurements(measurements)
+}
+
+// dryRunMeasurements prints the JSON body of every measurement on its own line, exactly as it would be posted, so it
+// can be reused with curl. The endpoint, the probes the measurements would use and their cost go to stderr.
+func dryRunMeasurements(measurements []model.PostMeasurement) error {
+	for _, m := range measurements {
+		body, err := client.MeasurementBody(m)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		fmt.Println(string(body))
+	}
+	previewCost(measurements)
+	return nil
+}
+
+// previewCost prints the endpoint the measurements would be posted to, the probes they would use and their cost on
+// stderr
+func previewCost(measurements []model.PostMeasurement) {
+	// The remaining limits are only informative, the preview works offline
+	var limits *model.Limits
+	if l, err := client.GetLimits(runCtx); err == nil {
+		limits = &l
+	}
+	fmt.Fprintf(os.Stderr, "POST %s\n", client.ApiUrl)
+	fmt.Fprintln(os.Stderr, client.CostPreview(measurements, limits))
+}
+
 func createLocations(from string) []model.Locations {
-	fromArr := strings.Split(from, ",")
-	locations := make([]model.Locations, len(fromArr))
-	for i, v := range fromArr {
-		locations[i] = model.Locations{
-			Magic: strings.TrimSpace(v),
+	return client.ParseLocations(from)
+}
+
+// withLocationLimits sets the limit of the measurement to the total of the location groups that have their own limit
+func withLocationLimits(build func() (model.PostMeasurement, error)) func() (model.PostMeasurement, error) {
+	return func() (model.PostMeasurement, error) {
+		m, err := build()
+		if err != nil {
+			return m, err
 		}
+		return client.ApplyLocationLimits(m), nil
 	}
-	return locations
 }
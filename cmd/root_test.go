@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/jsdelivr/globalping-cli/client"
 	"github.com/jsdelivr/globalping-cli/model"
 
 	"github.com/stretchr/testify/assert"
@@ -13,6 +17,7 @@ func TestCreateLocations(t *testing.T) {
 		"valid_single":              testLocationsSingle,
 		"valid_multiple":            testLocationsMultiple,
 		"valid_multiple_whitespace": testLocationsMultipleWhitespace,
+		"valid_group_limits":        testLocationsGroupLimits,
 	} {
 		t.Run(scenario, func(t *testing.T) {
 			fn(t)
@@ -36,6 +41,20 @@ func testLocationsMultipleWhitespace(t *testing.T) {
 	assert.Equal(t, []model.Locations{{Magic: "New York"}, {Magic: "Los Angeles"}}, locations)
 }
 
+// Check if per group limits are parsed and added up
+func testLocationsGroupLimits(t *testing.T) {
+	locations := createLocations("Germany:3, US:5,Asia")
+	assert.Equal(t, []model.Locations{{Magic: "Germany", Limit: 3}, {Magic: "US", Limit: 5}, {Magic: "Asia"}}, locations)
+
+	build := withLocationLimits(func() (model.PostMeasurement, error) {
+		return model.PostMeasurement{Locations: locations, Limit: 2}, nil
+	})
+	m, err := build()
+	assert.NoError(t, err)
+	assert.Equal(t, 10, m.Limit)
+	assert.Equal(t, 2, m.Locations[2].Limit)
+}
+
 func TestCreateContext(t *testing.T) {
 	for scenario, fn := range map[string]func(t *testing.T){
 		"no_arg":             testContextNoArg,
@@ -90,3 +109,275 @@ func testContextCIEnv(t *testing.T) {
 	assert.True(t, ctx.CI)
 	assert.NoError(t, err)
 }
+
+// Every measurement command should expose the --json output flag
+func TestMeasurementCommandsJsonFlag(t *testing.T) {
+	for _, c := range []string{"ping", "traceroute", "dns", "mtr", "http"} {
+		sub, _, err := rootCmd.Find([]string{c})
+		assert.NoError(t, err)
+		assert.Equal(t, "Measurements", sub.GroupID)
+		assert.NotNil(t, sub.InheritedFlags().Lookup("json"), c)
+	}
+}
+
+func TestCreateContextTargets(t *testing.T) {
+	ctx = model.Context{}
+	err := createContext("ping", []string{"google.com", "1.1.1.1", "from", "Germany"})
+	assert.NoError(t, err)
+	assert.Equal(t, "google.com", ctx.Target)
+	assert.Equal(t, []string{"google.com", "1.1.1.1"}, ctx.Targets)
+	assert.Equal(t, "Germany", ctx.From)
+
+	ctx = model.Context{}
+	err = createContext("ping", []string{"google.com", "1.1.1.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"google.com", "1.1.1.1"}, ctx.Targets)
+	assert.Equal(t, "world", ctx.From)
+}
+
+func TestCreateContextTargetsFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "hosts.txt")
+	assert.NoError(t, os.WriteFile(name, []byte("# hosts\njsdelivr.com\n\n  cloudflare.com \n"), 0o600))
+
+	targetsFile = name
+	defer func() { targetsFile = "" }()
+
+	ctx = model.Context{}
+	err := createContext("ping", []string{"google.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"google.com", "jsdelivr.com", "cloudflare.com"}, ctx.Targets)
+
+	ctx = model.Context{}
+	err = createContext("ping", []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "jsdelivr.com", ctx.Target)
+
+	targetsFile = filepath.Join(t.TempDir(), "missing.txt")
+	assert.Error(t, createContext("ping", []string{}))
+}
+
+func TestCheckCommandFormat(t *testing.T) {
+	check := checkCommandFormat()
+	assert.NoError(t, check(pingCmd, []string{"google.com", "from", "Germany"}))
+	assert.NoError(t, check(pingCmd, []string{"google.com", "jsdelivr.com"}))
+	assert.Error(t, check(pingCmd, []string{"from", "Germany"}))
+}
+
+func TestCIFlag(t *testing.T) {
+	defer func() { ctx = model.Context{} }()
+	flag := rootCmd.PersistentFlags().Lookup("ci")
+
+	ctx = model.Context{}
+	assert.NoError(t, flag.Value.Set(flag.NoOptDefVal))
+	assert.True(t, ctx.CI)
+	assert.Equal(t, "", ctx.CIProvider)
+
+	assert.NoError(t, flag.Value.Set("github"))
+	assert.True(t, ctx.CI)
+	assert.Equal(t, "github", ctx.CIProvider)
+	assert.Equal(t, "github", flag.Value.String())
+
+	assert.NoError(t, flag.Value.Set("false"))
+	assert.False(t, ctx.CI)
+	assert.Equal(t, "", ctx.CIProvider)
+
+	assert.Error(t, flag.Value.Set("gitlab"))
+}
+
+func TestPostError(t *testing.T) {
+	usage := &client.APIError{StatusCode: 400, Type: client.ErrorTypeValidation, Message: "invalid parameters", Params: map[string]string{"target": "invalid target"}}
+	assert.Equal(t, usage, postError(usage))
+
+	internal := &client.APIError{StatusCode: 500, Type: client.ErrorTypeApi, Message: "err: internal server error"}
+	assert.NoError(t, postError(internal))
+	assert.NoError(t, postError(errors.New("err: request failed")))
+}
+
+func TestSetLogLevel(t *testing.T) {
+	t.Cleanup(func() {
+		ctx = model.Context{}
+		verbose, debugHTTP = false, false
+		client.Level = client.LevelNormal
+	})
+
+	assert.NoError(t, setLogLevel())
+	assert.Equal(t, client.LevelNormal, client.Level)
+
+	verbose = true
+	assert.NoError(t, setLogLevel())
+	assert.Equal(t, client.LevelVerbose, client.Level)
+
+	debugHTTP = true
+	assert.NoError(t, setLogLevel())
+	assert.Equal(t, client.LevelDebug, client.Level)
+
+	ctx.Quiet = true
+	assert.EqualError(t, setLogLevel(), "--quiet cannot be combined with --verbose or --debug")
+
+	verbose, debugHTTP = false, false
+	assert.NoError(t, setLogLevel())
+	assert.Equal(t, client.LevelQuiet, client.Level)
+}
+
+func TestFailOnProbeErrorsFlag(t *testing.T) {
+	defer func() { ctx = model.Context{} }()
+	flag := rootCmd.PersistentFlags().Lookup("fail-on-probe-errors")
+
+	ctx = model.Context{}
+	assert.NoError(t, flag.Value.Set(flag.NoOptDefVal))
+	assert.Equal(t, "any", ctx.Thresholds.FailOnProbeErrors)
+	assert.True(t, ctx.Thresholds.Enabled())
+
+	assert.NoError(t, flag.Value.Set("all"))
+	assert.Equal(t, "all", flag.Value.String())
+
+	assert.NoError(t, flag.Value.Set("none"))
+	assert.False(t, ctx.Thresholds.Enabled())
+
+	assert.EqualError(t, flag.Value.Set("some"), "must be one of any, all, none")
+}
+
+func TestCopyResults(t *testing.T) {
+	defer func() { ctx = model.Context{} }()
+	write := client.WriteClipboard
+	defer func() { client.WriteClipboard = write }()
+	var copied string
+	client.WriteClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+
+	flag := rootCmd.PersistentFlags().Lookup("copy")
+	ctx = model.Context{}
+	assert.NoError(t, flag.Value.Set(flag.NoOptDefVal))
+	copyResults("abcd", model.GetMeasurement{})
+	assert.Equal(t, "https://globalping.io?measurement=abcd", copied)
+
+	assert.NoError(t, flag.Value.Set("markdown"))
+	data := model.GetMeasurement{Type: "ping", Results: []model.MeasurementResponse{{
+		Probe:  model.ProbeData{Continent: "EU", Country: "DE", City: "Berlin", ASN: 1, Network: "Network"},
+		Result: model.ResultData{Status: "finished", Stats: &model.PingStats{}},
+	}}}
+	copyResults("abcd", data)
+	expected, _ := client.FormatMarkdown(data, ctx)
+	assert.Equal(t, expected, copied)
+
+	assert.EqualError(t, flag.Value.Set("csv"), "must be one of url, json, markdown")
+}
+
+func TestParseSelection(t *testing.T) {
+	defer func() {
+		ctx = model.Context{}
+		resultFilters = nil
+	}()
+
+	ctx = model.Context{Selection: model.Selection{Sort: "latency", Top: 5}}
+	resultFilters = []string{"country=DE"}
+	assert.NoError(t, parseSelection())
+	assert.Equal(t, map[string][]string{"country": {"DE"}}, ctx.Selection.Filters)
+
+	ctx = model.Context{Selection: model.Selection{Sort: "city"}}
+	assert.EqualError(t, parseSelection(), `invalid --sort "city", must be one of latency, country, network`)
+
+	ctx = model.Context{Selection: model.Selection{Top: 5, Worst: 5}}
+	assert.EqualError(t, parseSelection(), "--top cannot be combined with --worst")
+
+	ctx = model.Context{GroupBy: "city"}
+	assert.EqualError(t, parseSelection(), `invalid --group-by "city", must be one of country, continent, asn, network`)
+
+	ctx = model.Context{}
+	resultFilters = []string{"DE"}
+	assert.Error(t, parseSelection())
+
+	ctx = model.Context{JsonOutput: true, Selection: model.Selection{Top: 5}}
+	resultFilters = nil
+	assert.EqualError(t, parseSelection(), "--sort, --filter, --top and --worst cannot be combined with --json, use --jq to select results")
+
+	ctx = model.Context{JsonOutput: true}
+	resultFilters = []string{"country=DE"}
+	assert.Error(t, parseSelection())
+}
+
+func TestLoadTemplate(t *testing.T) {
+	defer func() {
+		ctx = model.Context{}
+		templateFile = ""
+	}()
+
+	path := filepath.Join(t.TempDir(), "probe.tmpl")
+	assert.NoError(t, os.WriteFile(path, []byte("{{.Probe.City}}\n"), 0o644))
+
+	ctx = model.Context{}
+	templateFile = path
+	assert.NoError(t, loadTemplate())
+	assert.Equal(t, "{{.Probe.City}}\n", ctx.Template)
+
+	ctx = model.Context{Template: "{{.Probe.City}}"}
+	assert.EqualError(t, loadTemplate(), "--template cannot be combined with --template-file")
+
+	templateFile = ""
+	ctx = model.Context{Template: "{{.Probe.City"}
+	assert.Error(t, loadTemplate())
+}
+
+func TestRejectOutput(t *testing.T) {
+	defer func() {
+		ctx = model.Context{}
+	}()
+
+	ctx = model.Context{Output: "-"}
+	assert.NoError(t, rejectOutput("--watch"))
+
+	ctx = model.Context{Output: "results.json"}
+	assert.EqualError(t, rejectOutput("--watch"), "--output is not supported with --watch")
+}
+
+func TestRejectCopy(t *testing.T) {
+	defer func() {
+		ctx = model.Context{}
+	}()
+
+	assert.NoError(t, rejectCopy("diagnose"))
+
+	ctx = model.Context{Copy: "url"}
+	assert.EqualError(t, rejectCopy("diagnose"), "--copy is not supported with diagnose")
+
+	ctx = model.Context{Copy: "url", Targets: []string{"a.com", "b.com"}}
+	err := runMeasurements(func() (model.PostMeasurement, error) {
+		return model.PostMeasurement{Type: "ping", Target: ctx.Target, Limit: 1}, nil
+	})
+	assert.EqualError(t, err, "--copy only supports a single measurement, without --watch")
+}
+
+func TestWatchRejectsTargets(t *testing.T) {
+	defer func() {
+		ctx = model.Context{}
+	}()
+
+	ctx = model.Context{Targets: []string{"a.com", "b.com"}, Watch: true}
+	err := watchMeasurement(func() (model.PostMeasurement, error) {
+		return model.PostMeasurement{Type: "ping", Target: ctx.Target, Limit: 1}, nil
+	})
+	assert.EqualError(t, err, "--watch only supports a single target")
+}
+
+func TestMaxLossFlag(t *testing.T) {
+	defer func() { ctx = model.Context{} }()
+	flag := pingCmd.Flags().Lookup("max-loss")
+
+	ctx = model.Context{}
+	assert.False(t, ctx.Thresholds.Enabled())
+	assert.Equal(t, "", flag.Value.String())
+
+	// An explicit 0 fails on any loss
+	assert.NoError(t, flag.Value.Set("0"))
+	assert.Equal(t, 0.0, *ctx.Thresholds.MaxLoss)
+	assert.True(t, ctx.Thresholds.Enabled())
+	assert.Equal(t, "0", flag.Value.String())
+
+	assert.NoError(t, flag.Value.Set("2.5"))
+	assert.Equal(t, "2.5", flag.Value.String())
+
+	assert.Error(t, flag.Value.Set("-1"))
+	assert.Error(t, flag.Value.Set("lots"))
+}
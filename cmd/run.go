@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jsdelivr/globalping-cli/config"
+	"github.com/spf13/cobra"
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run [profile] [target] [from location] [flags]",
+	Short: "Run a measurement profile of the config file",
+	Long: `The run command runs a named measurement profile of the config file against a target. The type key of the profile selects the measurement command and every other key is one of its flags. Locations and flags given on the command line take precedence over the profile.
+
+Profiles are defined in ~/.globalping/config.yml:
+
+  profiles:
+    cdn-check:
+      type: http
+      from: eyeball network
+      limit: 20
+      expect-status: 200
+
+or with the config command:
+
+  config set profiles.cdn-check.type http
+  config set profiles.cdn-check.limit 20
+
+Examples:
+  # Run the cdn-check profile against cdn.jsdelivr.net
+  run cdn-check cdn.jsdelivr.net
+
+  # Run the profile from Asia on 5 probes instead
+  run cdn-check cdn.jsdelivr.net from Asia --limit 5`,
+	// The arguments are parsed by the measurement command of the profile
+	DisableFlagParsing: true,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		c, err := config.Load()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return profileNames(c), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			return cmd.Help()
+		}
+
+		c, err := config.Load()
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+		expanded, err := profileArgs(c, args[0], args[1:])
+		if err != nil {
+			return err
+		}
+
+		rootCmd.SetArgs(expanded)
+		if rootCmd.Execute() != nil {
+			exitCode = 1
+		}
+		return nil
+	},
+}
+
+// profileArgs returns the arguments of the measurement command running a profile: the type and the target, then the
+// keys of the profile as flags and the remaining arguments, which override the profile
+func profileArgs(c *config.Config, name string, args []string) ([]string, error) {
+	profile, err := c.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		return nil, fmt.Errorf("profile %q requires a target: globalping run %s <target>", name, name)
+	}
+
+	keys := make([]string, 0, len(profile))
+	for k := range profile {
+		if k != "type" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	res := []string{profile["type"], args[0]}
+	for _, k := range keys {
+		if k == "" || k[0] == '-' {
+			return nil, errors.New("profile keys are flag names without dashes, e.g. limit")
+		}
+		res = append(res, "--"+k+"="+profile[k])
+	}
+	return append(res, args[1:]...), nil
+}
+
+// Names of the profiles of the config file in alphabetical order
+func profileNames(c *config.Config) []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+}
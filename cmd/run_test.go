@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jsdelivr/globalping-cli/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileArgs(t *testing.T) {
+	c := &config.Config{Profiles: map[string]map[string]string{
+		"cdn-check": {"type": "http", "from": "eyeball network", "limit": "20", "expect-status": "200"},
+	}}
+
+	args, err := profileArgs(c, "cdn-check", []string{"cdn.jsdelivr.net"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http", "cdn.jsdelivr.net", "--expect-status=200", "--from=eyeball network", "--limit=20"}, args)
+
+	// Arguments given on the command line come last to override the profile
+	args, err = profileArgs(c, "cdn-check", []string{"cdn.jsdelivr.net", "from", "Asia", "--limit", "5"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http", "cdn.jsdelivr.net", "--expect-status=200", "--from=eyeball network", "--limit=20", "from", "Asia", "--limit", "5"}, args)
+
+	_, err = profileArgs(c, "cdn-check", []string{"--limit", "5"})
+	assert.EqualError(t, err, `profile "cdn-check" requires a target: globalping run cdn-check <target>`)
+	_, err = profileArgs(c, "missing", []string{"cdn.jsdelivr.net"})
+	assert.EqualError(t, err, `unknown profile "missing" - define it with: globalping config set profiles.missing.type http`)
+}
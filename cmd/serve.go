@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/scheduler"
+	"github.com/jsdelivr/globalping-cli/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveListen   string
+	serveSchedule string
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a local REST API proxying the Globalping API",
+	Long: `The serve command runs a local REST API proxying measurement creation and retrieval, so dashboards and scripts on a host can use Globalping without embedding the API logic or the credentials. Requests are sent with the token of the CLI and created measurements are recorded in the local history.
+
+Routes:
+  GET  /health                  reports that the server is up
+  POST /v1/measurements         creates a measurement, the body is the same as the Globalping API
+  GET  /v1/measurements/{id}    returns a measurement, finished ones are served from the cache
+  GET  /v1/history              lists the history, filtered with the type, target and last query parameters
+  GET  /v1/probes               lists the online probes
+  GET  /v1/schedule             returns the last run of every scheduled measurement
+  GET  /probe                   runs a measurement and returns its Prometheus metrics, like the blackbox exporter
+
+The /probe route takes the target, module, from and limit query parameters, the module is the type of the measurement and defaults to ping. Existing scrape configs of the blackbox exporter only need the address of the server and the module:
+
+  scrape_configs:
+    - job_name: globalping
+      metrics_path: /probe
+      params:
+        module: [ping]
+        from: [Europe]
+      static_configs:
+        - targets: [jsdelivr.com]
+      relabel_configs:
+        - source_labels: [__address__]
+          target_label: __param_target
+        - source_labels: [__param_target]
+          target_label: instance
+        - target_label: __address__
+          replacement: 127.0.0.1:8080
+
+With --schedule, the measurements of a YAML file are run every interval. Every run is recorded in the history and the webhook is called when a probe fails or a threshold is breached:
+
+  webhook: https://hooks.slack.com/services/...
+  notify: slack
+  measurements:
+    - name: cdn
+      type: http
+      target: cdn.jsdelivr.net
+      from: Europe, North America
+      limit: 10
+      interval: 5m
+      path: /npm/react
+      thresholds:
+        max-latency: 500ms
+        expect-status: 200
+    - type: ping
+      target: jsdelivr.com
+      from: Germany:2, Japan:2
+      interval: 1m
+      thresholds:
+        max-loss: 5
+      log: ping.ndjson
+
+The server listens on localhost by default, listening on every interface lets anyone reaching the host use the token of the CLI.
+
+Examples:
+  # Serve the API on port 8080 of localhost
+  serve
+
+  # Serve the API on port 9000 of every interface
+  serve --listen :9000
+
+  # Run the measurements of schedule.yml and serve their status
+  serve --schedule schedule.yml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srv := server.New()
+		if serveSchedule != "" {
+			sch, err := scheduler.Load(serveSchedule)
+			if err != nil {
+				fmt.Println(err)
+				return nil
+			}
+			srv.Schedule(sch)
+			go sch.Run(runCtx)
+			client.Logf(client.LevelNormal, "Scheduled %d measurements", len(sch.Jobs))
+		}
+
+		client.Logf(client.LevelNormal, "Listening on %s", serveListen)
+		err := srv.ListenAndServe(runCtx, serveListen)
+		if err != nil {
+			fmt.Println(err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "Address the API listens on")
+	serveCmd.Flags().StringVar(&serveSchedule, "schedule", "", "YAML file of measurements run every interval, see the help for its format")
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:   "show [file|id]",
+	Short: "Render a saved measurement or a past measurement by ID",
+	Long: `The show command renders a measurement through the same output as the measurement commands, with every output flag like --format, --latency, --json or --output.
+The measurement is either a JSON file saved with --json or --output results.json, read without calling the API, or the ID of a measurement fetched from the API.
+
+Examples:
+  # Render a saved measurement
+  show results.json
+
+  # Convert a saved measurement to CSV
+  show results.json --format csv
+
+  # Print the latency stats of measurement UKbdVoWpIr6ec0cy
+  show UKbdVoWpIr6ec0cy --latency
+
+  # Save measurement UKbdVoWpIr6ec0cy as the baseline prod
+  show UKbdVoWpIr6ec0cy --baseline save:prod`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !client.ValidFormat(ctx.Format) {
+			return fmt.Errorf("unknown format %q - supported formats: %s", ctx.Format, strings.Join(client.FormatNames(), ", "))
+		}
+
+		data, err := loadMeasurement(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+
+		ctx.Cmd = data.Type
+		ctx.Target = data.Target
+		detectCI()
+
+		client.OutputFinished(runCtx, data.ID, data, ctx)
+		printBodies(data)
+		summarizeResults(data.Type, data)
+		mapResults(data.Type, data)
+		copyResults(data.ID, data)
+		evaluateResults(data.Type, data)
+		baselineResults(data.Type, data)
+		return nil
+	},
+}
+
+// loadMeasurement reads a saved measurement if the argument is a file, or fetches it from the API by ID
+func loadMeasurement(arg string) (model.GetMeasurement, error) {
+	if _, err := os.Stat(arg); err == nil {
+		return client.LoadMeasurement(arg)
+	}
+	return client.GetAPI(runCtx, arg)
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+}
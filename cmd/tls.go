@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/spf13/cobra"
+)
+
+var minDaysValid int
+
+// tlsCmd represents the tls command
+var tlsCmd = &cobra.Command{
+	Use:     "tls [target...] from [location]",
+	GroupID: "Measurements",
+	Short:   "Inspect the TLS certificate of a host",
+	Long: `The tls command sends an HTTPS request to a host and prints the certificate each probe received: subject, issuer, subject alternative names, validity window and the number of days until it expires.
+Use it to check that every location gets the same, valid certificate.
+
+Examples:
+  # Inspect the certificate of jsdelivr.com from 3 probes in Europe
+  tls jsdelivr.com from Europe --limit 3
+
+  # Inspect the certificate served on a custom port
+  tls example.com:8443 from USA
+
+  # Exit with a non-zero code if the certificate expires in less than 30 days anywhere in the world
+  tls jsdelivr.com from world --limit 10 --min-days-valid 30`,
+	Args: checkCommandFormat(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Create context
+		err := createContext(cmd.CalledAs(), args)
+		if err != nil {
+			return err
+		}
+		ctx.Thresholds.MinDaysValid = minDaysValid
+		if ctx.Format == "" && !ctx.JsonOutput {
+			ctx.Format = "tls"
+		}
+
+		return runMeasurements(buildTlsMeasurement)
+	},
+}
+
+// buildTlsMeasurement builds an https measurement request returning the certificate of the target
+func buildTlsMeasurement() (model.PostMeasurement, error) {
+	m := model.PostMeasurement{
+		Type: PostMeasurementTypeHttp,
+	}
+
+	urlData, err := parseUrlData(ctx.Target)
+	if err != nil {
+		return m, err
+	}
+
+	v, err := ipVersion(urlData.Host)
+	if err != nil {
+		return m, err
+	}
+
+	m.Target = urlData.Host
+	m.Locations = createLocations(ctx.From)
+	m.Limit = ctx.Limit
+	m.Options = &model.MeasurementOptions{
+		Protocol:  "HTTPS",
+		Port:      urlData.Port,
+		IPVersion: v,
+		Request: &model.RequestOptions{
+			Host:   urlData.Host,
+			Method: "HEAD",
+		},
+	}
+
+	return m, nil
+}
+
+func init() {
+	rootCmd.AddCommand(tlsCmd)
+
+	tlsCmd.Flags().IntVar(&minDaysValid, "min-days-valid", 0, "Exit with a non-zero code if the certificate seen by any probe expires in less than the given number of days")
+}
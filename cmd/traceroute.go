@@ -1,16 +1,15 @@
 package cmd
 
 import (
-	"fmt"
+	"errors"
 
-	"github.com/jsdelivr/globalping-cli/client"
 	"github.com/jsdelivr/globalping-cli/model"
 	"github.com/spf13/cobra"
 )
 
 // tracerouteCmd represents the traceroute command
 var tracerouteCmd = &cobra.Command{
-	Use:     "traceroute [target] from [location]",
+	Use:     "traceroute [target...] from [location]",
 	GroupID: "Measurements",
 	Short:   "Use the native traceroute command",
 	Long: `traceroute tracks the route packets taken from an IP network on their way to a given host. It utilizes the IP protocol's time to live (TTL) field and attempts to elicit an ICMP TIME_EXCEEDED response from each gateway along the path to the host.
@@ -25,6 +24,24 @@ Examples:
   # Traceroute jsdelivr.com from a probe that is from the AWS network and is located in Montreal using the UDP protocol
   traceroute jsdelivr.com from aws+montreal --protocol udp
 
+  # Traceroute jsdelivr.com over TCP to port 443
+  traceroute jsdelivr.com from Germany --protocol tcp --port 443
+
+  # Traceroute google.com from the probes used by the last measurement
+  traceroute google.com --from-measurement last
+
+  # Traceroute google.com without looking up the ASN and organization of every hop
+  traceroute google.com from Germany --no-enrich
+
+  # Summarize the AS path of 5 probes in Europe to google.com and the transit networks they cross
+  traceroute google.com from Europe --limit 5 --format aspath
+
+  # Traceroute google.com printing the native traceroute output with live updates
+  traceroute google.com from Germany --raw
+
+  # Traceroute google.com printing every hop as soon as a probe reports it
+  traceroute google.com from Germany --live
+
   # Traceroute jsdelivr.com with ASN 12345 with json output
   traceroute jsdelivr.com from 12345 --json`,
 	Args: checkCommandFormat(),
@@ -35,36 +52,54 @@ Examples:
 			return err
 		}
 
-		// Make post struct
-		opts = model.PostMeasurement{
-			Type:      "traceroute",
-			Target:    ctx.Target,
-			Locations: createLocations(ctx.From),
-			Limit:     ctx.Limit,
-			Options: &model.MeasurementOptions{
-				Protocol: protocol,
-				Port:     port,
-			},
-		}
-
-		res, showHelp, err := client.PostAPI(opts)
-		if err != nil {
-			if showHelp {
-				return err
-			}
-			fmt.Println(err)
-			return nil
+		// Render the hops as a table unless the native output is requested
+		if !rawOutput && !ctx.Live && ctx.Format == "" && !ctx.JsonOutput {
+			ctx.Format = "table"
 		}
 
-		client.OutputResults(res.ID, ctx)
-		return nil
+		return runMeasurements(buildTracerouteMeasurement)
 	},
 }
 
+// buildTracerouteMeasurement builds the measurement request for the traceroute type
+func buildTracerouteMeasurement() (model.PostMeasurement, error) {
+	p, err := validateProtocol(protocol, "ICMP", "TCP", "UDP")
+	if err != nil {
+		return model.PostMeasurement{}, err
+	}
+	if err := validatePort(port); err != nil {
+		return model.PostMeasurement{}, err
+	}
+	if port != 0 && p != "TCP" {
+		return model.PostMeasurement{}, errors.New("--port is only supported with the TCP protocol")
+	}
+
+	v, err := ipVersion(ctx.Target)
+	if err != nil {
+		return model.PostMeasurement{}, err
+	}
+
+	return model.PostMeasurement{
+		Type:      "traceroute",
+		Target:    ctx.Target,
+		Locations: createLocations(ctx.From),
+		Limit:     ctx.Limit,
+		Options: &model.MeasurementOptions{
+			Protocol:  p,
+			Port:      port,
+			IPVersion: v,
+		},
+	}, nil
+}
+
 func init() {
 	rootCmd.AddCommand(tracerouteCmd)
 
 	// traceroute specific flags
 	tracerouteCmd.Flags().StringVar(&protocol, "protocol", "", "Specifies the protocol used for tracerouting (ICMP, TCP or UDP) (default \"icmp\")")
 	tracerouteCmd.Flags().IntVar(&port, "port", 0, "Specifies the port to use for the traceroute. Only applicable for TCP protocol (default 80)")
+
+	// Extra flags
+	tracerouteCmd.Flags().BoolVar(&ctx.NoEnrich, "no-enrich", false, "Do not look up the ASN and organization of every hop (default false)")
+	tracerouteCmd.Flags().BoolVar(&rawOutput, "raw", false, "Print the native traceroute output with live updates instead of the hops table (default false)")
 }
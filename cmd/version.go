@@ -2,18 +2,61 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
+	"runtime/debug"
+	"time"
 
+	"github.com/jsdelivr/globalping-cli/client"
 	"github.com/spf13/cobra"
 )
 
+var versionCheck bool
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
+
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "Also print the commit and Go version and check that the API is reachable (default false)")
 }
 
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number of Globalping CLI",
+	Long: `The version command prints the version of the CLI.
+With --check, it also prints the commit and Go version the CLI was built with and sends a request to the API to report whether it is reachable and its latency.
+
+Examples:
+  # Check whether a failure comes from the local network or from the API
+  version --check`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Globalping CLI v" + version)
+		if !versionCheck {
+			return
+		}
+
+		fmt.Println("Commit: " + buildCommit())
+		fmt.Printf("Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+		health, err := client.CheckAPI(runCtx)
+		if err != nil {
+			fmt.Printf("API: %s - %s\n", health.Url, err)
+			exitCode = 1
+			return
+		}
+		fmt.Printf("API: %s reachable, status %d in %s\n", health.Url, health.StatusCode, health.Latency.Round(time.Millisecond))
 	},
 }
+
+// buildCommit returns the commit set at release time, or the one recorded by go build in a git checkout
+func buildCommit() string {
+	if commit != "" {
+		return commit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				return s.Value
+			}
+		}
+	}
+	return "unknown"
+}
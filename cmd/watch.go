@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/runner"
+)
+
+// watchMeasurement runs the measurement of the target on a timer from the same probes and redraws the results
+// after every run until interrupted
+func watchMeasurement(build func() (model.PostMeasurement, error)) error {
+	if len(ctx.Targets) > 1 {
+		return errors.New("--watch only supports a single target")
+	}
+	if err := rejectOutput("--watch"); err != nil {
+		return err
+	}
+	m, err := build()
+	if err != nil {
+		return err
+	}
+
+	w := client.NewWatch(m.Type)
+	for {
+		results, _ := runner.New(1).Run(runCtx, []model.PostMeasurement{m})
+		res := results[0]
+		if res.ID == "" {
+			return postError(res.Err)
+		}
+		recordHistory(res.ID, m.Type, ctx.Target)
+
+		// Reuse the probes of the first run so results stay comparable
+		m.Locations = []model.Locations{{Magic: res.ID}}
+
+		if res.Err != nil {
+			fmt.Println(res.Err)
+			return nil
+		}
+		data := res.Data
+
+		if !ctx.CI {
+			// Clear the terminal before redrawing
+			fmt.Print("\033[H\033[2J")
+		}
+		fmt.Printf("Every %s: globalping %s %s from %s - %s\n\n", ctx.Interval, m.Type, ctx.Target, ctx.From, time.Now().Format("15:04:05"))
+		fmt.Println(w.Render(data, ctx))
+		summarizeResults(m.Type, data)
+		mapResults(m.Type, data)
+		logResults(data)
+		exportResults(data)
+		if ctx.CI {
+			fmt.Println()
+		}
+		evaluateResults(m.Type, data)
+
+		select {
+		case <-runCtx.Done():
+			return nil
+		case <-time.After(ctx.Interval):
+		}
+	}
+}
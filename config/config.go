@@ -0,0 +1,432 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the persistent defaults stored in ~/.globalping/config.yml
+type Config struct {
+	// From is the default location used when no "from" argument or flag is given
+	From string `yaml:"from,omitempty"`
+	// Limit is the default number of probes
+	Limit int `yaml:"limit,omitempty"`
+	// Format is the default output format (json, latency, ci or a --format value)
+	Format string `yaml:"format,omitempty"`
+	// ApiUrl is the base URL of the Globalping API
+	ApiUrl string `yaml:"api-url,omitempty"`
+	// Timeout of every request made to the API, e.g. 30s
+	Timeout string `yaml:"timeout,omitempty"`
+	// CacheTTL is the duration finished measurements are cached on disk, e.g. 24h
+	CacheTTL string `yaml:"cache-ttl,omitempty"`
+	// FailOnProbeErrors is the default exit code policy of failed probes: any, all or none
+	FailOnProbeErrors string `yaml:"fail-on-probe-errors,omitempty"`
+	// InfluxDB settings used by export and --export influxdb
+	InfluxUrl    string `yaml:"influxdb-url,omitempty"`
+	InfluxOrg    string `yaml:"influxdb-org,omitempty"`
+	InfluxBucket string `yaml:"influxdb-bucket,omitempty"`
+	InfluxToken  string `yaml:"influxdb-token,omitempty"`
+	// Locations are named sets of locations used as --from @name
+	Locations map[string]string `yaml:"locations,omitempty"`
+	// Profiles are named measurements run with "globalping run <name> <target>", the type key selects the measurement
+	// command and every other key is one of its flags, e.g. limit or expect-status
+	Profiles map[string]map[string]string `yaml:"profiles,omitempty"`
+	// Env is the environment used when no --env flag is given
+	Env string `yaml:"env,omitempty"`
+	// Environments are named API endpoints selected with --env, e.g. a staging or self-hosted API
+	Environments map[string]Environment `yaml:"environments,omitempty"`
+}
+
+// Environment is a named Globalping API with its own token
+type Environment struct {
+	// Url is the base URL of the API, e.g. https://api.globalping.io/v1
+	Url string `yaml:"url,omitempty"`
+	// Token replaces the stored token, the API is used anonymously without it
+	Token string `yaml:"token,omitempty"`
+}
+
+// Formats returns the values accepted by the format key, the json, latency and ci outputs and every --format
+func Formats() []string {
+	return append([]string{"json", "latency", "ci"}, client.FormatNames()...)
+}
+
+// Path of the config file, overridable for tests
+var Path = defaultPath()
+
+func defaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".globalping", "config.yml")
+	}
+	return filepath.Join(home, ".globalping", "config.yml")
+}
+
+// Every supported key with its getter and a setter validating the value
+var keys = map[string]struct {
+	get func(c *Config) string
+	set func(c *Config, v string) error
+}{
+	"from": {
+		get: func(c *Config) string { return c.From },
+		set: func(c *Config, v string) error { c.From = v; return nil },
+	},
+	"limit": {
+		get: func(c *Config) string {
+			if c.Limit == 0 {
+				return ""
+			}
+			return strconv.Itoa(c.Limit)
+		},
+		set: func(c *Config, v string) error {
+			if v == "" {
+				c.Limit = 0
+				return nil
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return errors.New("limit must be a positive number")
+			}
+			c.Limit = n
+			return nil
+		},
+	},
+	"format": {
+		get: func(c *Config) string { return c.Format },
+		set: func(c *Config, v string) error {
+			if v == "" {
+				c.Format = v
+				return nil
+			}
+			for _, f := range Formats() {
+				if v == f {
+					c.Format = v
+					return nil
+				}
+			}
+			return fmt.Errorf("format must be one of %s", strings.Join(Formats(), ", "))
+		},
+	},
+	"api-url": {
+		get: func(c *Config) string { return c.ApiUrl },
+		set: func(c *Config, v string) error { c.ApiUrl = v; return nil },
+	},
+	"env": {
+		get: func(c *Config) string { return c.Env },
+		set: func(c *Config, v string) error { c.Env = v; return nil },
+	},
+	"influxdb-url": {
+		get: func(c *Config) string { return c.InfluxUrl },
+		set: func(c *Config, v string) error { c.InfluxUrl = v; return nil },
+	},
+	"influxdb-org": {
+		get: func(c *Config) string { return c.InfluxOrg },
+		set: func(c *Config, v string) error { c.InfluxOrg = v; return nil },
+	},
+	"influxdb-bucket": {
+		get: func(c *Config) string { return c.InfluxBucket },
+		set: func(c *Config, v string) error { c.InfluxBucket = v; return nil },
+	},
+	"influxdb-token": {
+		get: func(c *Config) string { return c.InfluxToken },
+		set: func(c *Config, v string) error { c.InfluxToken = v; return nil },
+	},
+	"cache-ttl": {
+		get: func(c *Config) string { return c.CacheTTL },
+		set: func(c *Config, v string) error {
+			if v != "" {
+				if _, err := time.ParseDuration(v); err != nil {
+					return errors.New("cache-ttl must be a duration, e.g. 24h")
+				}
+			}
+			c.CacheTTL = v
+			return nil
+		},
+	},
+	"fail-on-probe-errors": {
+		get: func(c *Config) string { return c.FailOnProbeErrors },
+		set: func(c *Config, v string) error {
+			switch v {
+			case "", "any", "all", "none":
+				c.FailOnProbeErrors = v
+				return nil
+			}
+			return errors.New("fail-on-probe-errors must be one of any, all, none")
+		},
+	},
+	"timeout": {
+		get: func(c *Config) string { return c.Timeout },
+		set: func(c *Config, v string) error {
+			if v != "" {
+				if _, err := time.ParseDuration(v); err != nil {
+					return errors.New("timeout must be a duration, e.g. 30s")
+				}
+			}
+			c.Timeout = v
+			return nil
+		},
+	},
+}
+
+// Keys returns the supported config keys in alphabetical order
+func Keys() []string {
+	res := make([]string, 0, len(keys))
+	for k := range keys {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Load reads the config file, a missing file results in an empty config
+func Load() (*Config, error) {
+	c := &Config{}
+
+	b, err := os.ReadFile(Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return nil, errors.New("err: failed to read config file")
+	}
+
+	err = yaml.Unmarshal(b, c)
+	if err != nil {
+		return nil, fmt.Errorf("err: invalid config file format: %s", Path)
+	}
+	return c, nil
+}
+
+// Save writes the config file
+func (c *Config) Save() error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.New("err: failed to marshal config")
+	}
+
+	err = os.MkdirAll(filepath.Dir(Path), 0o700)
+	if err != nil {
+		return errors.New("err: failed to create config directory")
+	}
+
+	err = os.WriteFile(Path, b, 0o600)
+	if err != nil {
+		return errors.New("err: failed to write config file")
+	}
+	return nil
+}
+
+// aliasPrefix is the key prefix of the location aliases, e.g. locations.edge-pops
+const aliasPrefix = "locations."
+
+// profilePrefix is the key prefix of the profiles, e.g. profiles.cdn-check.limit
+const profilePrefix = "profiles."
+
+// environmentPrefix is the key prefix of the environments, e.g. environments.staging.url
+const environmentPrefix = "environments."
+
+// ProfileTypes are the measurement types a profile can run
+var ProfileTypes = []string{"ping", "traceroute", "dns", "mtr", "http"}
+
+// Split a profiles.<name>.<key> key
+func profileKey(key string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(key, profilePrefix), ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.ContainsAny(parts[0], " ,@") {
+		return "", "", errors.New("profile keys are written profiles.<name>.<key>, names cannot contain commas, spaces or @")
+	}
+	return parts[0], parts[1], nil
+}
+
+// Get returns the value of a key
+func (c *Config) Get(key string) (string, error) {
+	if strings.HasPrefix(key, aliasPrefix) {
+		return c.Locations[strings.TrimPrefix(key, aliasPrefix)], nil
+	}
+	if strings.HasPrefix(key, profilePrefix) {
+		name, k, err := profileKey(key)
+		if err != nil {
+			return "", err
+		}
+		return c.Profiles[name][k], nil
+	}
+	if strings.HasPrefix(key, environmentPrefix) {
+		name, k, err := environmentKey(key)
+		if err != nil {
+			return "", err
+		}
+		if k == "url" {
+			return c.Environments[name].Url, nil
+		}
+		return c.Environments[name].Token, nil
+	}
+	k, ok := keys[key]
+	if !ok {
+		return "", fmt.Errorf("unknown config key: %s", key)
+	}
+	return k.get(c), nil
+}
+
+// Set validates and sets the value of a key, an empty value unsets it
+func (c *Config) Set(key, value string) error {
+	if strings.HasPrefix(key, aliasPrefix) {
+		name := strings.TrimPrefix(key, aliasPrefix)
+		if name == "" || strings.ContainsAny(name, ", @") {
+			return errors.New("location alias names cannot be empty or contain commas, spaces or @")
+		}
+		if value == "" {
+			delete(c.Locations, name)
+			return nil
+		}
+		if c.Locations == nil {
+			c.Locations = map[string]string{}
+		}
+		c.Locations[name] = value
+		return nil
+	}
+	if strings.HasPrefix(key, profilePrefix) {
+		return c.setProfile(key, value)
+	}
+	if strings.HasPrefix(key, environmentPrefix) {
+		return c.setEnvironment(key, value)
+	}
+	k, ok := keys[key]
+	if !ok {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+	return k.set(c, value)
+}
+
+// Set a key of a profile, a profile without keys is removed
+func (c *Config) setProfile(key, value string) error {
+	name, k, err := profileKey(key)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		delete(c.Profiles[name], k)
+		if len(c.Profiles[name]) == 0 {
+			delete(c.Profiles, name)
+		}
+		return nil
+	}
+	if k == "type" && !validProfileType(value) {
+		return fmt.Errorf("the type of a profile must be one of %s", strings.Join(ProfileTypes, ", "))
+	}
+
+	if c.Profiles == nil {
+		c.Profiles = map[string]map[string]string{}
+	}
+	if c.Profiles[name] == nil {
+		c.Profiles[name] = map[string]string{}
+	}
+	c.Profiles[name][k] = value
+	return nil
+}
+
+func validProfileType(t string) bool {
+	for _, v := range ProfileTypes {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Profile returns the keys of a profile, it must have a valid type
+func (c *Config) Profile(name string) (map[string]string, error) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q - define it with: globalping config set %s%s.type http", name, profilePrefix, name)
+	}
+	if !validProfileType(p["type"]) {
+		return nil, fmt.Errorf("the type of profile %q must be one of %s", name, strings.Join(ProfileTypes, ", "))
+	}
+	return p, nil
+}
+
+// Split an environments.<name>.<key> key, the key is url or token
+func environmentKey(key string) (string, string, error) {
+	name, k, ok := strings.Cut(strings.TrimPrefix(key, environmentPrefix), ".")
+	if !ok || name == "" || strings.ContainsAny(name, " ,") || (k != "url" && k != "token") {
+		return "", "", errors.New("environment keys are environments.<name>.url or environments.<name>.token, names cannot contain commas or spaces")
+	}
+	return name, k, nil
+}
+
+// Set the url or the token of an environment, an environment without both is removed
+func (c *Config) setEnvironment(key, value string) error {
+	name, k, err := environmentKey(key)
+	if err != nil {
+		return err
+	}
+
+	e := c.Environments[name]
+	if k == "url" {
+		e.Url = value
+	} else {
+		e.Token = value
+	}
+	if e == (Environment{}) {
+		delete(c.Environments, name)
+		return nil
+	}
+
+	if c.Environments == nil {
+		c.Environments = map[string]Environment{}
+	}
+	c.Environments[name] = e
+	return nil
+}
+
+// Environment returns a named environment, it must have a URL
+func (c *Config) Environment(name string) (Environment, error) {
+	e, ok := c.Environments[name]
+	if !ok {
+		return Environment{}, fmt.Errorf("unknown environment %q - define it with: globalping config set %s%s.url https://api.example.com/v1", name, environmentPrefix, name)
+	}
+	if e.Url == "" {
+		return Environment{}, fmt.Errorf("environment %q has no url - set it with: globalping config set %s%s.url https://api.example.com/v1", name, environmentPrefix, name)
+	}
+	return e, nil
+}
+
+// TimeoutDuration returns the parsed timeout, zero if unset
+func (c *Config) TimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.Timeout)
+	return d
+}
+
+// CacheTTLDuration returns the parsed cache TTL, zero if unset
+func (c *Config) CacheTTLDuration() time.Duration {
+	d, _ := time.ParseDuration(c.CacheTTL)
+	return d
+}
+
+// ExpandLocations replaces every @name entry of a comma separated list of locations with the locations of the alias
+func ExpandLocations(from string, aliases map[string]string) (string, error) {
+	if !strings.Contains(from, "@") {
+		return from, nil
+	}
+
+	parts := strings.Split(from, ",")
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		if !strings.HasPrefix(p, "@") {
+			continue
+		}
+		v, ok := aliases[p[1:]]
+		if !ok {
+			return "", fmt.Errorf("unknown location alias %q - define it with: globalping config set %s%s \"Germany,France\"", p, aliasPrefix, p[1:])
+		}
+		parts[i] = v
+	}
+	return strings.Join(parts, ","), nil
+}
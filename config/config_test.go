@@ -0,0 +1,152 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	config.Path = filepath.Join(t.TempDir(), "config.yml")
+
+	c, err := config.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, &config.Config{}, c)
+
+	assert.NoError(t, c.Set("from", "Europe"))
+	assert.NoError(t, c.Set("limit", "5"))
+	assert.NoError(t, c.Set("format", "json"))
+	assert.NoError(t, c.Set("api-url", "https://api.example.com/v1"))
+	assert.NoError(t, c.Set("timeout", "30s"))
+	assert.NoError(t, c.Save())
+
+	b, err := os.ReadFile(config.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, `from: Europe
+limit: 5
+format: json
+api-url: https://api.example.com/v1
+timeout: 30s
+`, string(b))
+
+	c, err = config.Load()
+	assert.NoError(t, err)
+	v, err := c.Get("limit")
+	assert.NoError(t, err)
+	assert.Equal(t, "5", v)
+	assert.Equal(t, 30*time.Second, c.TimeoutDuration())
+
+	assert.NoError(t, c.Set("limit", ""))
+	v, _ = c.Get("limit")
+	assert.Equal(t, "", v)
+}
+
+func TestConfigValidation(t *testing.T) {
+	c := &config.Config{}
+	assert.EqualError(t, c.Set("limit", "zero"), "limit must be a positive number")
+	assert.EqualError(t, c.Set("format", "xml"), "format must be one of json, latency, ci, "+strings.Join(client.FormatNames(), ", "))
+	for _, f := range client.FormatNames() {
+		assert.NoError(t, c.Set("format", f))
+	}
+	assert.EqualError(t, c.Set("timeout", "soon"), "timeout must be a duration, e.g. 30s")
+	assert.EqualError(t, c.Set("cache-ttl", "forever"), "cache-ttl must be a duration, e.g. 24h")
+	assert.EqualError(t, c.Set("fail-on-probe-errors", "some"), "fail-on-probe-errors must be one of any, all, none")
+	assert.EqualError(t, c.Set("color", "red"), "unknown config key: color")
+
+	_, err := c.Get("color")
+	assert.Error(t, err)
+}
+
+func TestConfigKeys(t *testing.T) {
+	assert.Equal(t, []string{"api-url", "cache-ttl", "env", "fail-on-probe-errors", "format", "from", "influxdb-bucket", "influxdb-org", "influxdb-token", "influxdb-url", "limit", "timeout"}, config.Keys())
+}
+
+func TestLocationAliases(t *testing.T) {
+	c := &config.Config{}
+	assert.NoError(t, c.Set("locations.edge-pops", "aws-eu-west-1,aws-us-east-1"))
+	v, err := c.Get("locations.edge-pops")
+	assert.NoError(t, err)
+	assert.Equal(t, "aws-eu-west-1,aws-us-east-1", v)
+	assert.EqualError(t, c.Set("locations.a,b", "Germany"), "location alias names cannot be empty or contain commas, spaces or @")
+
+	from, err := config.ExpandLocations("@edge-pops, Japan", c.Locations)
+	assert.NoError(t, err)
+	assert.Equal(t, "aws-eu-west-1,aws-us-east-1, Japan", from)
+
+	_, err = config.ExpandLocations("@unknown", c.Locations)
+	assert.EqualError(t, err, `unknown location alias "@unknown" - define it with: globalping config set locations.unknown "Germany,France"`)
+
+	assert.NoError(t, c.Set("locations.edge-pops", ""))
+	assert.Empty(t, c.Locations)
+}
+
+func TestProfiles(t *testing.T) {
+	config.Path = filepath.Join(t.TempDir(), "config.yml")
+	assert.NoError(t, os.WriteFile(config.Path, []byte(`profiles:
+  cdn-check:
+    type: http
+    from: eyeball network
+    limit: 20
+    expect-status: 200
+  broken:
+    limit: 3
+`), 0o600))
+
+	c, err := config.Load()
+	assert.NoError(t, err)
+	p, err := c.Profile("cdn-check")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"type": "http", "from": "eyeball network", "limit": "20", "expect-status": "200"}, p)
+
+	_, err = c.Profile("broken")
+	assert.EqualError(t, err, `the type of profile "broken" must be one of ping, traceroute, dns, mtr, http`)
+	_, err = c.Profile("missing")
+	assert.EqualError(t, err, `unknown profile "missing" - define it with: globalping config set profiles.missing.type http`)
+
+	assert.NoError(t, c.Set("profiles.dns-check.type", "dns"))
+	assert.NoError(t, c.Set("profiles.dns-check.resolver", "1.1.1.1"))
+	v, err := c.Get("profiles.dns-check.resolver")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.1.1", v)
+
+	assert.EqualError(t, c.Set("profiles.dns-check.type", "curl"), "the type of a profile must be one of ping, traceroute, dns, mtr, http")
+	assert.EqualError(t, c.Set("profiles.dns-check", "dns"), "profile keys are written profiles.<name>.<key>, names cannot contain commas, spaces or @")
+
+	// Unsetting every key removes the profile
+	assert.NoError(t, c.Set("profiles.dns-check.type", ""))
+	assert.NoError(t, c.Set("profiles.dns-check.resolver", ""))
+	assert.NotContains(t, c.Profiles, "dns-check")
+}
+
+func TestEnvironments(t *testing.T) {
+	c := &config.Config{}
+	assert.NoError(t, c.Set("environments.staging.url", "https://api.staging.example.com/v1"))
+	assert.NoError(t, c.Set("environments.staging.token", "secret"))
+	v, err := c.Get("environments.staging.token")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", v)
+
+	e, err := c.Environment("staging")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Environment{Url: "https://api.staging.example.com/v1", Token: "secret"}, e)
+
+	_, err = c.Environment("local")
+	assert.EqualError(t, err, `unknown environment "local" - define it with: globalping config set environments.local.url https://api.example.com/v1`)
+	assert.NoError(t, c.Set("environments.local.token", "secret"))
+	_, err = c.Environment("local")
+	assert.EqualError(t, err, `environment "local" has no url - set it with: globalping config set environments.local.url https://api.example.com/v1`)
+
+	assert.EqualError(t, c.Set("environments.staging.api-url", "x"), "environment keys are environments.<name>.url or environments.<name>.token, names cannot contain commas or spaces")
+
+	// Unsetting the url and the token removes the environment
+	assert.NoError(t, c.Set("environments.local.token", ""))
+	assert.NotContains(t, c.Environments, "local")
+	assert.Contains(t, c.Environments, "staging")
+}
@@ -0,0 +1,147 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a measurement recorded in the local history
+type Entry struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Target    string    `json:"target"`
+	From      string    `json:"from"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Filter narrows down the entries returned by List
+type Filter struct {
+	Type   string
+	Target string
+	// Last returns only the N most recent entries if set
+	Last int
+}
+
+// Maximum amount of entries kept in the history file, older entries are dropped
+const maxEntries = 1000
+
+// Path of the history file, overridable for tests
+var Path = defaultPath()
+
+func defaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".globalping", "history.json")
+	}
+	return filepath.Join(home, ".globalping", "history.json")
+}
+
+func load() ([]Entry, error) {
+	b, err := os.ReadFile(Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, errors.New("err: failed to read history file")
+	}
+
+	var entries []Entry
+	err = json.Unmarshal(b, &entries)
+	if err != nil {
+		return nil, errors.New("err: invalid history file format")
+	}
+	return entries, nil
+}
+
+func save(entries []Entry) error {
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return errors.New("err: failed to marshal history")
+	}
+
+	err = os.MkdirAll(filepath.Dir(Path), 0o700)
+	if err != nil {
+		return errors.New("err: failed to create history directory")
+	}
+
+	err = os.WriteFile(Path, b, 0o600)
+	if err != nil {
+		return errors.New("err: failed to write history file")
+	}
+	return nil
+}
+
+// Serializes the updates of the history file, measurements are recorded concurrently by serve
+var mu sync.Mutex
+
+// Add appends a measurement to the history
+func Add(entry Entry) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	entries, err := load()
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+
+	return save(entries)
+}
+
+// List returns the recorded measurements matching the filter, oldest first
+func List(f Filter) ([]Entry, error) {
+	entries, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []Entry
+	for _, e := range entries {
+		if f.Type != "" && !strings.EqualFold(e.Type, f.Type) {
+			continue
+		}
+		if f.Target != "" && !strings.Contains(e.Target, f.Target) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+
+	if f.Last > 0 && len(filtered) > f.Last {
+		filtered = filtered[len(filtered)-f.Last:]
+	}
+
+	return filtered, nil
+}
+
+// Find returns the entry with the given measurement ID
+func Find(id string) (Entry, bool, error) {
+	entries, err := load()
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	for _, e := range entries {
+		if e.ID == id {
+			return e, true, nil
+		}
+	}
+	return Entry{}, false, nil
+}
+
+// Clear removes every recorded measurement
+func Clear() error {
+	err := os.Remove(Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.New("err: failed to remove history file")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package history_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/history"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	history.Path = filepath.Join(t.TempDir(), "nested", "history.json")
+
+	entries, err := history.List(history.Filter{})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	now := time.Date(2023, 2, 17, 18, 11, 52, 0, time.UTC)
+	assert.NoError(t, history.Add(history.Entry{ID: "a", Type: "ping", Target: "google.com", From: "world", CreatedAt: now}))
+	assert.NoError(t, history.Add(history.Entry{ID: "b", Type: "dns", Target: "jsdelivr.com", From: "Europe", CreatedAt: now}))
+	assert.NoError(t, history.Add(history.Entry{ID: "c", Type: "ping", Target: "jsdelivr.com", From: "Asia", CreatedAt: now}))
+
+	entries, err = history.List(history.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, now, entries[0].CreatedAt)
+
+	entries, _ = history.List(history.Filter{Type: "PING"})
+	assert.Equal(t, []string{"a", "c"}, ids(entries))
+
+	entries, _ = history.List(history.Filter{Target: "jsdelivr"})
+	assert.Equal(t, []string{"b", "c"}, ids(entries))
+
+	entries, _ = history.List(history.Filter{Last: 1})
+	assert.Equal(t, []string{"c"}, ids(entries))
+
+	e, ok, err := history.Find("b")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Europe", e.From)
+
+	_, ok, _ = history.Find("z")
+	assert.False(t, ok)
+
+	assert.NoError(t, history.Clear())
+	entries, _ = history.List(history.Filter{})
+	assert.Empty(t, entries)
+}
+
+func ids(entries []history.Entry) []string {
+	var res []string
+	for _, e := range entries {
+		res = append(res, e.ID)
+	}
+	return res
+}
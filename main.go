@@ -5,8 +5,9 @@ import "github.com/jsdelivr/globalping-cli/cmd"
 var (
 	// https://goreleaser.com/cookbooks/using-main.version/
 	version = "dev"
+	commit  = ""
 )
 
 func main() {
-	cmd.Execute(version)
+	cmd.Execute(version, commit)
 }
@@ -0,0 +1,156 @@
+// Package manifest defines measurements in YAML files, it is the format of the batch files and of the schedule of
+// serve.
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is one measurement of a manifest
+//
+//	measurements:
+//	  - name: cdn
+//	    type: http
+//	    target: cdn.jsdelivr.net
+//	    from: Europe, North America
+//	    limit: 10
+//	    path: /npm/react
+//	    thresholds:
+//	      max-latency: 500ms
+//	      expect-status: 200
+type Entry struct {
+	// Name identifies the measurement in reports and logs, defaults to the type and the target
+	Name   string `yaml:"name"`
+	Type   string `yaml:"type"`
+	Target string `yaml:"target"`
+	From   string `yaml:"from"`
+	Limit  int    `yaml:"limit"`
+	// Options of the measurement, only used by the types supporting them
+	Packets  int    `yaml:"packets"`
+	Protocol string `yaml:"protocol"`
+	Port     int    `yaml:"port"`
+	Path     string `yaml:"path"`
+	Method   string `yaml:"method"`
+	Query    string `yaml:"query"`
+	Resolver string `yaml:"resolver"`
+	// QueryType is the record type of a dns measurement, e.g. AAAA
+	QueryType string `yaml:"query-type"`
+
+	Thresholds Thresholds `yaml:"thresholds"`
+}
+
+// Thresholds are the limits every probe result must respect, zero values are not checked except an explicit max-loss
+// of 0, which fails on any loss
+type Thresholds struct {
+	MaxLatency   time.Duration `yaml:"max-latency"`
+	MaxLoss      *float64      `yaml:"max-loss"`
+	ExpectStatus int           `yaml:"expect-status"`
+}
+
+// File is a list of measurements, e.g. of the batch command
+type File struct {
+	Measurements []Entry `yaml:"measurements"`
+}
+
+// Read decodes a YAML file into v
+func Read(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("err: failed to read %s", path)
+	}
+	err = yaml.Unmarshal(b, v)
+	if err != nil {
+		return fmt.Errorf("err: invalid manifest %s: %s", path, err)
+	}
+	return nil
+}
+
+// Load reads a manifest and validates its measurements
+func Load(path string) (File, error) {
+	var f File
+	err := Read(path, &f)
+	if err != nil {
+		return f, err
+	}
+	if len(f.Measurements) == 0 {
+		return f, fmt.Errorf("err: %s has no measurements", path)
+	}
+
+	names := map[string]bool{}
+	for i := range f.Measurements {
+		err = f.Measurements[i].Validate(i + 1)
+		if err != nil {
+			return f, err
+		}
+		if names[f.Measurements[i].Name] {
+			return f, fmt.Errorf("err: measurement %d: duplicate name %q", i+1, f.Measurements[i].Name)
+		}
+		names[f.Measurements[i].Name] = true
+	}
+	return f, nil
+}
+
+// Validate checks the entry at the given position of its manifest, starting at 1, and fills in the defaults
+func (e *Entry) Validate(n int) error {
+	switch e.Type {
+	case "ping", "traceroute", "dns", "mtr", "http":
+	default:
+		return fmt.Errorf("err: measurement %d: unsupported type %q", n, e.Type)
+	}
+	if e.Target == "" {
+		return fmt.Errorf("err: measurement %d: the target is required", n)
+	}
+	if e.Name == "" {
+		e.Name = e.Type + " " + e.Target
+	}
+	if e.From == "" {
+		e.From = "world"
+	}
+	if e.Limit == 0 {
+		e.Limit = 1
+	}
+	return nil
+}
+
+// PostMeasurement builds the measurement posted for the entry
+func (e Entry) PostMeasurement() model.PostMeasurement {
+	m := client.ApplyLocationLimits(model.PostMeasurement{
+		Type:      e.Type,
+		Target:    e.Target,
+		Limit:     e.Limit,
+		Locations: client.ParseLocations(e.From),
+	})
+
+	opts := model.MeasurementOptions{Packets: e.Packets, Protocol: strings.ToUpper(e.Protocol), Port: e.Port, Resolver: e.Resolver}
+	if e.Type == "http" && (e.Path != "" || e.Method != "" || e.Query != "") {
+		opts.Request = &model.RequestOptions{Path: e.Path, Method: strings.ToUpper(e.Method), Query: e.Query}
+	}
+	if e.Type == "dns" && e.QueryType != "" {
+		opts.Query = &model.QueryOptions{Type: strings.ToUpper(e.QueryType)}
+	}
+	if opts != (model.MeasurementOptions{}) {
+		m.Options = &opts
+	}
+	return m
+}
+
+// Context of the results of the entry, used by the threshold checks, the logs and the webhooks
+func (e Entry) Context() model.Context {
+	return model.Context{
+		Cmd:    e.Type,
+		Target: e.Target,
+		From:   e.From,
+		Thresholds: model.Thresholds{
+			MaxLatency:   e.Thresholds.MaxLatency,
+			MaxLoss:      e.Thresholds.MaxLoss,
+			ExpectStatus: e.Thresholds.ExpectStatus,
+		},
+	}
+}
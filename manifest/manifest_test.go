@@ -0,0 +1,95 @@
+package manifest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/manifest"
+	"github.com/jsdelivr/globalping-cli/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeManifest(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "measurements.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	f, err := manifest.Load(writeManifest(t, `measurements:
+  - name: cdn
+    type: http
+    target: cdn.jsdelivr.net
+    from: Europe, North America
+    limit: 10
+    path: /npm/react
+    method: head
+    thresholds:
+      max-latency: 500ms
+      expect-status: 200
+  - type: ping
+    target: jsdelivr.com
+    from: Germany:2, Japan
+    limit: 3
+    packets: 5
+  - type: dns
+    target: jsdelivr.com
+    query-type: aaaa
+    resolver: 1.1.1.1
+`))
+	assert.NoError(t, err)
+	assert.Len(t, f.Measurements, 3)
+
+	cdn := f.Measurements[0]
+	assert.Equal(t, "cdn", cdn.Name)
+	assert.Equal(t, model.PostMeasurement{
+		Type:      "http",
+		Target:    "cdn.jsdelivr.net",
+		Limit:     10,
+		Locations: []model.Locations{{Magic: "Europe"}, {Magic: "North America"}},
+		Options:   &model.MeasurementOptions{Request: &model.RequestOptions{Path: "/npm/react", Method: "HEAD"}},
+	}, cdn.PostMeasurement())
+	assert.Equal(t, model.Thresholds{MaxLatency: 500 * time.Millisecond, ExpectStatus: 200}, cdn.Context().Thresholds)
+
+	ping := f.Measurements[1]
+	assert.Equal(t, "ping jsdelivr.com", ping.Name)
+	assert.Equal(t, model.PostMeasurement{
+		Type:      "ping",
+		Target:    "jsdelivr.com",
+		Limit:     5,
+		Locations: []model.Locations{{Magic: "Germany", Limit: 2}, {Magic: "Japan", Limit: 3}},
+		Options:   &model.MeasurementOptions{Packets: 5},
+	}, ping.PostMeasurement())
+
+	dns := f.Measurements[2]
+	assert.Equal(t, model.PostMeasurement{
+		Type:      "dns",
+		Target:    "jsdelivr.com",
+		Limit:     1,
+		Locations: []model.Locations{{Magic: "world"}},
+		Options:   &model.MeasurementOptions{Resolver: "1.1.1.1", Query: &model.QueryOptions{Type: "AAAA"}},
+	}, dns.PostMeasurement())
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := manifest.Load(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.ErrorContains(t, err, "err: failed to read")
+
+	_, err = manifest.Load(writeManifest(t, "measurements: {"))
+	assert.ErrorContains(t, err, "err: invalid manifest")
+
+	_, err = manifest.Load(writeManifest(t, "measurements: []"))
+	assert.ErrorContains(t, err, "has no measurements")
+
+	_, err = manifest.Load(writeManifest(t, "measurements:\n  - type: curl\n    target: jsdelivr.com\n"))
+	assert.EqualError(t, err, `err: measurement 1: unsupported type "curl"`)
+
+	_, err = manifest.Load(writeManifest(t, "measurements:\n  - type: ping\n"))
+	assert.EqualError(t, err, "err: measurement 1: the target is required")
+
+	_, err = manifest.Load(writeManifest(t, "measurements:\n  - type: ping\n    target: a.com\n  - type: ping\n    target: a.com\n"))
+	assert.EqualError(t, err, `err: measurement 2: duplicate name "ping a.com"`)
+}
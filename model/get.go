@@ -1,6 +1,9 @@
 package model
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+)
 
 // Modeled from https://github.com/jsdelivr/globalping/blob/master/docs/measurement/get.md
 
@@ -13,6 +16,8 @@ type ProbeData struct {
 	ASN       int      `json:"asn"`
 	Network   string   `json:"network,omitempty"`
 	Tags      []string `json:"tags,omitempty"`
+	Latitude  float64  `json:"latitude,omitempty"`
+	Longitude float64  `json:"longitude,omitempty"`
 }
 
 type ResultData struct {
@@ -20,13 +25,165 @@ type ResultData struct {
 	RawOutput        string                 `json:"rawOutput"`
 	ResolvedAddress  string                 `json:"resolvedAddress"`
 	ResolvedHostname string                 `json:"resolvedHostname"`
-	Stats            map[string]interface{} `json:"stats,omitempty"`
-	TimingsRaw       json.RawMessage        `json:"timings,omitempty"`
+	StatusCode       int                    `json:"statusCode,omitempty"`
+	StatusCodeName   string                 `json:"statusCodeName,omitempty"`
+	RawBody          string                 `json:"rawBody,omitempty"`
+	Headers          map[string]interface{} `json:"headers,omitempty"`
+	TLS              *TLSCertificate        `json:"tls,omitempty"`
+	Answers          []DnsAnswer            `json:"answers,omitempty"`
+	Resolver         string                 `json:"resolver,omitempty"`
+	// RawHeaders are the response headers of an http result as received, one "Name: value" line per header
+	RawHeaders string `json:"rawHeaders,omitempty"`
+	// Hops of a traceroute or mtr result, or the delegation path of a dns result with trace enabled
+	Hops []Hop `json:"hops,omitempty"`
+	// Stats of a ping result
+	Stats *PingStats `json:"stats,omitempty"`
+	// Timings of a ping, dns or http result
+	Timings *Timings `json:"timings,omitempty"`
 }
 
+// PingStats are the packet statistics of a ping result, the latencies are nil if no packet was received
+type PingStats struct {
+	Min   *float64 `json:"min"`
+	Avg   *float64 `json:"avg"`
+	Max   *float64 `json:"max"`
+	Total int      `json:"total"`
+	Rcv   int      `json:"rcv"`
+	Drop  int      `json:"drop"`
+	Loss  float64  `json:"loss"`
+}
+
+// PacketTiming is the round trip time of a ping packet or of a packet sent to a traceroute or mtr hop
+type PacketTiming struct {
+	TTL int     `json:"ttl,omitempty"`
+	RTT float64 `json:"rtt"`
+}
+
+// Timings are returned as the list of packets of a ping result or a hop, or as the duration of every phase of a dns
+// query or an http request in milliseconds, a phase is nil if it did not happen, e.g. TLS without https
 type Timings struct {
-	Arr       []map[string]interface{}
-	Interface map[string]interface{}
+	Packets   []PacketTiming
+	Total     *float64
+	DNS       *float64
+	TCP       *float64
+	TLS       *float64
+	FirstByte *float64
+	Download  *float64
+}
+
+// JSON object of the timings of a dns query or an http request
+type phaseTimings struct {
+	Total     *float64 `json:"total,omitempty"`
+	DNS       *float64 `json:"dns,omitempty"`
+	TCP       *float64 `json:"tcp,omitempty"`
+	TLS       *float64 `json:"tls,omitempty"`
+	FirstByte *float64 `json:"firstByte,omitempty"`
+	Download  *float64 `json:"download,omitempty"`
+}
+
+func (t *Timings) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '[' {
+		*t = Timings{}
+		return json.Unmarshal(b, &t.Packets)
+	}
+	var p phaseTimings
+	err := json.Unmarshal(b, &p)
+	if err != nil {
+		return err
+	}
+	*t = Timings{Total: p.Total, DNS: p.DNS, TCP: p.TCP, TLS: p.TLS, FirstByte: p.FirstByte, Download: p.Download}
+	return nil
+}
+
+func (t Timings) MarshalJSON() ([]byte, error) {
+	if t.Packets != nil {
+		return json.Marshal(t.Packets)
+	}
+	return json.Marshal(phaseTimings{Total: t.Total, DNS: t.DNS, TCP: t.TCP, TLS: t.TLS, FirstByte: t.FirstByte, Download: t.Download})
+}
+
+// RTTs returns the round trip times of the packets, nil timings have none
+func (t *Timings) RTTs() []float64 {
+	if t == nil {
+		return nil
+	}
+	rtts := make([]float64, len(t.Packets))
+	for i, p := range t.Packets {
+		rtts[i] = p.RTT
+	}
+	return rtts
+}
+
+// TotalMs returns the total duration of a dns query or an http request, false if unknown
+func (t *Timings) TotalMs() (float64, bool) {
+	if t == nil || t.Total == nil {
+		return 0, false
+	}
+	return *t.Total, true
+}
+
+// Phases returns the duration of every phase of a dns query or an http request by its name in the API, e.g. firstByte
+func (t *Timings) Phases() map[string]*float64 {
+	if t == nil {
+		return nil
+	}
+	return map[string]*float64{"total": t.Total, "dns": t.DNS, "tcp": t.TCP, "tls": t.TLS, "firstByte": t.FirstByte, "download": t.Download}
+}
+
+// DnsAnswer is a record of the answer section of a dns result
+type DnsAnswer struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	TTL   int    `json:"ttl"`
+	Class string `json:"class"`
+	Value string `json:"value"`
+}
+
+// Hop is a router on the path of a traceroute or mtr result, or a name server of the delegation path of a dns result
+// with trace enabled
+type Hop struct {
+	ResolvedAddress  string `json:"resolvedAddress,omitempty"`
+	ResolvedHostname string `json:"resolvedHostname,omitempty"`
+	// ASN and Stats of an mtr hop
+	ASN   []int     `json:"asn,omitempty"`
+	Stats *MtrStats `json:"stats,omitempty"`
+	// Resolver and Answers of a dns hop
+	Resolver string      `json:"resolver,omitempty"`
+	Answers  []DnsAnswer `json:"answers,omitempty"`
+	Timings  *Timings    `json:"timings,omitempty"`
+}
+
+// MtrStats are the stats of the packets sent to an mtr hop
+type MtrStats struct {
+	Min   float64 `json:"min"`
+	Avg   float64 `json:"avg"`
+	Max   float64 `json:"max"`
+	StDev float64 `json:"stDev"`
+	JMin  float64 `json:"jMin"`
+	JAvg  float64 `json:"jAvg"`
+	JMax  float64 `json:"jMax"`
+	Total int     `json:"total"`
+	Rcv   int     `json:"rcv"`
+	Drop  int     `json:"drop"`
+	Loss  float64 `json:"loss"`
+}
+
+// TLSCertificate is the certificate presented to the probe by an HTTPS server
+type TLSCertificate struct {
+	Authorized bool   `json:"authorized"`
+	Error      string `json:"error,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+	ExpiresAt  string `json:"expiresAt"`
+	Subject    struct {
+		CommonName string `json:"CN"`
+		AltNames   string `json:"alt"`
+	} `json:"subject"`
+	Issuer struct {
+		Country      string `json:"C"`
+		Organization string `json:"O"`
+		CommonName   string `json:"CN"`
+	} `json:"issuer"`
 }
 
 // Nested structs
@@ -44,4 +201,10 @@ type GetMeasurement struct {
 	UpdatedAt   string                `json:"updatedAt"`
 	ProbesCount int                   `json:"probesCount"`
 	Results     []MeasurementResponse `json:"results"`
+
+	// Original request, used to run the same measurement again
+	Target             string              `json:"target,omitempty"`
+	Limit              int                 `json:"limit,omitempty"`
+	Locations          []Locations         `json:"locations,omitempty"`
+	MeasurementOptions *MeasurementOptions `json:"measurementOptions,omitempty"`
 }
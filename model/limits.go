@@ -0,0 +1,38 @@
+package model
+
+// Modeled from https://github.com/jsdelivr/globalping/blob/master/docs/limits.md
+
+type RateLimitDetails struct {
+	Type      string `json:"type"`
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Reset     int    `json:"reset"`
+}
+
+type CreditLimits struct {
+	Remaining int `json:"remaining"`
+}
+
+// Main struct
+type Limits struct {
+	RateLimit struct {
+		Measurements struct {
+			Create RateLimitDetails `json:"create"`
+		} `json:"measurements"`
+	} `json:"rateLimit"`
+	Credits *CreditLimits `json:"credits,omitempty"`
+}
+
+// RateLimit is parsed from the X-RateLimit-* and X-Credits-* headers of API responses
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	// Reset is the number of seconds until the rate limit resets
+	Reset int
+	// Cost is the amount of rate limit points the request consumed
+	Cost             int
+	CreditsConsumed  int
+	CreditsRemaining int
+	// Set is true if the response included rate limit headers
+	Set bool
+}
@@ -4,7 +4,16 @@ package model
 
 // Nested structs
 type Locations struct {
-	Magic string `json:"magic"`
+	Continent string   `json:"continent,omitempty"`
+	Region    string   `json:"region,omitempty"`
+	Country   string   `json:"country,omitempty"`
+	State     string   `json:"state,omitempty"`
+	City      string   `json:"city,omitempty"`
+	ASN       int      `json:"asn,omitempty"`
+	Network   string   `json:"network,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Magic     string   `json:"magic,omitempty"`
+	Limit     int      `json:"limit,omitempty"`
 }
 
 type QueryOptions struct {
@@ -27,6 +36,8 @@ type MeasurementOptions struct {
 	Resolver string          `json:"resolver,omitempty"`
 	Trace    bool            `json:"trace,omitempty"`
 	Packets  int             `json:"packets,omitempty"`
+	// IPVersion forces the address family a hostname target is resolved to, 4 or 6
+	IPVersion int `json:"ipVersion,omitempty"`
 }
 
 // Main struct
@@ -36,11 +47,15 @@ type PostMeasurement struct {
 	Type      string              `json:"type"`
 	Target    string              `json:"target"`
 	Options   *MeasurementOptions `json:"measurementOptions,omitempty"`
+	// InProgressUpdates makes the API return the partial output of the probes while the measurement is in progress
+	InProgressUpdates bool `json:"inProgressUpdates,omitempty"`
 }
 
 type PostResponse struct {
 	ID          string `json:"id"`
 	ProbesCount int    `json:"probesCount"`
+	// RateLimit holds the rate limit headers of the response, it is not part of the body
+	RateLimit RateLimit `json:"-"`
 }
 
 type PostError struct {
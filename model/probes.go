@@ -0,0 +1,23 @@
+package model
+
+// Modeled from https://github.com/jsdelivr/globalping/blob/master/docs/probes.md
+
+type ProbeLocation struct {
+	Continent string  `json:"continent"`
+	Region    string  `json:"region"`
+	Country   string  `json:"country"`
+	State     string  `json:"state,omitempty"`
+	City      string  `json:"city"`
+	ASN       int     `json:"asn"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Network   string  `json:"network"`
+}
+
+// Main struct
+type Probe struct {
+	Version   string        `json:"version"`
+	Location  ProbeLocation `json:"location"`
+	Tags      []string      `json:"tags"`
+	Resolvers []string      `json:"resolvers"`
+}
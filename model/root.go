@@ -1,15 +1,107 @@
 package model
 
+import "time"
+
 // Used in thc client TUI
 type Context struct {
 	Cmd    string
 	Target string
-	From   string
-	Limit  int
+	// Targets holds every target when several are measured in one invocation
+	Targets []string
+	From    string
+	Limit   int
 	// JsonOutput is a flag that determines whether the output should be in JSON format.
 	JsonOutput bool
 	// Latency is a flag that outputs only stats of a measurement
 	Latency bool
 	// CI flag is used to determine whether the output should be in a format that is easy to parse by a CI tool
 	CI bool
+	// CIProvider enables provider specific output in CI, e.g. GitHub Actions annotations
+	CIProvider string
+	// Format selects an alternative output format, e.g. prometheus
+	Format string
+	// Thresholds evaluated once the measurement is finished, a violation sets a non-zero exit code
+	Thresholds Thresholds
+	// Watch runs the measurement again every Interval until interrupted
+	Watch    bool
+	Interval time.Duration
+	// Infinite flag keeps running ping measurements until the user interrupts the CLI
+	Infinite bool
+	// Share prints the globalping.io URL of the results and copies it to the clipboard
+	Share bool
+	// Copy is the artifact copied to the clipboard once the measurement is finished: url, json or markdown
+	Copy string
+	// Summary prints the latency distribution and packet loss across all probes
+	Summary bool
+	// Map prints a grid of the continents with the median latency of every continent and region
+	Map bool
+	// NoEnrich disables the ASN lookup of traceroute hops
+	NoEnrich bool
+	// ShowBody prints the response body of every probe of an http measurement
+	ShowBody bool
+	// SaveBody is the path response bodies are saved to, one file per probe
+	SaveBody string
+	// BodyLimit is the maximum number of bytes of a response body printed or saved, zero means no limit
+	BodyLimit int
+	// Output is the file the formatted results are written to instead of stdout, "-" is stdout
+	Output string
+	// Quiet prints only the final metrics of the measurement instead of the output of every probe
+	Quiet bool
+	// Live requests the partial output of the probes and prints new lines as they arrive
+	Live bool
+	// Analyze prints the response headers of every probe of an http measurement with their cache, compression and
+	// HSTS analysis
+	Analyze bool
+	// AnswerFilter keeps only the dns answers of these record types, e.g. A and CNAME
+	AnswerFilter []string
+	// Selection narrows down and orders the probes displayed, thresholds and exports still use every probe
+	Selection Selection
+	// GroupBy displays the metrics aggregated by country, continent, asn or network instead of every probe
+	GroupBy string
+	// Query is a jq query run on the results, its values are printed instead of the results
+	Query string
+	// Template is a Go template executed for every probe instead of the default output
+	Template string
+}
+
+// Selection is the post-processing of the displayed results, zero values keep every probe in the API order
+type Selection struct {
+	// Sort orders the probes by latency, country or network
+	Sort string
+	// Filters keeps the probes matching every field, e.g. country=DE, with any of the values of the field
+	Filters map[string][]string
+	// Top and Worst keep the given number of probes with the lowest or the highest latency
+	Top   int
+	Worst int
+}
+
+// Enabled returns true if the displayed results are narrowed down or ordered
+func (s Selection) Enabled() bool {
+	return s.Sort != "" || len(s.Filters) > 0 || s.Top > 0 || s.Worst > 0
+}
+
+// Thresholds are the limits every probe result must respect, zero or nil values are not checked
+type Thresholds struct {
+	MaxLatency time.Duration
+	// MaxLoss is the maximum packet loss percentage, nil disables the check and 0 fails on any loss
+	MaxLoss      *float64
+	ExpectStatus int
+	// Assertions on the http response of every probe, header values must contain the expected value
+	ExpectBodyContains string
+	ExpectHeaders      map[string]string
+	// MinDaysValid is the minimum number of days before the TLS certificate of an https target expires
+	MinDaysValid int
+	// FailOnProbeErrors is the policy failing the run when probes fail, any, all or none
+	FailOnProbeErrors string
+}
+
+// Enabled returns true if at least one threshold is set
+func (t Thresholds) Enabled() bool {
+	return t.MaxLatency > 0 || t.MaxLoss != nil || t.ExpectStatus > 0 || t.Assertions() || t.MinDaysValid > 0 ||
+		(t.FailOnProbeErrors != "" && t.FailOnProbeErrors != "none")
+}
+
+// Assertions returns true if the content of http responses is checked
+func (t Thresholds) Assertions() bool {
+	return t.ExpectBodyContains != "" || len(t.ExpectHeaders) > 0
 }
@@ -0,0 +1,260 @@
+// Package globalping is a Go client of the Globalping API, it lets other programs create measurements and wait for
+// their results without running the CLI. The CLI itself sends every API request through this package.
+package globalping
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// DefaultBaseURL is the base URL of the public Globalping API
+const DefaultBaseURL = "https://api.globalping.io/v1"
+
+const userAgent = "Globalping API Go Client / v1"
+
+// ErrInvalidResponse is returned when a successful response of the API cannot be decoded
+var ErrInvalidResponse = errors.New("globalping: invalid response")
+
+// Client calls the Globalping API, the zero value is not usable, create one with NewClient
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	userAgent  string
+	retry      RetryPolicy
+	logf       func(format string, args ...interface{})
+}
+
+// Option configures a Client
+type Option func(c *Client)
+
+// WithBaseURL points the client to another API, e.g. a self-hosted one
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(url, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for every request, e.g. to configure a proxy or timeouts
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = h
+	}
+}
+
+// WithToken sends an API token with every request, registered users get higher rate limits
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithUserAgent overrides the User-Agent header of every request
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithRetry retries the requests failing with a transient error, requests are not retried by default
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithLogger receives a message for every posted measurement, poll, retry and rate limit response
+func WithLogger(logf func(format string, args ...interface{})) Option {
+	return func(c *Client) {
+		c.logf = logf
+	}
+}
+
+// NewClient creates a client of the public API, options override the defaults
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: http.DefaultClient,
+		userAgent:  userAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) log(format string, args ...interface{}) {
+	if c.logf != nil {
+		c.logf(format, args...)
+	}
+}
+
+// Create a request with the headers shared by every call
+func (c *Client) newRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return req, nil
+}
+
+// Send a GET request and decode the JSON response into out
+func (c *Client) getJSON(ctx context.Context, path string, out interface{}) error {
+	req, err := c.newRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return responseError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+	}
+	return nil
+}
+
+// CreateMeasurement posts a measurement and returns its ID, the results are fetched with GetMeasurement or Wait
+func (c *Client) CreateMeasurement(ctx context.Context, m model.PostMeasurement) (model.PostResponse, error) {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return model.PostResponse{}, err
+	}
+	return c.CreateMeasurementJSON(ctx, body)
+}
+
+// CreateMeasurementJSON posts the JSON body of a measurement as is, e.g. with options the builders do not support
+func (c *Client) CreateMeasurementJSON(ctx context.Context, body []byte) (model.PostResponse, error) {
+	req, err := c.newRequest(ctx, "POST", "/measurements", body)
+	if err != nil {
+		return model.PostResponse{}, err
+	}
+	c.log("POST %s %s", req.URL, body)
+
+	resp, err := c.send(req)
+	if err != nil {
+		return model.PostResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	rl := parseRateLimit(resp.Header)
+	if rl.Set {
+		c.log("Rate limit: %d of %d remaining, resets in %ds, cost %d", rl.Remaining, rl.Limit, rl.Reset, rl.Cost)
+	}
+
+	if resp.StatusCode != http.StatusAccepted {
+		apiErr := responseError(resp)
+		apiErr.RateLimit = rl
+		return model.PostResponse{}, apiErr
+	}
+
+	var data model.PostResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return model.PostResponse{}, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+	}
+	data.RateLimit = rl
+	return data, nil
+}
+
+// GetMeasurementJSON returns the JSON of a measurement as sent by the API. With the ETag of a previous response,
+// raw is nil if the measurement did not change since, the API then answers 304 Not Modified without a body.
+func (c *Client) GetMeasurementJSON(ctx context.Context, id, etag string) (raw []byte, newEtag string, err error) {
+	req, err := c.newRequest(ctx, "GET", "/measurements/"+id, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		c.log("GET %s: not modified", req.URL)
+		return nil, etag, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", responseError(resp)
+	}
+
+	raw, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return raw, resp.Header.Get("ETag"), nil
+}
+
+// GetMeasurement returns the current state of a measurement, results are partial while it is in progress
+func (c *Client) GetMeasurement(ctx context.Context, id string) (model.GetMeasurement, error) {
+	raw, _, err := c.GetMeasurementJSON(ctx, id, "")
+	if err != nil {
+		return model.GetMeasurement{}, err
+	}
+	var data model.GetMeasurement
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return model.GetMeasurement{}, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+	}
+	return data, nil
+}
+
+// Measure creates a measurement and waits for its results
+func (c *Client) Measure(ctx context.Context, m model.PostMeasurement) (model.GetMeasurement, error) {
+	res, err := c.CreateMeasurement(ctx, m)
+	if err != nil {
+		return model.GetMeasurement{}, err
+	}
+	return c.Wait(ctx, res.ID, 0)
+}
+
+// Probes returns the online probes
+func (c *Client) Probes(ctx context.Context) ([]model.Probe, error) {
+	var data []model.Probe
+	err := c.getJSON(ctx, "/probes", &data)
+	return data, err
+}
+
+// Limits returns the rate limits and the credits of the client
+func (c *Client) Limits(ctx context.Context) (model.Limits, error) {
+	var data model.Limits
+	err := c.getJSON(ctx, "/limits", &data)
+	return data, err
+}
+
+// Reachable sends one request to the base URL of the API, without retries, and returns the status of the response.
+// Any response means the API is reachable
+func (c *Client) Reachable(ctx context.Context) (int, error) {
+	req, err := c.newRequest(ctx, "GET", "", nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,112 @@
+package globalping_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/pkg/globalping"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilders(t *testing.T) {
+	assert.Equal(t, model.PostMeasurement{
+		Type:      "ping",
+		Target:    "jsdelivr.com",
+		Limit:     1,
+		Locations: []model.Locations{{Magic: "world"}},
+	}, globalping.Ping("jsdelivr.com"))
+
+	m := globalping.Http("jsdelivr.com", globalping.From("Germany", "AS3320"), globalping.Limit(3), globalping.Protocol("https"),
+		globalping.Method("get"), globalping.Path("/npm/react"), globalping.Header("Accept", "text/html"))
+	assert.Equal(t, []model.Locations{{Magic: "Germany"}, {Magic: "AS3320"}}, m.Locations)
+	assert.Equal(t, 3, m.Limit)
+	assert.Equal(t, "HTTPS", m.Options.Protocol)
+	assert.Equal(t, &model.RequestOptions{Method: "GET", Path: "/npm/react", Headers: map[string]string{"Accept": "text/html"}}, m.Options.Request)
+
+	d := globalping.Dns("jsdelivr.com", globalping.QueryType("aaaa"), globalping.Resolver("1.1.1.1"), globalping.Trace())
+	assert.Equal(t, &model.MeasurementOptions{Query: &model.QueryOptions{Type: "AAAA"}, Resolver: "1.1.1.1", Trace: true}, d.Options)
+}
+
+func TestMeasure(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/measurements":
+			var m model.PostMeasurement
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&m))
+			assert.Equal(t, "ping", m.Type)
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+		case r.URL.Path == "/v1/measurements/abcd":
+			polls++
+			status := "in-progress"
+			if polls > 1 {
+				status = "finished"
+			}
+			w.Write([]byte(`{"id":"abcd","status":"` + status + `","results":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := globalping.NewClient(globalping.WithBaseURL(server.URL+"/v1/"), globalping.WithToken("secret"))
+	res, err := c.CreateMeasurement(context.Background(), globalping.Ping("jsdelivr.com"))
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", res.ID)
+
+	data, err := c.Wait(context.Background(), res.ID, time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, "finished", data.Status)
+	assert.Equal(t, 2, polls)
+}
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":{"type":"no_probes_found","message":"No suitable probes found."}}`))
+	}))
+	defer server.Close()
+
+	c := globalping.NewClient(globalping.WithBaseURL(server.URL))
+	_, err := c.Measure(context.Background(), globalping.Ping("jsdelivr.com", globalping.From("Antarctica")))
+	assert.EqualError(t, err, "No suitable probes found.")
+	assert.True(t, globalping.IsNoProbesError(err))
+
+	var apiErr *globalping.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+}
+
+func TestRetryAndRateLimit(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "250")
+		w.Header().Set("X-RateLimit-Remaining", "249")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+	}))
+	defer server.Close()
+
+	var logs []string
+	c := globalping.NewClient(globalping.WithBaseURL(server.URL), globalping.WithRetry(globalping.RetryPolicy{Count: 1}),
+		globalping.WithLogger(func(format string, args ...interface{}) { logs = append(logs, fmt.Sprintf(format, args...)) }))
+	res, err := c.CreateMeasurement(context.Background(), globalping.Ping("jsdelivr.com"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 249, res.RateLimit.Remaining)
+	assert.Contains(t, logs[1], "failed (status 503), retrying in 0s (attempt 2 of 2)")
+}
@@ -0,0 +1,97 @@
+package globalping
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Error types returned by the API
+const (
+	ErrorTypeNoProbes   = "no_probes_found"
+	ErrorTypeValidation = "validation_error"
+	ErrorTypeApi        = "api_error"
+	ErrorTypeRateLimit  = "too_many_requests"
+	ErrorTypeNotFound   = "not_found"
+)
+
+// APIError is an error response of the API
+type APIError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Type is the error type of the API, e.g. validation_error, or the status text if the response has no type
+	Type string
+	// Message explains the error, the message of the API unless the caller replaced it
+	Message string
+	// Params holds the reason of every invalid field of a validation error
+	Params map[string]string
+	// RateLimit holds the rate limit headers of the response
+	RateLimit model.RateLimit
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("globalping: %s (status %d)", e.Type, e.StatusCode)
+	}
+	return e.Message
+}
+
+// Usage returns true if the error is caused by the measurement, e.g. an invalid option or a location without probes
+func (e *APIError) Usage() bool {
+	return e.Type == ErrorTypeNoProbes || e.Type == ErrorTypeValidation
+}
+
+// IsNoProbesError returns true if the API found no probe matching the locations of the measurement
+func IsNoProbesError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Type == ErrorTypeNoProbes
+}
+
+// Build the error of a response with an unexpected status, the type and the message are read from the body
+func responseError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	var data model.PostError
+	if json.NewDecoder(resp.Body).Decode(&data) == nil {
+		apiErr.Type = data.Error.Type
+		apiErr.Message = data.Error.Message
+		if len(data.Error.Params) > 0 {
+			apiErr.Params = make(map[string]string, len(data.Error.Params))
+			for k, v := range data.Error.Params {
+				apiErr.Params[k] = fmt.Sprint(v)
+			}
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		apiErr.Type = ErrorTypeRateLimit
+	}
+	if apiErr.Type == "" {
+		apiErr.Type = strings.ToLower(strings.ReplaceAll(http.StatusText(resp.StatusCode), " ", "_"))
+	}
+	return apiErr
+}
+
+// Parse the X-RateLimit-* and X-Credits-* response headers, missing headers are left at zero
+func parseRateLimit(h http.Header) model.RateLimit {
+	var rl model.RateLimit
+	for name, dst := range map[string]*int{
+		"X-RateLimit-Limit":     &rl.Limit,
+		"X-RateLimit-Remaining": &rl.Remaining,
+		"X-RateLimit-Reset":     &rl.Reset,
+		"X-Request-Cost":        &rl.Cost,
+		"X-Credits-Consumed":    &rl.CreditsConsumed,
+		"X-Credits-Remaining":   &rl.CreditsRemaining,
+	} {
+		v, err := strconv.Atoi(h.Get(name))
+		if err == nil {
+			*dst = v
+			rl.Set = true
+		}
+	}
+	return rl
+}
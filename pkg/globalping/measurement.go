@@ -0,0 +1,164 @@
+package globalping
+
+import (
+	"strings"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// MeasurementOption configures a measurement built by Ping, Traceroute, Dns, Mtr or Http
+type MeasurementOption func(m *model.PostMeasurement)
+
+// Build a measurement running from anywhere on one probe unless options say otherwise
+func newMeasurement(measurementType, target string, opts []MeasurementOption) model.PostMeasurement {
+	m := model.PostMeasurement{
+		Type:      measurementType,
+		Target:    target,
+		Limit:     1,
+		Locations: []model.Locations{{Magic: "world"}},
+		Options:   &model.MeasurementOptions{},
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	if *m.Options == (model.MeasurementOptions{}) {
+		m.Options = nil
+	}
+	return m
+}
+
+// Ping builds a ping measurement
+func Ping(target string, opts ...MeasurementOption) model.PostMeasurement {
+	return newMeasurement("ping", target, opts)
+}
+
+// Traceroute builds a traceroute measurement
+func Traceroute(target string, opts ...MeasurementOption) model.PostMeasurement {
+	return newMeasurement("traceroute", target, opts)
+}
+
+// Mtr builds an mtr measurement
+func Mtr(target string, opts ...MeasurementOption) model.PostMeasurement {
+	return newMeasurement("mtr", target, opts)
+}
+
+// Dns builds a dns measurement of the A record unless QueryType is given
+func Dns(target string, opts ...MeasurementOption) model.PostMeasurement {
+	return newMeasurement("dns", target, opts)
+}
+
+// Http builds an http measurement of a host, the request is configured with Method, Path, Query and Header
+func Http(host string, opts ...MeasurementOption) model.PostMeasurement {
+	return newMeasurement("http", host, opts)
+}
+
+// From sets the locations the probes are selected from, e.g. "Germany" or "AS3320"
+func From(locations ...string) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		m.Locations = make([]model.Locations, len(locations))
+		for i, l := range locations {
+			m.Locations[i] = model.Locations{Magic: strings.TrimSpace(l)}
+		}
+	}
+}
+
+// FromMeasurement runs the measurement from the same probes as a previous one
+func FromMeasurement(id string) MeasurementOption {
+	return From(id)
+}
+
+// Limit sets the number of probes
+func Limit(n int) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		m.Limit = n
+	}
+}
+
+// Packets sets the number of packets sent by ping and mtr, between 1 and 16
+func Packets(n int) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		m.Options.Packets = n
+	}
+}
+
+// Protocol sets the protocol of traceroute, mtr, dns or http, e.g. TCP or HTTPS
+func Protocol(p string) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		m.Options.Protocol = strings.ToUpper(p)
+	}
+}
+
+// Port sets the destination port
+func Port(port int) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		m.Options.Port = port
+	}
+}
+
+// IPVersion forces the address family a hostname target is resolved to, 4 or 6
+func IPVersion(v int) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		m.Options.IPVersion = v
+	}
+}
+
+// Resolver sets the resolver of dns and http measurements
+func Resolver(r string) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		m.Options.Resolver = r
+	}
+}
+
+// QueryType sets the record type of a dns measurement, e.g. AAAA
+func QueryType(t string) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		m.Options.Query = &model.QueryOptions{Type: strings.ToUpper(t)}
+	}
+}
+
+// Trace follows the delegation path of a dns measurement from the root servers
+func Trace() MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		m.Options.Trace = true
+	}
+}
+
+// Return the request options of an http measurement, created on first use
+func request(m *model.PostMeasurement) *model.RequestOptions {
+	if m.Options.Request == nil {
+		m.Options.Request = &model.RequestOptions{}
+	}
+	return m.Options.Request
+}
+
+// Method sets the method of an http measurement, GET or HEAD
+func Method(method string) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		request(m).Method = strings.ToUpper(method)
+	}
+}
+
+// Path sets the path of an http measurement
+func Path(path string) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		request(m).Path = path
+	}
+}
+
+// Query sets the query string of an http measurement
+func Query(query string) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		request(m).Query = query
+	}
+}
+
+// Header adds a request header to an http measurement
+func Header(name, value string) MeasurementOption {
+	return func(m *model.PostMeasurement) {
+		r := request(m)
+		if r.Headers == nil {
+			r.Headers = map[string]string{}
+		}
+		r.Headers[name] = value
+	}
+}
@@ -0,0 +1,70 @@
+package globalping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// PollInterval returns the delay between two polls of a measurement. Small measurements are polled every
+// 100 milliseconds to stream results as they arrive, larger or longer ones less often, up to every 2 seconds.
+func PollInterval(probesCount int, elapsed time.Duration) time.Duration {
+	interval := 100 * time.Millisecond
+	switch {
+	case probesCount > 50:
+		interval = 500 * time.Millisecond
+	case probesCount > 10:
+		interval = 250 * time.Millisecond
+	}
+
+	switch {
+	case elapsed > 30*time.Second:
+		interval *= 4
+	case elapsed > 5*time.Second:
+		interval *= 2
+	}
+
+	if interval > 2*time.Second {
+		interval = 2 * time.Second
+	}
+	return interval
+}
+
+// Wait polls a measurement until it is no longer in progress or the context is done. Every poll sends the ETag of
+// the previous response so the API does not send an unchanged measurement again. A zero interval adapts the delay
+// between polls to the measurement with PollInterval
+func (c *Client) Wait(ctx context.Context, id string, interval time.Duration) (model.GetMeasurement, error) {
+	start := time.Now()
+	var data model.GetMeasurement
+	etag := ""
+
+	for {
+		raw, tag, err := c.GetMeasurementJSON(ctx, id, etag)
+		if err != nil {
+			return data, err
+		}
+		if raw != nil {
+			data = model.GetMeasurement{}
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return data, fmt.Errorf("%w: %s", ErrInvalidResponse, err)
+			}
+			etag = tag
+		}
+		if data.Status != "in-progress" {
+			return data, nil
+		}
+
+		d := interval
+		if d <= 0 {
+			d = PollInterval(data.ProbesCount, time.Since(start))
+		}
+		select {
+		case <-ctx.Done():
+			return data, ctx.Err()
+		case <-time.After(d):
+		}
+	}
+}
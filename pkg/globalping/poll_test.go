@@ -0,0 +1,44 @@
+package globalping_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/pkg/globalping"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollInterval(t *testing.T) {
+	assert.Equal(t, 100*time.Millisecond, globalping.PollInterval(1, 0))
+	assert.Equal(t, 250*time.Millisecond, globalping.PollInterval(20, time.Second))
+	assert.Equal(t, 500*time.Millisecond, globalping.PollInterval(100, time.Second))
+	assert.Equal(t, 200*time.Millisecond, globalping.PollInterval(1, 10*time.Second))
+	assert.Equal(t, 2*time.Second, globalping.PollInterval(100, time.Minute))
+}
+
+func TestWaitNotModified(t *testing.T) {
+	var ifNoneMatch []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		switch len(ifNoneMatch) {
+		case 1:
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"id":"abcd","status":"in-progress","probesCount":1}`))
+		case 2:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Write([]byte(`{"id":"abcd","status":"finished","probesCount":1}`))
+		}
+	}))
+	defer server.Close()
+
+	c := globalping.NewClient(globalping.WithBaseURL(server.URL))
+	data, err := c.Wait(context.Background(), "abcd", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "finished", data.Status)
+	assert.Equal(t, []string{"", `"v1"`, `"v1"`}, ifNoneMatch)
+}
@@ -0,0 +1,83 @@
+package globalping
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures the retries of requests failing with a transient error: a network error, a 5xx status or
+// a 429 status with a Retry-After header
+type RetryPolicy struct {
+	// Count is the number of additional attempts, zero disables retries
+	Count int
+	// Delay before the first retry, doubled for every following one
+	Delay time.Duration
+	// Jitter randomizes every delay by up to this fraction of it, e.g. 0.2 for ±20%
+	Jitter float64
+}
+
+// Delay before the given retry, starting at 1
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.Delay) * math.Pow(2, float64(retry-1))
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(d)
+}
+
+// Parse the Retry-After header given in seconds
+func retryAfter(h http.Header) (time.Duration, bool) {
+	s, err := strconv.Atoi(h.Get("Retry-After"))
+	if err != nil || s < 0 {
+		return 0, false
+	}
+	return time.Duration(s) * time.Second, true
+}
+
+// send sends a request, retrying it according to the retry policy, the response of the last attempt is returned
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if attempt >= c.retry.Count {
+			return resp, err
+		}
+
+		var delay time.Duration
+		var reason string
+		switch {
+		case err != nil:
+			delay, reason = c.retry.backoff(attempt+1), err.Error()
+		case resp.StatusCode >= 500:
+			delay, reason = c.retry.backoff(attempt+1), "status "+strconv.Itoa(resp.StatusCode)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			d, ok := retryAfter(resp.Header)
+			if !ok {
+				return resp, nil
+			}
+			delay, reason = d, "rate limited"
+		default:
+			return resp, nil
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+		c.log("%s %s failed (%s), retrying in %s (attempt %d of %d)", req.Method, req.URL, reason, delay.Round(time.Millisecond), attempt+2, c.retry.Count+1)
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package runner runs measurements concurrently, posting each one and waiting for its results
+// with a bounded pool of workers.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Progress is the state of a run, reported whenever a measurement finishes
+type Progress struct {
+	Total    int
+	Finished int
+	Failed   int
+}
+
+// Runner posts measurements and waits for their results
+type Runner struct {
+	// Workers is the maximum number of measurements running at the same time
+	Workers int
+	// SameProbes runs every measurement from the probes of the first one, which is posted before the others
+	SameProbes bool
+	// Progress is called after every finished measurement if set, calls are never concurrent
+	Progress func(p Progress)
+}
+
+// New creates a runner of at most workers measurements at the same time
+func New(workers int) *Runner {
+	return &Runner{Workers: workers}
+}
+
+// Error is returned by Run when some measurements failed, the error of each one is in its result
+type Error struct {
+	Total  int
+	Failed []error
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Failed))
+	for i, err := range e.Failed {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d measurements failed: %s", len(e.Failed), e.Total, strings.Join(msgs, "; "))
+}
+
+// Run posts every measurement and waits for its results. Results are returned in the same order as the measurements,
+// along with an *Error if some of them failed. With SameProbes, an error posting the first measurement is returned
+// as is and no other measurement is posted.
+func (r *Runner) Run(c context.Context, measurements []model.PostMeasurement) ([]client.BatchResult, error) {
+	workers := r.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]client.BatchResult, len(measurements))
+	if len(measurements) == 0 {
+		return results, nil
+	}
+
+	var mu sync.Mutex
+	progress := Progress{Total: len(measurements)}
+	finish := func(i int, res client.BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = res
+		progress.Finished++
+		if res.Err != nil {
+			progress.Failed++
+		}
+		if r.Progress != nil {
+			r.Progress(progress)
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	start := 0
+
+	if r.SameProbes {
+		first, err := client.PostAPI(c, measurements[0])
+		if err != nil {
+			return nil, err
+		}
+		// Copy the measurements so the locations of the caller are left untouched
+		measurements = append([]model.PostMeasurement(nil), measurements...)
+		for i := range measurements[1:] {
+			measurements[i+1].Locations = []model.Locations{{Magic: first.ID}}
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			data, err := client.WaitForResults(c, first.ID)
+			finish(0, client.BatchResult{ID: first.ID, Data: data, Err: err})
+		}()
+		start = 1
+	}
+
+	for i := start; i < len(measurements); i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res, err := client.PostAPI(c, measurements[i])
+			if err != nil {
+				finish(i, client.BatchResult{Err: err})
+				return
+			}
+
+			data, err := client.WaitForResults(c, res.ID)
+			finish(i, client.BatchResult{ID: res.ID, Data: data, Err: err})
+		}(i)
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, res := range results {
+		if res.Err != nil {
+			failed = append(failed, res.Err)
+		}
+	}
+	if len(failed) > 0 {
+		return results, &Error{Total: len(results), Failed: failed}
+	}
+	return results, nil
+}
@@ -0,0 +1,149 @@
+package runner_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/runner"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+			return
+		}
+		w.Write([]byte(`{"id":"abcd","status":"finished","results":[]}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	var progress []runner.Progress
+	r := runner.New(2)
+	r.Progress = func(p runner.Progress) {
+		progress = append(progress, p)
+	}
+
+	results, err := r.Run(context.Background(), make([]model.PostMeasurement, 6))
+	assert.NoError(t, err)
+	assert.Len(t, results, 6)
+	for _, r := range results {
+		assert.NoError(t, r.Err)
+		assert.Equal(t, "abcd", r.ID)
+		assert.Equal(t, "finished", r.Data.Status)
+	}
+	assert.LessOrEqual(t, maxRunning, 2)
+	assert.Len(t, progress, 6)
+	assert.Equal(t, runner.Progress{Total: 6, Finished: 6}, progress[5])
+}
+
+func TestRunErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var m model.PostMeasurement
+			json.NewDecoder(r.Body).Decode(&m)
+			if m.Target == "fail" {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":{"message":"Internal Server Error","type":"api_error"}}`))
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+			return
+		}
+		w.Write([]byte(`{"id":"abcd","status":"finished","results":[]}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	measurements := []model.PostMeasurement{{Target: "ok"}, {Target: "fail"}, {Target: "ok"}}
+	results, err := runner.New(3).Run(context.Background(), measurements)
+	assert.EqualError(t, err, "1 of 3 measurements failed: err: internal server error - please try again later")
+	assert.NoError(t, results[0].Err)
+	assert.Error(t, results[1].Err)
+	assert.NoError(t, results[2].Err)
+
+	var runErr *runner.Error
+	assert.ErrorAs(t, err, &runErr)
+	assert.Equal(t, 3, runErr.Total)
+}
+
+func TestRunSameProbes(t *testing.T) {
+	var mu sync.Mutex
+	var locations []string
+	posted := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var m model.PostMeasurement
+			json.NewDecoder(r.Body).Decode(&m)
+			mu.Lock()
+			posted++
+			id := "id" + strconv.Itoa(posted)
+			locations = append(locations, m.Locations[0].Magic)
+			mu.Unlock()
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id":"` + id + `","probesCount":1}`))
+			return
+		}
+		w.Write([]byte(`{"status":"finished","results":[]}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	measurements := []model.PostMeasurement{
+		{Locations: []model.Locations{{Magic: "Europe"}}},
+		{Locations: []model.Locations{{Magic: "Europe"}}},
+		{Locations: []model.Locations{{Magic: "Europe"}}},
+	}
+	r := runner.New(2)
+	r.SameProbes = true
+	results, err := r.Run(context.Background(), measurements)
+	assert.NoError(t, err)
+	assert.Equal(t, "id1", results[0].ID)
+	assert.Equal(t, []string{"Europe", "id1", "id1"}, locations)
+	// The measurements of the caller are left untouched
+	assert.Equal(t, "Europe", measurements[1].Locations[0].Magic)
+}
+
+func TestRunSameProbesPostError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":{"message":"No suitable probes found","type":"no_probes_found"}}`))
+	}))
+	defer server.Close()
+	client.ApiUrl = server.URL
+
+	r := runner.New(2)
+	r.SameProbes = true
+	results, err := r.Run(context.Background(), make([]model.PostMeasurement, 2))
+	assert.Nil(t, results)
+	assert.True(t, client.IsUsageError(err))
+}
@@ -0,0 +1,214 @@
+// Package scheduler runs recurring measurements defined in a YAML file, records their results and calls a webhook
+// when a probe fails or a threshold is breached.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/manifest"
+)
+
+// MinInterval is the shortest interval between two runs of a job, to stay well within the rate limits of the API
+const MinInterval = 10 * time.Second
+
+// File is the schedule file, the webhook settings apply to every job that does not set its own
+//
+//	webhook: https://hooks.slack.com/services/...
+//	notify: slack
+//	measurements:
+//	  - name: cdn
+//	    type: http
+//	    target: cdn.jsdelivr.net
+//	    from: Europe, North America
+//	    limit: 10
+//	    interval: 5m
+//	    thresholds:
+//	      max-latency: 500ms
+//	      expect-status: 200
+type File struct {
+	Webhook      string `yaml:"webhook"`
+	Notify       string `yaml:"notify"`
+	Measurements []Job  `yaml:"measurements"`
+}
+
+// Job is a measurement run every Interval
+type Job struct {
+	manifest.Entry `yaml:",inline"`
+	Interval       time.Duration `yaml:"interval"`
+	// Webhook is called when a probe fails or a threshold is breached, Notify formats it for a chat service (slack)
+	Webhook string `yaml:"webhook"`
+	Notify  string `yaml:"notify"`
+	// Log is an NDJSON file the results of every run are appended to
+	Log string `yaml:"log"`
+}
+
+// Status is the outcome of the last run of a job
+type Status struct {
+	Name    string    `json:"name"`
+	LastRun time.Time `json:"lastRun"`
+	NextRun time.Time `json:"nextRun"`
+	ID      string    `json:"id,omitempty"`
+	Failed  bool      `json:"failed"`
+	// Violations are the breached thresholds and the failed probes
+	Violations []string `json:"violations,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// Scheduler runs the jobs of a schedule file
+type Scheduler struct {
+	Jobs []Job
+
+	mu     sync.Mutex
+	status map[string]*Status
+}
+
+// Load reads and validates a schedule file
+func Load(path string) (*Scheduler, error) {
+	var f File
+	err := manifest.Read(path, &f)
+	if err != nil {
+		return nil, err
+	}
+	return New(f)
+}
+
+// New validates the jobs of a schedule, filling in their defaults
+func New(f File) (*Scheduler, error) {
+	if len(f.Measurements) == 0 {
+		return nil, errors.New("err: the schedule has no measurements")
+	}
+
+	s := &Scheduler{status: map[string]*Status{}}
+	for i, job := range f.Measurements {
+		err := job.Validate(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		if job.Interval < MinInterval {
+			return nil, fmt.Errorf("err: measurement %d: the interval must be at least %s", i+1, MinInterval)
+		}
+		if _, ok := s.status[job.Name]; ok {
+			return nil, fmt.Errorf("err: measurement %d: duplicate name %q", i+1, job.Name)
+		}
+		if job.Webhook == "" {
+			job.Webhook = f.Webhook
+		}
+		if job.Notify == "" {
+			job.Notify = f.Notify
+		}
+		if job.Notify != "" && job.Notify != "slack" {
+			return nil, fmt.Errorf("err: measurement %d: unsupported notification format %q, supported formats are slack", i+1, job.Notify)
+		}
+
+		s.Jobs = append(s.Jobs, job)
+		s.status[job.Name] = &Status{Name: job.Name}
+	}
+	return s, nil
+}
+
+// Run runs every job right away then every interval until the context is done
+func (s *Scheduler) Run(c context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.Jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			for {
+				s.RunJob(c, job)
+				select {
+				case <-c.Done():
+					return
+				case <-time.After(job.Interval):
+				}
+			}
+		}(job)
+	}
+	wg.Wait()
+}
+
+// RunJob runs a job once: the measurement is recorded in the history and the log, then the webhook is called if
+// a probe failed or a threshold is breached
+func (s *Scheduler) RunJob(c context.Context, job Job) Status {
+	status := Status{Name: job.Name, LastRun: time.Now().UTC()}
+
+	err := s.run(c, job, &status)
+	if err != nil {
+		status.Failed = true
+		status.Error = err.Error()
+		client.Logf(client.LevelNormal, "%s: %s", job.Name, err)
+	}
+	// The interval starts once the run is finished
+	status.NextRun = time.Now().UTC().Add(job.Interval)
+
+	s.mu.Lock()
+	s.status[job.Name] = &status
+	s.mu.Unlock()
+	return status
+}
+
+func (s *Scheduler) run(c context.Context, job Job, status *Status) error {
+	ctx := job.Context()
+
+	res, err := client.PostAPI(c, job.PostMeasurement())
+	if err != nil {
+		return err
+	}
+	status.ID = res.ID
+
+	err = history.Add(history.Entry{ID: res.ID, Type: job.Type, Target: job.Target, From: job.From, CreatedAt: status.LastRun})
+	if err != nil {
+		client.Logf(client.LevelNormal, "%s: %s", job.Name, err)
+	}
+
+	data, err := client.WaitForResults(c, res.ID)
+	if err != nil {
+		return err
+	}
+
+	if job.Log != "" {
+		err = client.AppendNdjson(job.Log, data, ctx)
+		if err != nil {
+			client.Logf(client.LevelNormal, "%s: %s", job.Name, err)
+		}
+	}
+
+	violations := client.CheckThresholds(job.Type, data, ctx.Thresholds)
+	status.Failed = client.MeasurementFailed(data, violations, ctx.Thresholds.FailOnProbeErrors)
+	for _, v := range violations {
+		status.Violations = append(status.Violations, v.String())
+	}
+	client.Logf(client.LevelVerbose, "%s: measurement %s finished on %d probes, %d violations", job.Name, res.ID, len(data.Results), len(violations))
+
+	if status.Failed && job.Webhook != "" {
+		var payload interface{}
+		switch job.Notify {
+		case "slack":
+			payload = client.NewSlackMessage(data, ctx, violations)
+		default:
+			payload = client.NewWebhookPayload(data, ctx, violations, time.Now())
+		}
+		err = client.PostWebhook(job.Webhook, payload)
+		if err != nil {
+			client.Logf(client.LevelNormal, "%s: %s", job.Name, err)
+		}
+	}
+	return nil
+}
+
+// Statuses returns the outcome of the last run of every job, in the order of the schedule
+func (s *Scheduler) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Status, len(s.Jobs))
+	for i, job := range s.Jobs {
+		res[i] = *s.status[job.Name]
+	}
+	return res
+}
@@ -0,0 +1,217 @@
+package scheduler_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/manifest"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/scheduler"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(`webhook: https://example.com/hook
+measurements:
+  - name: cdn
+    type: http
+    target: cdn.jsdelivr.net
+    from: Europe, North America
+    limit: 10
+    interval: 5m
+    path: /npm/react
+    method: head
+    thresholds:
+      max-latency: 500ms
+      expect-status: 200
+  - type: ping
+    target: jsdelivr.com
+    from: Germany:2, Japan:3
+    interval: 1m
+    packets: 5
+    webhook: https://example.com/ping
+    thresholds:
+      max-loss: 5
+`), 0o644))
+
+	s, err := scheduler.Load(path)
+	assert.NoError(t, err)
+	assert.Len(t, s.Jobs, 2)
+
+	cdn := s.Jobs[0]
+	assert.Equal(t, "cdn", cdn.Name)
+	assert.Equal(t, 5*time.Minute, cdn.Interval)
+	assert.Equal(t, 500*time.Millisecond, cdn.Thresholds.MaxLatency)
+	assert.Equal(t, 200, cdn.Thresholds.ExpectStatus)
+	assert.Equal(t, "https://example.com/hook", cdn.Webhook)
+	assert.Equal(t, model.PostMeasurement{
+		Type:      "http",
+		Target:    "cdn.jsdelivr.net",
+		Limit:     10,
+		Locations: []model.Locations{{Magic: "Europe"}, {Magic: "North America"}},
+		Options:   &model.MeasurementOptions{Request: &model.RequestOptions{Path: "/npm/react", Method: "HEAD"}},
+	}, cdn.PostMeasurement())
+
+	ping := s.Jobs[1]
+	assert.Equal(t, "ping jsdelivr.com", ping.Name)
+	assert.Equal(t, "https://example.com/ping", ping.Webhook)
+	assert.Equal(t, 5.0, *ping.Thresholds.MaxLoss)
+	assert.Equal(t, model.PostMeasurement{
+		Type:      "ping",
+		Target:    "jsdelivr.com",
+		Limit:     5,
+		Locations: []model.Locations{{Magic: "Germany", Limit: 2}, {Magic: "Japan", Limit: 3}},
+		Options:   &model.MeasurementOptions{Packets: 5},
+	}, ping.PostMeasurement())
+
+	statuses := s.Statuses()
+	assert.Equal(t, []string{"cdn", "ping jsdelivr.com"}, []string{statuses[0].Name, statuses[1].Name})
+}
+
+func TestNewErrors(t *testing.T) {
+	job := func(mod func(j *scheduler.Job)) scheduler.File {
+		j := scheduler.Job{Entry: manifest.Entry{Type: "ping", Target: "jsdelivr.com"}, Interval: time.Minute}
+		mod(&j)
+		return scheduler.File{Measurements: []scheduler.Job{j}}
+	}
+
+	_, err := scheduler.New(scheduler.File{})
+	assert.EqualError(t, err, "err: the schedule has no measurements")
+	_, err = scheduler.New(job(func(j *scheduler.Job) { j.Type = "curl" }))
+	assert.EqualError(t, err, `err: measurement 1: unsupported type "curl"`)
+	_, err = scheduler.New(job(func(j *scheduler.Job) { j.Target = "" }))
+	assert.EqualError(t, err, "err: measurement 1: the target is required")
+	_, err = scheduler.New(job(func(j *scheduler.Job) { j.Interval = time.Second }))
+	assert.EqualError(t, err, "err: measurement 1: the interval must be at least 10s")
+	_, err = scheduler.New(job(func(j *scheduler.Job) { j.Notify = "teams" }))
+	assert.EqualError(t, err, `err: measurement 1: unsupported notification format "teams", supported formats are slack`)
+
+	f := job(func(j *scheduler.Job) {})
+	f.Measurements = append(f.Measurements, f.Measurements[0])
+	_, err = scheduler.New(f)
+	assert.EqualError(t, err, `err: measurement 2: duplicate name "ping jsdelivr.com"`)
+}
+
+func TestRunJob(t *testing.T) {
+	var webhooks []client.WebhookPayload
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p client.WebhookPayload
+		b, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(b, &p))
+		webhooks = append(webhooks, p)
+	}))
+	defer hook.Close()
+
+	avg := 20.0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+			return
+		}
+		data := model.GetMeasurement{ID: "abcd", Type: "ping", Status: "finished", Results: []model.MeasurementResponse{{
+			Probe:  model.ProbeData{City: "Berlin", Country: "DE", ASN: 3320},
+			Result: model.ResultData{Status: "finished", Stats: &model.PingStats{Avg: &avg}},
+		}}}
+		_ = json.NewEncoder(w).Encode(data)
+	}))
+	defer api.Close()
+
+	apiUrl, path := client.ApiUrl, history.Path
+	client.ApiUrl = api.URL
+	history.Path = filepath.Join(t.TempDir(), "history.json")
+	t.Cleanup(func() { client.ApiUrl, history.Path = apiUrl, path })
+
+	log := filepath.Join(t.TempDir(), "ping.ndjson")
+	s, err := scheduler.New(scheduler.File{Webhook: hook.URL, Measurements: []scheduler.Job{{
+		Entry: manifest.Entry{
+			Type:       "ping",
+			Target:     "jsdelivr.com",
+			Thresholds: manifest.Thresholds{MaxLatency: 50 * time.Millisecond},
+		},
+		Interval: time.Minute,
+		Log:      log,
+	}}})
+	assert.NoError(t, err)
+
+	// Within the thresholds, the webhook is not called
+	status := s.RunJob(context.Background(), s.Jobs[0])
+	assert.Equal(t, "abcd", status.ID)
+	assert.False(t, status.Failed)
+	assert.Empty(t, status.Error)
+	assert.Empty(t, webhooks)
+	assert.True(t, status.NextRun.After(status.LastRun))
+
+	// A breach calls the webhook
+	avg = 80
+	status = s.RunJob(context.Background(), s.Jobs[0])
+	assert.True(t, status.Failed)
+	assert.Equal(t, []string{"Berlin, DE, ASN:3320: latency 80.00 ms exceeds 50ms"}, status.Violations)
+	assert.Len(t, webhooks, 1)
+	assert.True(t, webhooks[0].Failed)
+	assert.Equal(t, "jsdelivr.com", webhooks[0].Target)
+	assert.Equal(t, status, s.Statuses()[0])
+
+	// Every run is recorded in the history and the log
+	entries, err := history.List(history.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	b, err := os.ReadFile(log)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(b)), "\n"), 2)
+}
+
+func TestRunJobError(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"error":{"type":"no_probes_found","message":"No suitable probes found."}}`))
+	}))
+	defer api.Close()
+
+	apiUrl := client.ApiUrl
+	client.ApiUrl = api.URL
+	t.Cleanup(func() { client.ApiUrl = apiUrl })
+
+	s, err := scheduler.New(scheduler.File{Measurements: []scheduler.Job{{Entry: manifest.Entry{Type: "ping", Target: "jsdelivr.com", From: "Atlantis"}, Interval: time.Minute}}})
+	assert.NoError(t, err)
+
+	status := s.RunJob(context.Background(), s.Jobs[0])
+	assert.True(t, status.Failed)
+	assert.Equal(t, "no suitable probes found - please choose a different location", status.Error)
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	s, err := scheduler.New(scheduler.File{Measurements: []scheduler.Job{{Entry: manifest.Entry{Type: "ping", Target: "jsdelivr.com"}, Interval: time.Minute}}})
+	assert.NoError(t, err)
+
+	// The jobs fail right away without an API and wait for the next run
+	apiUrl := client.ApiUrl
+	client.ApiUrl = "http://127.0.0.1:0"
+	t.Cleanup(func() { client.ApiUrl = apiUrl })
+
+	c, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(c)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return !s.Statuses()[0].LastRun.IsZero() }, time.Second, 10*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the scheduler did not stop")
+	}
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/manifest"
+	"github.com/jsdelivr/globalping-cli/model"
+)
+
+// Modules accepted by /probe, the module is the type of the measurement
+var probeModules = []string{"ping", "traceroute", "dns", "mtr", "http"}
+
+// Time left to write the metrics before the scrape timeout of Prometheus, like the default of the blackbox exporter
+const scrapeTimeoutOffset = 500 * time.Millisecond
+
+// Write a plain text error, the blackbox exporter answers invalid probe requests the same way
+func writeProbeError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	_, _ = w.Write([]byte(message + "\n"))
+}
+
+// probe runs a measurement on demand and returns its metrics, so the scrape configs of the Prometheus blackbox
+// exporter can use globalping by pointing them at /probe?target=...&module=ping&from=...
+//
+// A measurement which could not be run or did not finish before the scrape timeout is reported with probe_success 0,
+// like a failed probe of the blackbox exporter
+func (s *Server) probe(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+
+	q := r.URL.Query()
+	e := manifest.Entry{Type: q.Get("module"), Target: q.Get("target"), From: q.Get("from")}
+	if e.Target == "" {
+		writeProbeError(w, "target parameter is missing")
+		return
+	}
+	if e.Type == "" {
+		e.Type = "ping"
+	}
+	known := false
+	for _, m := range probeModules {
+		known = known || m == e.Type
+	}
+	if !known {
+		writeProbeError(w, fmt.Sprintf("unknown module %q, supported modules are %s", e.Type, strings.Join(probeModules, ", ")))
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			writeProbeError(w, "limit must be a positive number")
+			return
+		}
+		e.Limit = limit
+	}
+	if err := e.Validate(1); err != nil {
+		writeProbeError(w, strings.TrimPrefix(err.Error(), "err: "))
+		return
+	}
+
+	c := r.Context()
+	if v, err := strconv.ParseFloat(r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"), 64); err == nil && v > 0 {
+		timeout := time.Duration(v*float64(time.Second)) - scrapeTimeoutOffset
+		if timeout <= 0 {
+			timeout = time.Duration(v * float64(time.Second))
+		}
+		var cancel context.CancelFunc
+		c, cancel = context.WithTimeout(c, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	data, err := runProbe(c, e)
+	duration := time.Since(start)
+
+	var output strings.Builder
+	success := 0
+	if err != nil {
+		client.Logf(client.LevelNormal, "probe %s %s: %s", e.Type, e.Target, err)
+	} else if len(data.Results) > 0 && !client.MeasurementFailed(data, nil, client.FailPolicyAny) {
+		success = 1
+	}
+	fmt.Fprintf(&output, "# HELP probe_success Whether every probe finished the measurement successfully\n# TYPE probe_success gauge\nprobe_success %d\n", success)
+	fmt.Fprintf(&output, "# HELP probe_duration_seconds How long the measurement took to complete\n# TYPE probe_duration_seconds gauge\nprobe_duration_seconds %s\n", strconv.FormatFloat(duration.Seconds(), 'g', -1, 64))
+	if err == nil {
+		metrics, _ := client.FormatPrometheus(data, e.Context())
+		output.WriteString(metrics)
+	} else {
+		output.WriteString("# EOF")
+	}
+	output.WriteString("\n")
+
+	w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	_, _ = w.Write([]byte(output.String()))
+}
+
+// Create the measurement of a probe request, record it in the history and wait for its results
+func runProbe(c context.Context, e manifest.Entry) (model.GetMeasurement, error) {
+	res, err := client.PostAPI(c, e.PostMeasurement())
+	if err != nil {
+		return model.GetMeasurement{}, err
+	}
+
+	err = history.Add(history.Entry{ID: res.ID, Type: e.Type, Target: e.Target, From: e.From, CreatedAt: time.Now().UTC()})
+	if err != nil {
+		client.Logf(client.LevelNormal, "%s", err)
+	}
+
+	return client.WaitForResults(c, res.ID)
+}
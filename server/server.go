@@ -0,0 +1,265 @@
+// Package server exposes a local REST API proxying the Globalping API, so dashboards and scripts on a host can create
+// and fetch measurements with the credentials and the history of the CLI.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/model"
+	"github.com/jsdelivr/globalping-cli/scheduler"
+)
+
+// Server handles the requests of the local API:
+//
+//	GET  /health                  reports that the server is up
+//	POST /v1/measurements         creates a measurement and records it in the history
+//	GET  /v1/measurements/{id}    returns a measurement as returned by the API
+//	GET  /v1/history              lists the history, filtered with the type, target and last query parameters
+//	GET  /v1/probes               lists the online probes
+//	GET  /v1/schedule             returns the last run of every scheduled measurement, see Schedule
+//	GET  /probe                   runs a measurement and returns its metrics like the blackbox exporter, see probe
+type Server struct {
+	mux       *http.ServeMux
+	scheduler *scheduler.Scheduler
+}
+
+// New creates a server with every route registered
+func New() *Server {
+	s := &Server{mux: http.NewServeMux()}
+	s.mux.HandleFunc("/health", s.health)
+	s.mux.HandleFunc("/v1/measurements", s.createMeasurement)
+	s.mux.HandleFunc("/v1/measurements/", s.getMeasurement)
+	s.mux.HandleFunc("/v1/history", s.history)
+	s.mux.HandleFunc("/v1/probes", s.probes)
+	s.mux.HandleFunc("/v1/schedule", s.schedule)
+	s.mux.HandleFunc("/probe", s.probe)
+	return s
+}
+
+// Schedule exposes the status of the scheduled measurements, the scheduler is run separately
+func (s *Server) Schedule(sch *scheduler.Scheduler) {
+	s.scheduler = sch
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.mux.ServeHTTP(rec, r)
+	client.Logf(client.LevelVerbose, "%s %s %d %s", r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Millisecond))
+}
+
+// ListenAndServe serves the API on addr until the context is done, in-flight requests are then given a few seconds
+// to complete
+func (s *Server) ListenAndServe(c context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.New("err: failed to listen on " + addr + ": " + err.Error())
+	}
+	return s.Serve(c, ln)
+}
+
+// Serve serves the API on the listener until the context is done
+func (s *Server) Serve(c context.Context, ln net.Listener) error {
+	srv := &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-c.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	err := srv.Serve(ln)
+	if errors.Is(err, http.ErrServerClosed) {
+		<-done
+		return nil
+	}
+	return err
+}
+
+// Records the status of a response for the request log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func writeJson(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Write an error in the format of the Globalping API
+func writeError(w http.ResponseWriter, status int, errorType, message string) {
+	var data model.PostError
+	data.Error.Type = errorType
+	data.Error.Message = message
+	writeJson(w, status, data)
+}
+
+// Write the error of a call to the Globalping API, errors without a response are a bad gateway
+func writeApiError(w http.ResponseWriter, err error) {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		writeError(w, http.StatusBadGateway, "bad_gateway", strings.TrimPrefix(err.Error(), "err: "))
+		return
+	}
+
+	var data model.PostError
+	data.Error.Type = apiErr.Type
+	data.Error.Message = strings.TrimPrefix(apiErr.Message, "err: ")
+	if len(apiErr.Params) > 0 {
+		data.Error.Params = map[string]interface{}{}
+		for k, v := range apiErr.Params {
+			data.Error.Params[k] = v
+		}
+	}
+	writeJson(w, apiErr.StatusCode, data)
+}
+
+// Reject requests with another method than the allowed one
+func allow(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method+" is not allowed, use "+method)
+	return false
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	if allow(w, r, http.MethodGet) {
+		writeJson(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+func (s *Server) createMeasurement(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodPost) {
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, client.ErrorTypeValidation, "invalid measurement: "+err.Error())
+		return
+	}
+	// The body is forwarded as is, only the fields of the history entry are read
+	var m model.PostMeasurement
+	err = json.Unmarshal(body, &m)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, client.ErrorTypeValidation, "invalid measurement: "+err.Error())
+		return
+	}
+
+	res, err := client.PostRawAPI(r.Context(), body)
+	if err != nil {
+		writeApiError(w, err)
+		return
+	}
+
+	from := make([]string, len(m.Locations))
+	for i, l := range m.Locations {
+		from[i] = l.Magic
+	}
+	err = history.Add(history.Entry{
+		ID:        res.ID,
+		Type:      m.Type,
+		Target:    m.Target,
+		From:      strings.Join(from, ","),
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		client.Logf(client.LevelNormal, "%s", err)
+	}
+
+	w.Header().Set("Location", "/v1/measurements/"+res.ID)
+	writeJson(w, http.StatusAccepted, res)
+}
+
+func (s *Server) getMeasurement(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/v1/measurements/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, client.ErrorTypeNotFound, "measurement not found")
+		return
+	}
+
+	// The JSON of the API is returned as is, finished measurements are served from the cache
+	raw, err := client.GetApiJson(r.Context(), id)
+	if err != nil {
+		writeApiError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(raw))
+}
+
+func (s *Server) history(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+
+	q := r.URL.Query()
+	f := history.Filter{Type: q.Get("type"), Target: q.Get("target")}
+	if v := q.Get("last"); v != "" {
+		last, err := strconv.Atoi(v)
+		if err != nil || last < 0 {
+			writeError(w, http.StatusBadRequest, client.ErrorTypeValidation, "last must be a positive number")
+			return
+		}
+		f.Last = last
+	}
+
+	entries, err := history.List(f)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "history_error", strings.TrimPrefix(err.Error(), "err: "))
+		return
+	}
+	if entries == nil {
+		entries = []history.Entry{}
+	}
+	writeJson(w, http.StatusOK, entries)
+}
+
+func (s *Server) probes(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+
+	probes, err := client.GetProbes(r.Context())
+	if err != nil {
+		writeApiError(w, err)
+		return
+	}
+	writeJson(w, http.StatusOK, probes)
+}
+
+func (s *Server) schedule(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	if s.scheduler == nil {
+		writeJson(w, http.StatusOK, []scheduler.Status{})
+		return
+	}
+	writeJson(w, http.StatusOK, s.scheduler.Statuses())
+}
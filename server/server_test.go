@@ -0,0 +1,213 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsdelivr/globalping-cli/client"
+	"github.com/jsdelivr/globalping-cli/history"
+	"github.com/jsdelivr/globalping-cli/manifest"
+	"github.com/jsdelivr/globalping-cli/scheduler"
+	"github.com/jsdelivr/globalping-cli/server"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Start the local API in front of a fake Globalping API
+func setup(t *testing.T, upstream http.HandlerFunc) *httptest.Server {
+	api := httptest.NewServer(upstream)
+	t.Cleanup(api.Close)
+
+	apiUrl, probesUrl, path := client.ApiUrl, client.ProbesApiUrl, history.Path
+	client.ApiUrl = api.URL + "/v1/measurements"
+	client.ProbesApiUrl = api.URL + "/v1/probes"
+	history.Path = filepath.Join(t.TempDir(), "history.json")
+	t.Cleanup(func() {
+		client.ApiUrl, client.ProbesApiUrl, history.Path = apiUrl, probesUrl, path
+	})
+
+	local := httptest.NewServer(server.New())
+	t.Cleanup(local.Close)
+	return local
+}
+
+func request(t *testing.T, method, url, body string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp, strings.TrimSpace(string(b))
+}
+
+func TestCreateAndGetMeasurement(t *testing.T) {
+	var posted string
+	local := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/measurements":
+			b, _ := io.ReadAll(r.Body)
+			posted = string(b)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"id":"abcd","probesCount":1}`))
+		case r.URL.Path == "/v1/measurements/abcd":
+			_, _ = w.Write([]byte(`{"id":"abcd","type":"ping","status":"in-progress","results":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	// Fields the CLI does not know are forwarded too
+	measurement := `{"type":"ping","target":"jsdelivr.com","limit":1,"locations":[{"magic":"Germany","tags":["eyeball-network"]}],"measurementOptions":{"packets":2,"newOption":true}}`
+	resp, body := request(t, "POST", local.URL+"/v1/measurements", measurement)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "/v1/measurements/abcd", resp.Header.Get("Location"))
+	assert.Equal(t, `{"id":"abcd","probesCount":1}`, body)
+	assert.Equal(t, measurement, posted)
+
+	entries, err := history.List(history.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "abcd", entries[0].ID)
+	assert.Equal(t, "Germany", entries[0].From)
+
+	resp, body = request(t, "GET", local.URL+"/v1/measurements/abcd", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"id":"abcd","type":"ping","status":"in-progress","results":[]}`, body)
+
+	resp, body = request(t, "GET", local.URL+"/v1/history?type=ping&last=5", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"id":"abcd","type":"ping","target":"jsdelivr.com","from":"Germany"`)
+}
+
+func TestErrors(t *testing.T) {
+	local := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/measurements":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"error":{"type":"no_probes_found","message":"No suitable probes found."}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	resp, body := request(t, "POST", local.URL+"/v1/measurements", `{"type":"ping","target":"jsdelivr.com","limit":1,"locations":[{"magic":"Atlantis"}]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, `{"error":{"message":"no suitable probes found - please choose a different location","type":"no_probes_found"}}`, body)
+
+	resp, body = request(t, "POST", local.URL+"/v1/measurements", `{`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, body, `"type":"validation_error"`)
+
+	resp, body = request(t, "GET", local.URL+"/v1/measurements/missing", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":{"message":"measurement not found","type":"not_found"}}`, body)
+
+	resp, _ = request(t, "DELETE", local.URL+"/v1/measurements/abcd", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "GET", resp.Header.Get("Allow"))
+
+	resp, _ = request(t, "GET", local.URL+"/v1/history?last=x", "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, body = request(t, "GET", local.URL+"/health", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"status":"ok"}`, body)
+}
+
+func TestServeStopsWithContext(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	c, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- server.New().Serve(c, ln) }()
+
+	resp, body := request(t, "GET", "http://"+ln.Addr().String()+"/health", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"status":"ok"}`, body)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestSchedule(t *testing.T) {
+	srv := server.New()
+	local := httptest.NewServer(srv)
+	defer local.Close()
+
+	_, body := request(t, "GET", local.URL+"/v1/schedule", "")
+	assert.Equal(t, "[]", body)
+
+	sch, err := scheduler.New(scheduler.File{Measurements: []scheduler.Job{{Entry: manifest.Entry{Type: "ping", Target: "jsdelivr.com"}, Interval: time.Minute}}})
+	assert.NoError(t, err)
+	srv.Schedule(sch)
+
+	resp, body := request(t, "GET", local.URL+"/v1/schedule", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `[{"name":"ping jsdelivr.com","lastRun":"0001-01-01T00:00:00Z","nextRun":"0001-01-01T00:00:00Z","failed":false}]`, body)
+}
+
+func TestProbe(t *testing.T) {
+	var posted string
+	local := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/measurements":
+			b, _ := io.ReadAll(r.Body)
+			posted = string(b)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"id":"probe1","probesCount":1}`))
+		case r.URL.Path == "/v1/measurements/probe1":
+			_, _ = w.Write([]byte(`{"id":"probe1","type":"ping","status":"finished","results":[{"probe":{"continent":"EU","country":"DE","city":"Berlin","asn":3320,"network":"DTAG"},"result":{"status":"finished","stats":{"min":10,"avg":12,"max":14,"loss":0}}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	resp, body := request(t, "GET", local.URL+"/probe?target=jsdelivr.com&module=ping&from=Germany", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/openmetrics-text; version=1.0.0; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `{"limit":1,"locations":[{"magic":"Germany"}],"type":"ping","target":"jsdelivr.com"}`, posted)
+	assert.Contains(t, body, "# TYPE probe_success gauge\nprobe_success 1\n")
+	assert.Contains(t, body, "probe_duration_seconds ")
+	assert.Contains(t, body, `globalping_ping_rtt_avg_seconds{target="jsdelivr.com",continent="EU",country="DE",city="Berlin",asn="3320",network="DTAG"} 0.012`)
+	assert.True(t, strings.HasSuffix(body, "# EOF"))
+
+	entries, err := history.List(history.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "probe1", entries[0].ID)
+}
+
+func TestProbeErrors(t *testing.T) {
+	local := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"error":{"type":"no_probes_found","message":"No suitable probes found."}}`))
+	})
+
+	resp, body := request(t, "GET", local.URL+"/probe?module=ping", "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "target parameter is missing", body)
+
+	resp, body = request(t, "GET", local.URL+"/probe?target=jsdelivr.com&module=icmp", "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `unknown module "icmp", supported modules are ping, traceroute, dns, mtr, http`, body)
+
+	resp, _ = request(t, "GET", local.URL+"/probe?target=jsdelivr.com&limit=0", "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// A measurement which could not be created is a failed probe
+	resp, body = request(t, "GET", local.URL+"/probe?target=jsdelivr.com&from=Atlantis", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "probe_success 0\n")
+	assert.NotContains(t, body, "globalping_probe_success")
+	assert.True(t, strings.HasSuffix(body, "# EOF"))
+}